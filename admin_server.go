@@ -0,0 +1,189 @@
+package lit
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	adminUnauthorizedKey = "unauthorized"
+)
+
+var (
+	errInvalidAdminToken = HttpError{Status: http.StatusUnauthorized, Code: adminUnauthorizedKey, Desc: "Invalid admin token"}
+)
+
+// AdminServer serves the internal endpoints (profiling, health, readiness, metrics and route listings)
+// on a separate listener, so they are never exposed on the public port
+type AdminServer struct {
+	Server
+
+	cfg adminConfig
+
+	mu           sync.RWMutex
+	publicRoutes func() gin.RoutesInfo
+}
+
+// NewAdminServer creates new admin server listening at the given address
+//
+// Default routes:
+//   - GET /_/healthz: liveness probe, always unprotected
+//   - GET /_/readyz: readiness probe running all registered ReadinessCheck, always unprotected
+//   - /_/profile/*: pprof endpoints, unless AdminWithProfilingDisabled is given
+//   - GET /_/routes: routes of the public Handler configured with HandlerWithAdminServer
+//   - GET /_/metrics: only if AdminWithMetricsHandler is given
+//   - GET, PUT /_/log-level: only if AdminWithLogLevel is given
+func NewAdminServer(addr string, opts ...AdminOption) *AdminServer {
+	admin := &AdminServer{}
+	for _, opt := range opts {
+		opt(&admin.cfg)
+	}
+
+	admin.Server = NewHttpServer(addr, admin.handler(), admin.cfg.serverOpts...)
+
+	return admin
+}
+
+// handler builds the http.Handler of the admin server
+func (admin *AdminServer) handler() http.Handler {
+	r, hdl := NewRouter()
+
+	// Probes are registered before the protection middlewares,
+	// so that orchestrators can reach them without credentials
+	r.Handle(http.MethodGet, "/_/healthz", WrapF(LivenessHandlerFunc))
+	r.Handle(http.MethodGet, "/_/readyz", readinessHandler(admin.cfg.readinessChecks))
+
+	r.Use(admin.cfg.middlewares...)
+
+	if !admin.cfg.profilingDisabled {
+		registerProfilingRoutes(r)
+	}
+
+	if admin.cfg.metricsHandler != nil {
+		r.Handle(http.MethodGet, "/_/metrics", WrapH(admin.cfg.metricsHandler))
+	}
+
+	r.Handle(http.MethodGet, "/_/routes", admin.routesHandler)
+
+	for _, rt := range admin.cfg.routes {
+		r.Handle(rt.method, rt.path, rt.handler)
+	}
+
+	return hdl
+}
+
+// setPublicRoutes keeps the routes provider of the public Handler for route listings
+func (admin *AdminServer) setPublicRoutes(fn func() gin.RoutesInfo) {
+	admin.mu.Lock()
+	defer admin.mu.Unlock()
+
+	admin.publicRoutes = fn
+}
+
+type routeInfo struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+func (admin *AdminServer) routesHandler(c Context) {
+	admin.mu.RLock()
+	fn := admin.publicRoutes
+	admin.mu.RUnlock()
+
+	result := []routeInfo{}
+	if fn != nil {
+		for _, rt := range fn() {
+			result = append(result, routeInfo{Method: rt.Method, Path: rt.Path})
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ReadinessCheck reports whether a dependency is ready to serve traffic
+type ReadinessCheck func(ctx context.Context) error
+
+type namedReadinessCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+type readinessResult struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+func readinessHandler(checks []namedReadinessCheck) HandlerFunc {
+	return func(c Context) {
+		result := readinessResult{Status: "ok"}
+		for _, chk := range checks {
+			if err := chk.check(c.Request().Context()); err != nil {
+				if result.Checks == nil {
+					result.Checks = map[string]string{}
+				}
+				result.Status = "unavailable"
+				result.Checks[chk.name] = err.Error()
+			}
+		}
+
+		if len(result.Checks) > 0 {
+			c.JSON(http.StatusServiceUnavailable, result)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// staticTokenMiddleware only allows requests carrying the given bearer token
+func staticTokenMiddleware(token string) HandlerFunc {
+	return func(c Context) {
+		given, ok := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			c.AbortWithError(errInvalidAdminToken)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// registerProfilingRoutes registers pprof routes
+// Usage on pprof refer to : https: //pkg.go.dev/net/http/pprof
+func registerProfilingRoutes(r Router) {
+	const prefix = "/_/profile"
+	r.Handle(http.MethodGet, prefix+"/", WrapF(pprof.Index))
+	r.Handle(http.MethodGet, prefix+"/cmdline", WrapF(pprof.Cmdline))
+	r.Handle(http.MethodGet, prefix+"/profile", WrapF(pprof.Profile))
+	r.Handle(http.MethodPost, prefix+"/symbol", WrapF(pprof.Symbol))
+	r.Handle(http.MethodGet, prefix+"/symbol", WrapF(pprof.Symbol))
+	r.Handle(http.MethodGet, prefix+"/trace", WrapF(pprof.Trace))
+	r.Handle(http.MethodGet, prefix+"/allocs", WrapH(pprof.Handler("allocs")))
+	r.Handle(http.MethodGet, prefix+"/block", WrapH(pprof.Handler("block")))
+	r.Handle(http.MethodGet, prefix+"/goroutine", WrapH(pprof.Handler("goroutine")))
+	r.Handle(http.MethodGet, prefix+"/heap", WrapH(pprof.Handler("heap")))
+	r.Handle(http.MethodGet, prefix+"/mutex", WrapH(pprof.Handler("mutex")))
+	r.Handle(http.MethodGet, prefix+"/threadcreate", WrapH(pprof.Handler("threadcreate")))
+}
+
+// adminConfig is configurations of the AdminServer
+type adminConfig struct {
+	serverOpts        []ServerOption
+	middlewares       []func(Context)
+	profilingDisabled bool
+	metricsHandler    http.Handler
+	readinessChecks   []namedReadinessCheck
+	routes            []adminRoute
+}
+
+type adminRoute struct {
+	method  string
+	path    string
+	handler HandlerFunc
+}
@@ -0,0 +1,74 @@
+package lit
+
+import (
+	"net/http"
+
+	"github.com/viebiz/lit/monitoring"
+)
+
+// AdminOption is an optional config used to modify the AdminServer's behaviour
+type AdminOption func(*adminConfig)
+
+// AdminWithServerOptions overrides the admin server's default http server configurations
+func AdminWithServerOptions(opts ...ServerOption) AdminOption {
+	return func(c *adminConfig) {
+		c.serverOpts = append(c.serverOpts, opts...)
+	}
+}
+
+// AdminWithMiddleware protects all admin routes except the probes with the given middlewares,
+// e.g. guard.AuthGuard's AuthenticateM2MMiddleware and RequiredM2MScopeMiddleware
+func AdminWithMiddleware(middlewares ...HandlerFunc) AdminOption {
+	return func(c *adminConfig) {
+		for _, m := range middlewares {
+			c.middlewares = append(c.middlewares, m)
+		}
+	}
+}
+
+// AdminWithStaticToken protects all admin routes except the probes with a static bearer token
+func AdminWithStaticToken(token string) AdminOption {
+	return func(c *adminConfig) {
+		c.middlewares = append(c.middlewares, staticTokenMiddleware(token))
+	}
+}
+
+// AdminWithProfilingDisabled disables the AdminServer's default pprof routes
+func AdminWithProfilingDisabled() AdminOption {
+	return func(c *adminConfig) {
+		c.profilingDisabled = true
+	}
+}
+
+// AdminWithMetricsHandler serves the given metrics handler at /_/metrics
+func AdminWithMetricsHandler(hdl http.Handler) AdminOption {
+	return func(c *adminConfig) {
+		c.metricsHandler = hdl
+	}
+}
+
+// AdminWithLogLevel serves the given Monitor's log level controls at /_/log-level,
+// GET to get the current levels and PUT to change them, see monitoring.LevelRequest
+func AdminWithLogLevel(m *monitoring.Monitor) AdminOption {
+	return func(c *adminConfig) {
+		hdl := WrapH(m.LevelHandler())
+		c.routes = append(c.routes,
+			adminRoute{method: http.MethodGet, path: "/_/log-level", handler: hdl},
+			adminRoute{method: http.MethodPut, path: "/_/log-level", handler: hdl},
+		)
+	}
+}
+
+// AdminWithReadinessCheck adds a named dependency check to the /_/readyz probe
+func AdminWithReadinessCheck(name string, check ReadinessCheck) AdminOption {
+	return func(c *adminConfig) {
+		c.readinessChecks = append(c.readinessChecks, namedReadinessCheck{name: name, check: check})
+	}
+}
+
+// AdminWithRoute registers an additional internal route on the AdminServer
+func AdminWithRoute(method string, relativePath string, handler HandlerFunc) AdminOption {
+	return func(c *adminConfig) {
+		c.routes = append(c.routes, adminRoute{method: method, path: relativePath, handler: handler})
+	}
+}
@@ -0,0 +1,174 @@
+package lit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/viebiz/lit/monitoring"
+)
+
+func TestAdminServer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m, err := monitoring.New(monitoring.Config{Writer: io.Discard})
+	require.NoError(t, err)
+
+	type arg struct {
+		givenOpts []AdminOption
+		givenURL  string
+		givenAuth string
+		expStatus int
+		expBody   string
+	}
+	tcs := map[string]arg{
+		"liveness": {
+			givenURL:  "/_/healthz",
+			expStatus: http.StatusOK,
+			expBody:   "ok\n",
+		},
+		"readiness ok": {
+			givenOpts: []AdminOption{
+				AdminWithReadinessCheck("postgres", func(ctx context.Context) error { return nil }),
+			},
+			givenURL:  "/_/readyz",
+			expStatus: http.StatusOK,
+			expBody:   `{"status":"ok"}`,
+		},
+		"readiness failed": {
+			givenOpts: []AdminOption{
+				AdminWithReadinessCheck("postgres", func(ctx context.Context) error { return nil }),
+				AdminWithReadinessCheck("redis", func(ctx context.Context) error { return errors.New("connection refused") }),
+			},
+			givenURL:  "/_/readyz",
+			expStatus: http.StatusServiceUnavailable,
+			expBody:   `{"status":"unavailable","checks":{"redis":"connection refused"}}`,
+		},
+		"profiling": {
+			givenURL:  "/_/profile/allocs",
+			expStatus: http.StatusOK,
+		},
+		"profiling disabled": {
+			givenOpts: []AdminOption{AdminWithProfilingDisabled()},
+			givenURL:  "/_/profile/allocs",
+			expStatus: http.StatusNotFound,
+		},
+		"metrics": {
+			givenOpts: []AdminOption{
+				AdminWithMetricsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte("up 1"))
+				})),
+			},
+			givenURL:  "/_/metrics",
+			expStatus: http.StatusOK,
+			expBody:   "up 1",
+		},
+		"metrics not configured": {
+			givenURL:  "/_/metrics",
+			expStatus: http.StatusNotFound,
+		},
+		"log level": {
+			givenOpts: []AdminOption{AdminWithLogLevel(m)},
+			givenURL:  "/_/log-level",
+			expStatus: http.StatusOK,
+			expBody:   `{"level":"info"}` + "\n",
+		},
+		"custom route": {
+			givenOpts: []AdminOption{
+				AdminWithRoute(http.MethodGet, "/_/custom", func(c Context) {
+					c.JSON(http.StatusOK, map[string]string{"message": "custom"})
+				}),
+			},
+			givenURL:  "/_/custom",
+			expStatus: http.StatusOK,
+			expBody:   `{"message":"custom"}`,
+		},
+		"static token - valid": {
+			givenOpts: []AdminOption{AdminWithStaticToken("secret")},
+			givenURL:  "/_/routes",
+			givenAuth: "Bearer secret",
+			expStatus: http.StatusOK,
+			expBody:   `[{"method":"GET","path":"/test-route"}]`,
+		},
+		"static token - invalid": {
+			givenOpts: []AdminOption{AdminWithStaticToken("secret")},
+			givenURL:  "/_/profile/allocs",
+			givenAuth: "Bearer not-secret",
+			expStatus: http.StatusUnauthorized,
+			expBody:   `{"error":"unauthorized","error_description":"Invalid admin token"}`,
+		},
+		"static token - missing bearer scheme": {
+			givenOpts: []AdminOption{AdminWithStaticToken("secret")},
+			givenURL:  "/_/routes",
+			givenAuth: "secret",
+			expStatus: http.StatusUnauthorized,
+			expBody:   `{"error":"unauthorized","error_description":"Invalid admin token"}`,
+		},
+		"static token - probes are unprotected": {
+			givenOpts: []AdminOption{AdminWithStaticToken("secret")},
+			givenURL:  "/_/healthz",
+			expStatus: http.StatusOK,
+			expBody:   "ok\n",
+		},
+		"middleware": {
+			givenOpts: []AdminOption{
+				AdminWithMiddleware(func(c Context) {
+					c.AbortWithError(HttpError{Status: http.StatusForbidden, Code: "forbidden", Desc: "Permission denied"})
+				}),
+			},
+			givenURL:  "/_/routes",
+			expStatus: http.StatusForbidden,
+			expBody:   `{"error":"forbidden","error_description":"Permission denied"}`,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			admin := NewAdminServer(":0", tc.givenOpts...)
+			Handler(context.Background(), NewCORSConfig([]string{"*"}), mockRouter, HandlerWithAdminServer(admin))
+
+			server := httptest.NewServer(admin.httpServer.Handler)
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+tc.givenURL, nil)
+			require.NoError(t, err)
+			if tc.givenAuth != "" {
+				req.Header.Set("Authorization", tc.givenAuth)
+			}
+
+			// When
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			// Then
+			require.Equal(t, tc.expStatus, resp.StatusCode)
+			if tc.expBody != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				require.Equal(t, tc.expBody, string(body))
+			}
+		})
+	}
+}
+
+func TestRunWithAdminServer(t *testing.T) {
+	admin := NewAdminServer("127.0.0.1:0", AdminWithServerOptions(ServerShutdownGrace(time.Second)))
+	server := NewHttpServer("127.0.0.1:0", emptyHandler{}, ServerShutdownGrace(time.Second), ServerWithAdmin(admin))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond) // Ensure servers start
+		cancel()                           // Trigger shutdown
+	}()
+
+	require.NoError(t, server.RunWithContext(ctx))
+}
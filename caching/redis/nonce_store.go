@@ -0,0 +1,34 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const nonceKeyPrefix = "lit:nonce:"
+
+// NonceStore remembers the nonces for a while, e.g. to detect the replayed requests
+type NonceStore struct {
+	client Client
+}
+
+// NewNonceStore creates the NonceStore of the client
+func NewNonceStore(client Client) NonceStore {
+	return NonceStore{client: client}
+}
+
+// Remember stores the nonce for the ttl, returning false if it is already stored
+func (s NonceStore) Remember(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	err := s.client.SetStringIfNotExist(ctx, nonceKeyPrefix+nonce, "1", ttl)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, redis.Nil), errors.Is(err, ErrFailToSetValue): // Not set as the key exists
+		return false, nil
+	}
+
+	return false, err
+}
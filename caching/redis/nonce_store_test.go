@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceStore_Remember(t *testing.T) {
+	tcs := map[string]struct {
+		mockErr error
+		expOK   bool
+		expErr  error
+	}{
+		"new nonce": {
+			expOK: true,
+		},
+		"known nonce": {
+			mockErr: pkgerrors.WithStack(redis.Nil),
+		},
+		"known nonce - not set": {
+			mockErr: ErrFailToSetValue,
+		},
+		"error": {
+			mockErr: errors.New("connection refused"),
+			expErr:  errors.New("connection refused"),
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			client := NewMockClient(t)
+			client.EXPECT().
+				SetStringIfNotExist(ctx, "lit:nonce:orders:b3k2pp5k7z", "1", time.Minute).
+				Return(tc.mockErr)
+
+			// When
+			ok, err := NewNonceStore(client).Remember(ctx, "orders:b3k2pp5k7z", time.Minute)
+
+			// Then
+			if tc.expErr != nil {
+				require.EqualError(t, err, tc.expErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expOK, ok)
+		})
+	}
+}
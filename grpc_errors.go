@@ -0,0 +1,151 @@
+package lit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"github.com/viebiz/lit/grpcclient"
+	"github.com/viebiz/lit/monitoring"
+)
+
+// httpStatusToGRPCCode maps HTTP status codes of lit.Error to gRPC codes
+// following https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto
+var httpStatusToGRPCCode = map[int]codes.Code{
+	http.StatusBadRequest:                   codes.InvalidArgument,
+	http.StatusUnauthorized:                 codes.Unauthenticated,
+	http.StatusForbidden:                    codes.PermissionDenied,
+	http.StatusNotFound:                     codes.NotFound,
+	http.StatusConflict:                     codes.AlreadyExists,
+	http.StatusPreconditionFailed:           codes.FailedPrecondition,
+	http.StatusRequestedRangeNotSatisfiable: codes.OutOfRange,
+	http.StatusUnprocessableEntity:          codes.InvalidArgument,
+	http.StatusTooManyRequests:              codes.ResourceExhausted,
+	499:                                     codes.Canceled, // Client Closed Request
+	http.StatusInternalServerError:          codes.Internal,
+	http.StatusNotImplemented:               codes.Unimplemented,
+	http.StatusServiceUnavailable:           codes.Unavailable,
+	http.StatusGatewayTimeout:               codes.DeadlineExceeded,
+}
+
+// toGRPCError converts the error returned by gRPC handler to a gRPC status error
+//   - gRPC status errors are returned as is
+//   - lit.Error is mapped to the corresponding gRPC code, with google.rpc.ErrorInfo carrying the error code
+//     and google.rpc.BadRequest carrying the field violations of ValidationError
+//   - Internal details are hidden for 5xx errors except 503, same as Context.AbortWithError
+func toGRPCError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var litErr Error
+	if !errors.As(err, &litErr) || !isExposedStatus(litErr.StatusCode()) {
+		monitoring.FromContext(ctx).Errorf(err, "got unexpected error")
+		monitoring.NotifyErrorToInstrumentation(ctx, err)
+
+		return grpcDefaultInternalErr()
+	}
+
+	code := grpcCodeFromHTTPStatus(litErr.StatusCode())
+
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		return newGRPCStatusError(code, "Invalid request", "", validationErr)
+	}
+
+	var httpErr HttpError
+	if errors.As(err, &httpErr) {
+		return newGRPCStatusError(code, httpErr.Desc, httpErr.Code, nil)
+	}
+
+	return newGRPCStatusError(code, litErr.Error(), "", nil)
+}
+
+// grpcDefaultInternalErr returns ErrDefaultInternal as a gRPC status error
+func grpcDefaultInternalErr() error {
+	return newGRPCStatusError(codes.Internal, ErrDefaultInternal.Desc, ErrDefaultInternal.Code, nil)
+}
+
+// isExposedStatus returns true if the error details can be exposed to the client
+func isExposedStatus(statusCode int) bool {
+	return statusCode < http.StatusInternalServerError || statusCode == http.StatusServiceUnavailable
+}
+
+func grpcCodeFromHTTPStatus(statusCode int) codes.Code {
+	if code, ok := httpStatusToGRPCCode[statusCode]; ok {
+		return code
+	}
+
+	if statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError {
+		return codes.InvalidArgument
+	}
+
+	return codes.Unknown
+}
+
+func newGRPCStatusError(code codes.Code, msg string, reason string, violations ValidationError) error {
+	st := status.New(code, msg)
+
+	var details []protoadapt.MessageV1
+	if reason != "" {
+		details = append(details, &errdetails.ErrorInfo{Reason: reason})
+	}
+
+	if len(violations) > 0 {
+		// Sort fields to have a stable output
+		fields := make([]string, 0, len(violations))
+		for field := range violations {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		badReq := &errdetails.BadRequest{}
+		for _, field := range fields {
+			badReq.FieldViolations = append(badReq.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: violations[field],
+			})
+		}
+		details = append(details, badReq)
+	}
+
+	if len(details) == 0 {
+		return st.Err()
+	}
+
+	stWithDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err() // Should never happen as the details are valid proto messages
+	}
+
+	return stWithDetails.Err()
+}
+
+// fromGRPCError converts the gRPC status error to lit.Error, the reverse of toGRPCError
+//   - google.rpc.BadRequest is converted to ValidationError
+//   - Otherwise HttpError is returned, with the code taken from google.rpc.ErrorInfo or the snake case gRPC code name
+func fromGRPCError(err error) error {
+	err = grpcclient.ConvertError(err)
+
+	var validationErr grpcclient.ValidationError
+	if errors.As(err, &validationErr) {
+		return ValidationError(validationErr.Fields)
+	}
+
+	var statusErr grpcclient.StatusError
+	if errors.As(err, &statusErr) {
+		return HttpError{Status: statusErr.Status, Code: statusErr.Code, Desc: statusErr.Desc}
+	}
+
+	return err
+}
@@ -0,0 +1,100 @@
+package lit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func Test_toGRPCError(t *testing.T) {
+	type args struct {
+		givenErr   error
+		expCode    codes.Code
+		expMsg     string
+		expDetails []proto.Message
+	}
+	tcs := map[string]args{
+		"nil": {
+			expCode: codes.OK,
+		},
+		"gRPC status error": {
+			givenErr: status.Error(codes.NotFound, "weather not found"),
+			expCode:  codes.NotFound,
+			expMsg:   "weather not found",
+		},
+		"HttpError": {
+			givenErr:   HttpError{Status: http.StatusNotFound, Code: "weather_not_found", Desc: "Weather not found"},
+			expCode:    codes.NotFound,
+			expMsg:     "Weather not found",
+			expDetails: []proto.Message{&errdetails.ErrorInfo{Reason: "weather_not_found"}},
+		},
+		"wrapped HttpError": {
+			givenErr:   errors.Join(errors.New("wrapped"), HttpError{Status: http.StatusUnauthorized, Code: "unauthorized", Desc: "Access token is required"}),
+			expCode:    codes.Unauthenticated,
+			expMsg:     "Access token is required",
+			expDetails: []proto.Message{&errdetails.ErrorInfo{Reason: "unauthorized"}},
+		},
+		"HttpError - unknown 4xx": {
+			givenErr:   HttpError{Status: http.StatusTeapot, Code: "teapot", Desc: "I'm a teapot"},
+			expCode:    codes.InvalidArgument,
+			expMsg:     "I'm a teapot",
+			expDetails: []proto.Message{&errdetails.ErrorInfo{Reason: "teapot"}},
+		},
+		"HttpError - 503 is exposed": {
+			givenErr:   HttpError{Status: http.StatusServiceUnavailable, Code: "maintenance", Desc: "Under maintenance"},
+			expCode:    codes.Unavailable,
+			expMsg:     "Under maintenance",
+			expDetails: []proto.Message{&errdetails.ErrorInfo{Reason: "maintenance"}},
+		},
+		"HttpError - 5xx is hidden": {
+			givenErr:   HttpError{Status: http.StatusBadGateway, Code: "bad_gateway", Desc: "Upstream secret"},
+			expCode:    codes.Internal,
+			expMsg:     "Something went wrong",
+			expDetails: []proto.Message{&errdetails.ErrorInfo{Reason: "internal_server_error"}},
+		},
+		"ValidationError": {
+			givenErr: ValidationError{"date": "date is required", "location": "location is invalid"},
+			expCode:  codes.InvalidArgument,
+			expMsg:   "Invalid request",
+			expDetails: []proto.Message{&errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{
+					{Field: "date", Description: "date is required"},
+					{Field: "location", Description: "location is invalid"},
+				},
+			}},
+		},
+		"unexpected error": {
+			givenErr:   errors.New("pq: connection refused"),
+			expCode:    codes.Internal,
+			expMsg:     "Something went wrong",
+			expDetails: []proto.Message{&errdetails.ErrorInfo{Reason: "internal_server_error"}},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			err := toGRPCError(context.Background(), tc.givenErr)
+
+			// Then
+			st := status.Convert(err)
+			require.Equal(t, tc.expCode, st.Code())
+			require.Equal(t, tc.expMsg, st.Message())
+
+			details := st.Details()
+			require.Len(t, details, len(tc.expDetails))
+			for idx, d := range details {
+				require.True(t, proto.Equal(tc.expDetails[idx], d.(proto.Message)), "detail %d: %v", idx, d)
+			}
+		})
+	}
+}
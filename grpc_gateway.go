@@ -0,0 +1,420 @@
+package lit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/viebiz/lit/monitoring"
+)
+
+const (
+	// gatewayMetadataHeaderPrefix is the prefix of the response headers carrying the gRPC header and trailer metadata
+	gatewayMetadataHeaderPrefix = "Grpc-Metadata-"
+)
+
+// GRPCGateway transcodes HTTP/JSON requests to the gRPC services registered on it, following google.api.http annotations.
+// The calls are handled in-process without network hop, so the middlewares of the router (guard, rate limits, i18n) still apply.
+//
+// Usage:
+//
+//	gw := lit.NewGRPCGateway()
+//	pb.RegisterWeatherServiceServer(lit.MultiRegistrar(grpcSrv.Registrar(), gw), impl)
+//	if err := gw.Mount(router); err != nil { ... }
+type GRPCGateway struct {
+	cfg      gatewayConfig
+	services []gatewayService
+}
+
+type gatewayService struct {
+	desc *grpc.ServiceDesc
+	impl any
+	sd   protoreflect.ServiceDescriptor // Resolved from protoregistry.GlobalFiles when mounting if nil
+}
+
+// NewGRPCGateway creates a new GRPCGateway
+func NewGRPCGateway(opts ...GatewayOption) *GRPCGateway {
+	cfg := gatewayConfig{
+		rules:       map[string]*annotations.HttpRule{},
+		marshalOpts: protojson.MarshalOptions{UseProtoNames: true},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &GRPCGateway{cfg: cfg}
+}
+
+// RegisterService implements ServiceRegistrar
+// The proto descriptor of the service is resolved from the global registry, which is populated by the generated code
+func (gw *GRPCGateway) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	gw.services = append(gw.services, gatewayService{desc: desc, impl: impl})
+}
+
+// RegisterServiceWithDescriptor registers the service with the given proto descriptor, e.g. built from a descriptor set
+func (gw *GRPCGateway) RegisterServiceWithDescriptor(desc *grpc.ServiceDesc, impl any, sd protoreflect.ServiceDescriptor) {
+	gw.services = append(gw.services, gatewayService{desc: desc, impl: impl, sd: sd})
+}
+
+// Mount adds a route to the router for each HTTP rule of the registered unary methods
+// Streaming methods and methods without HTTP rule are skipped
+func (gw *GRPCGateway) Mount(r Router) error {
+	for _, svc := range gw.services {
+		if err := gw.mountService(r, svc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (gw *GRPCGateway) mountService(r Router, svc gatewayService) error {
+	if svc.desc.HandlerType != nil {
+		ht := reflect.TypeOf(svc.desc.HandlerType).Elem()
+		if st := reflect.TypeOf(svc.impl); st == nil || !st.Implements(ht) {
+			return pkgerrors.Errorf("grpc gateway: %T does not implement %s", svc.impl, ht)
+		}
+	}
+
+	sd := svc.sd
+	if sd == nil {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(svc.desc.ServiceName))
+		if err != nil {
+			return pkgerrors.Wrapf(err, "grpc gateway: find descriptor of %s", svc.desc.ServiceName)
+		}
+
+		var ok bool
+		if sd, ok = d.(protoreflect.ServiceDescriptor); !ok {
+			return pkgerrors.Errorf("grpc gateway: %s is not a service", svc.desc.ServiceName)
+		}
+	}
+
+	for _, md := range svc.desc.Methods {
+		fullMethod := "/" + svc.desc.ServiceName + "/" + md.MethodName
+
+		methodDesc := sd.Methods().ByName(protoreflect.Name(md.MethodName))
+		if methodDesc == nil {
+			return pkgerrors.Errorf("grpc gateway: method %s not found in descriptor of %s", md.MethodName, sd.FullName())
+		}
+
+		rule := gw.cfg.rules[fullMethod]
+		if rule == nil {
+			rule = httpRuleOf(methodDesc)
+		}
+		if rule == nil {
+			continue
+		}
+
+		for _, binding := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+			rt, err := newGatewayRoute(methodDesc, binding)
+			if err != nil {
+				return pkgerrors.Wrapf(err, "grpc gateway: %s", fullMethod)
+			}
+
+			r.Handle(rt.method, rt.path, gw.handler(svc.impl, md, fullMethod, rt))
+		}
+	}
+
+	return nil
+}
+
+// handler returns the HandlerFunc invoking the gRPC method handler in-process
+func (gw *GRPCGateway) handler(impl any, md grpc.MethodDesc, fullMethod string, rt gatewayRoute) HandlerFunc {
+	interceptor := gw.cfg.interceptor()
+
+	return func(c Context) {
+		req := c.Request()
+		stream := &gatewayTransportStream{method: fullMethod}
+
+		ctx := grpc.NewContextWithServerTransportStream(req.Context(), stream)
+		ctx = metadata.NewIncomingContext(ctx, incomingMetadata(req))
+
+		dec := func(v any) error {
+			return gw.decodeRequest(c, rt, v)
+		}
+
+		resp, err := md.Handler(impl, ctx, dec, interceptor)
+
+		stream.writeHeaders(c.Writer().Header())
+
+		// Translate lit.Error to gRPC status error first, so that HTTP clients get the same error as gRPC clients
+		if err = toGRPCError(ctx, err); err != nil {
+			c.AbortWithError(fromGRPCError(err))
+			return
+		}
+
+		body, err := gw.encodeResponse(resp, rt.responseField)
+		if err != nil {
+			monitoring.FromContext(ctx).Errorf(err, "[GRPCGateway] Encode response failed")
+			c.AbortWithError(ErrDefaultInternal)
+			return
+		}
+
+		c.Header("Content-Type", "application/json")
+		c.Status(http.StatusOK)
+		if _, writeErr := c.Writer().Write(body); writeErr != nil {
+			monitoring.FromContext(ctx).Errorf(writeErr, "[GRPCGateway] Write failed")
+		}
+	}
+}
+
+func (gw *GRPCGateway) encodeResponse(resp any, field protoreflect.FieldDescriptor) ([]byte, error) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, pkgerrors.Errorf("unexpected response type %T", resp)
+	}
+
+	if field == nil {
+		return gw.cfg.marshalOpts.Marshal(msg)
+	}
+
+	if field.Kind() == protoreflect.MessageKind && field.Cardinality() != protoreflect.Repeated {
+		return gw.cfg.marshalOpts.Marshal(msg.ProtoReflect().Get(field).Message().Interface())
+	}
+
+	// protojson cannot marshal a non-message field alone, so extract it from the whole response
+	b, err := gw.cfg.marshalOpts.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, pkgerrors.WithStack(err)
+	}
+
+	name := field.JSONName()
+	if gw.cfg.marshalOpts.UseProtoNames {
+		name = string(field.Name())
+	}
+
+	if v, ok := fields[name]; ok {
+		return v, nil
+	}
+
+	return []byte("null"), nil
+}
+
+// httpRuleOf returns the google.api.http annotation of the method, nil if there is none
+func httpRuleOf(md protoreflect.MethodDescriptor) *annotations.HttpRule {
+	opts, ok := md.Options().(*descriptorpb.MethodOptions)
+	if !ok || opts == nil || !proto.HasExtension(opts, annotations.E_Http) {
+		return nil
+	}
+
+	rule, _ := proto.GetExtension(opts, annotations.E_Http).(*annotations.HttpRule)
+	return rule
+}
+
+// incomingMetadata converts the request headers to incoming gRPC metadata, e.g. authorization
+func incomingMetadata(r *http.Request) metadata.MD {
+	md := make(metadata.MD, len(r.Header))
+	for k, vs := range r.Header {
+		md.Append(k, vs...)
+	}
+
+	return md
+}
+
+// gatewayTransportStream collects the header and trailer metadata set by the gRPC handler with grpc.SetHeader and grpc.SetTrailer
+type gatewayTransportStream struct {
+	method string
+
+	mu      sync.Mutex
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *gatewayTransportStream) Method() string {
+	return s.method
+}
+
+func (s *gatewayTransportStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *gatewayTransportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *gatewayTransportStream) SetTrailer(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+// writeHeaders writes the collected metadata as response headers prefixed by Grpc-Metadata-
+func (s *gatewayTransportStream) writeHeaders(h http.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, md := range []metadata.MD{s.header, s.trailer} {
+		for k, vs := range md {
+			for _, v := range vs {
+				h.Add(gatewayMetadataHeaderPrefix+k, v)
+			}
+		}
+	}
+}
+
+// gatewayRoute is a route of the gRPC method built from an HTTP rule
+type gatewayRoute struct {
+	method        string
+	path          string // In router syntax, e.g. /v1/weather/:p0
+	params        []gatewayPathParam
+	body          string // Empty for no body, * for the whole request message, or the request field name
+	responseField protoreflect.FieldDescriptor
+}
+
+type gatewayPathParam struct {
+	name     string // Name of the router param
+	field    string // Dotted path of the request field, empty for unnamed wildcards
+	catchAll bool
+}
+
+func newGatewayRoute(md protoreflect.MethodDescriptor, rule *annotations.HttpRule) (gatewayRoute, error) {
+	var method, tmpl string
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		method, tmpl = http.MethodGet, p.Get
+	case *annotations.HttpRule_Put:
+		method, tmpl = http.MethodPut, p.Put
+	case *annotations.HttpRule_Post:
+		method, tmpl = http.MethodPost, p.Post
+	case *annotations.HttpRule_Delete:
+		method, tmpl = http.MethodDelete, p.Delete
+	case *annotations.HttpRule_Patch:
+		method, tmpl = http.MethodPatch, p.Patch
+	case *annotations.HttpRule_Custom:
+		method, tmpl = p.Custom.GetKind(), p.Custom.GetPath()
+	default:
+		return gatewayRoute{}, pkgerrors.New("http rule has no pattern")
+	}
+
+	path, params, err := parsePathTemplate(tmpl)
+	if err != nil {
+		return gatewayRoute{}, err
+	}
+
+	rt := gatewayRoute{
+		method: method,
+		path:   path,
+		params: params,
+		body:   rule.GetBody(),
+	}
+
+	for _, p := range params {
+		if p.field == "" {
+			continue
+		}
+		if _, err := resolveFieldPath(md.Input(), p.field); err != nil {
+			return gatewayRoute{}, pkgerrors.Wrapf(err, "path %s", tmpl)
+		}
+	}
+
+	if rt.body != "" && rt.body != "*" {
+		if md.Input().Fields().ByName(protoreflect.Name(rt.body)) == nil {
+			return gatewayRoute{}, pkgerrors.Errorf("body field %s not found in %s", rt.body, md.Input().FullName())
+		}
+	}
+
+	if name := rule.GetResponseBody(); name != "" {
+		if rt.responseField = md.Output().Fields().ByName(protoreflect.Name(name)); rt.responseField == nil {
+			return gatewayRoute{}, pkgerrors.Errorf("response body field %s not found in %s", name, md.Output().FullName())
+		}
+	}
+
+	return rt, nil
+}
+
+// parsePathTemplate converts the path template of the HTTP rule to router syntax
+// Supported segments are literals, *, ** and variables {field}, {field=*}, {field=**}, ** is only allowed as the last segment
+func parsePathTemplate(tmpl string) (string, []gatewayPathParam, error) {
+	if !strings.HasPrefix(tmpl, "/") {
+		return "", nil, pkgerrors.Errorf("path %s must start with /", tmpl)
+	}
+
+	var (
+		b        strings.Builder
+		params   []gatewayPathParam
+		segments = strings.Split(tmpl[1:], "/")
+	)
+	for idx, seg := range segments {
+		b.WriteByte('/')
+
+		var field, pattern string
+		switch {
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+			field, pattern, _ = strings.Cut(seg[1:len(seg)-1], "=")
+			if field == "" {
+				return "", nil, pkgerrors.Errorf("path %s has an empty variable", tmpl)
+			}
+		case seg == "*" || seg == "**":
+			pattern = seg
+		case strings.ContainsAny(seg, "{}*:"):
+			return "", nil, pkgerrors.Errorf("path %s: unsupported segment %s", tmpl, seg)
+		default:
+			b.WriteString(seg)
+			continue
+		}
+
+		p := gatewayPathParam{name: fmt.Sprintf("p%d", len(params)), field: field}
+		switch pattern {
+		case "", "*":
+			b.WriteString(":" + p.name)
+		case "**":
+			if idx != len(segments)-1 {
+				return "", nil, pkgerrors.Errorf("path %s: ** must be the last segment", tmpl)
+			}
+			p.catchAll = true
+			b.WriteString("*" + p.name)
+		default:
+			return "", nil, pkgerrors.Errorf("path %s: unsupported variable pattern %s", tmpl, pattern)
+		}
+		params = append(params, p)
+	}
+
+	return b.String(), params, nil
+}
+
+// isBound returns true if the query parameter refers to a field bound by the path or the body
+func (rt gatewayRoute) isBound(key string) bool {
+	if rt.body == "*" {
+		return true
+	}
+
+	bound := make([]string, 0, len(rt.params)+1)
+	for _, p := range rt.params {
+		bound = append(bound, p.field)
+	}
+	if rt.body != "" {
+		bound = append(bound, rt.body)
+	}
+
+	for _, field := range bound {
+		if field != "" && (key == field || strings.HasPrefix(key, field+".")) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,70 @@
+package lit
+
+import (
+	"context"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// GatewayOption is an optional config used to modify the GRPCGateway's behaviour
+type GatewayOption func(*gatewayConfig)
+
+// GatewayWithUnaryInterceptors adds interceptors wrapping the in-process gRPC calls, e.g. the auth interceptor
+// They are chained in order, same as grpc.ChainUnaryInterceptor
+func GatewayWithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) GatewayOption {
+	return func(cfg *gatewayConfig) {
+		cfg.interceptors = append(cfg.interceptors, interceptors...)
+	}
+}
+
+// GatewayWithHTTPRule sets the HTTP rule of the given full method, e.g. /weather.WeatherService/GetWeatherInfo
+// It takes precedence over the google.api.http annotation, useful for services without annotations
+func GatewayWithHTTPRule(fullMethod string, rule *annotations.HttpRule) GatewayOption {
+	return func(cfg *gatewayConfig) {
+		cfg.rules[fullMethod] = rule
+	}
+}
+
+// GatewayWithMarshalOptions overrides the protojson options used to write the response body
+// By default, proto field names are used to be consistent with the error response
+func GatewayWithMarshalOptions(opts protojson.MarshalOptions) GatewayOption {
+	return func(cfg *gatewayConfig) {
+		cfg.marshalOpts = opts
+	}
+}
+
+// GatewayWithUnmarshalOptions overrides the protojson options used to read the request body
+func GatewayWithUnmarshalOptions(opts protojson.UnmarshalOptions) GatewayOption {
+	return func(cfg *gatewayConfig) {
+		cfg.unmarshalOpts = opts
+	}
+}
+
+// gatewayConfig is configurations of the GRPCGateway
+type gatewayConfig struct {
+	interceptors  []grpc.UnaryServerInterceptor
+	rules         map[string]*annotations.HttpRule
+	marshalOpts   protojson.MarshalOptions
+	unmarshalOpts protojson.UnmarshalOptions
+}
+
+// interceptor returns the configured interceptors chained in order, nil if there is none
+func (cfg gatewayConfig) interceptor() grpc.UnaryServerInterceptor {
+	if len(cfg.interceptors) == 0 {
+		return nil
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		next := handler
+		for i := len(cfg.interceptors) - 1; i > 0; i-- {
+			interceptor, innerNext := cfg.interceptors[i], next
+			next = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, innerNext)
+			}
+		}
+
+		return cfg.interceptors[0](ctx, req, info, next)
+	}
+}
@@ -0,0 +1,201 @@
+package lit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strconv"
+	"strings"
+
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// decodeRequest fills the request message of the gRPC method from the HTTP request
+// The body is decoded first, then the query parameters, then the path parameters, so the path always wins
+func (gw *GRPCGateway) decodeRequest(c Context, rt gatewayRoute, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected request type %T", v)
+	}
+
+	req := c.Request()
+	if rt.body != "" && req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "read request body: %v", err)
+		}
+
+		if b = bytes.TrimSpace(b); len(b) > 0 {
+			if rt.body != "*" {
+				// Wrap the body, so that the field is decoded by protojson whatever its kind is
+				fd := msg.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(rt.body))
+				b = append(append([]byte(`{"`+fd.JSONName()+`":`), b...), '}')
+			}
+
+			if err := gw.cfg.unmarshalOpts.Unmarshal(b, msg); err != nil {
+				return status.Errorf(codes.InvalidArgument, "invalid request body: %v", err)
+			}
+		}
+	}
+
+	m := msg.ProtoReflect()
+	for key, values := range req.URL.Query() {
+		if rt.isBound(key) {
+			continue
+		}
+
+		fds, err := resolveFieldPath(m.Descriptor(), key)
+		if err != nil {
+			continue // Ignore unknown query parameters, e.g. cache busters
+		}
+
+		if err := setFieldValues(m, fds, values); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid query parameter %s: %v", key, err)
+		}
+	}
+
+	paramGetter, _ := c.(interface{ Param(string) string })
+	for _, p := range rt.params {
+		if p.field == "" || paramGetter == nil {
+			continue
+		}
+
+		value := paramGetter.Param(p.name)
+		if p.catchAll {
+			value = strings.TrimPrefix(value, "/")
+		}
+
+		fds, err := resolveFieldPath(m.Descriptor(), p.field)
+		if err != nil {
+			return status.Errorf(codes.Internal, "resolve path parameter %s: %v", p.field, err) // Should never happen as it is validated when mounting
+		}
+
+		if err := setFieldValues(m, fds, []string{value}); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid path parameter %s: %v", p.field, err)
+		}
+	}
+
+	return nil
+}
+
+// resolveFieldPath returns the fields of the dotted path, e.g. location.city, by proto or JSON name
+func resolveFieldPath(md protoreflect.MessageDescriptor, path string) ([]protoreflect.FieldDescriptor, error) {
+	names := strings.Split(path, ".")
+	fds := make([]protoreflect.FieldDescriptor, 0, len(names))
+	for idx, name := range names {
+		fd := md.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(name)
+		}
+		if fd == nil {
+			return nil, pkgerrors.Errorf("field %s not found in %s", name, md.FullName())
+		}
+
+		if fd.IsMap() {
+			return nil, pkgerrors.Errorf("map field %s is not supported", name)
+		}
+
+		if idx < len(names)-1 {
+			if fd.Kind() != protoreflect.MessageKind || fd.IsList() {
+				return nil, pkgerrors.Errorf("field %s is not a singular message", name)
+			}
+			md = fd.Message()
+		}
+
+		fds = append(fds, fd)
+	}
+
+	return fds, nil
+}
+
+// setFieldValues sets the leaf field of the path, values are appended to repeated fields
+func setFieldValues(m protoreflect.Message, fds []protoreflect.FieldDescriptor, values []string) error {
+	for _, fd := range fds[:len(fds)-1] {
+		m = m.Mutable(fd).Message()
+	}
+
+	leaf := fds[len(fds)-1]
+	if leaf.IsList() {
+		list := m.Mutable(leaf).List()
+		for _, s := range values {
+			v, err := parseFieldValue(leaf, s, list.NewElement())
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+		}
+
+		return nil
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	v, err := parseFieldValue(leaf, values[0], m.NewField(leaf))
+	if err != nil {
+		return err
+	}
+	m.Set(leaf, v)
+
+	return nil
+}
+
+// parseFieldValue parses the string as the value of the field
+// Message fields, e.g. google.protobuf.Timestamp, are decoded from their JSON string representation into newValue
+func parseFieldValue(fd protoreflect.FieldDescriptor, s string, newValue protoreflect.Value) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BytesKind:
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			if b, err = base64.URLEncoding.DecodeString(s); err != nil {
+				return protoreflect.Value{}, pkgerrors.WithStack(err)
+			}
+		}
+		return protoreflect.ValueOfBytes(b), nil
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(s)
+		return protoreflect.ValueOfBool(b), pkgerrors.WithStack(err)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		i, err := strconv.ParseInt(s, 10, 32)
+		return protoreflect.ValueOfInt32(int32(i)), pkgerrors.WithStack(err)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		i, err := strconv.ParseInt(s, 10, 64)
+		return protoreflect.ValueOfInt64(i), pkgerrors.WithStack(err)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		u, err := strconv.ParseUint(s, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(u)), pkgerrors.WithStack(err)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		u, err := strconv.ParseUint(s, 10, 64)
+		return protoreflect.ValueOfUint64(u), pkgerrors.WithStack(err)
+	case protoreflect.FloatKind:
+		f, err := strconv.ParseFloat(s, 32)
+		return protoreflect.ValueOfFloat32(float32(f)), pkgerrors.WithStack(err)
+	case protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(s, 64)
+		return protoreflect.ValueOfFloat64(f), pkgerrors.WithStack(err)
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		i, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, pkgerrors.Errorf("unknown enum value %s", s)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(i)), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if err := protojson.Unmarshal([]byte(strconv.Quote(s)), newValue.Message().Interface()); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return newValue, nil
+	default:
+		return protoreflect.Value{}, pkgerrors.Errorf("unsupported field kind %s", fd.Kind())
+	}
+}
@@ -0,0 +1,362 @@
+package lit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/viebiz/lit/grpcclient/testdata"
+)
+
+func TestGRPCGateway(t *testing.T) {
+	rule := &annotations.HttpRule{
+		Pattern: &annotations.HttpRule_Get{Get: "/v1/weather/{location}"},
+		AdditionalBindings: []*annotations.HttpRule{
+			{Pattern: &annotations.HttpRule_Post{Post: "/v1/weather/{location}"}, Body: "*"},
+			{Pattern: &annotations.HttpRule_Get{Get: "/v1/weather-details/{location=**}"}, ResponseBody: "weather_details"},
+		},
+	}
+	weatherResp := &testdata.WeatherResponse{
+		WeatherDetails: []*testdata.WeatherDetail{{Location: "Macragge", Description: "Sunny", Temperature: 21.5}},
+	}
+
+	type mockData struct {
+		inReq  *testdata.WeatherRequest
+		outRes *testdata.WeatherResponse
+		outErr error
+	}
+	tcs := map[string]struct {
+		givenMethod string
+		givenURL    string
+		givenBody   string
+		mockData    *mockData
+		expStatus   int
+		expBody     string
+	}{
+		"path and query": {
+			givenMethod: http.MethodGet,
+			givenURL:    "/v1/weather/Macragge?date=2024-01-01&unknown=1",
+			mockData: &mockData{
+				inReq:  &testdata.WeatherRequest{Location: "Macragge", Date: "2024-01-01"},
+				outRes: weatherResp,
+			},
+			expStatus: http.StatusOK,
+			expBody:   `{"weather_details":[{"location":"Macragge","description":"Sunny","temperature":21.5}]}`,
+		},
+		"body, path wins": {
+			givenMethod: http.MethodPost,
+			givenURL:    "/v1/weather/Macragge",
+			givenBody:   `{"location":"Cadia","date":"2024-01-01"}`,
+			mockData: &mockData{
+				inReq:  &testdata.WeatherRequest{Location: "Macragge", Date: "2024-01-01"},
+				outRes: weatherResp,
+			},
+			expStatus: http.StatusOK,
+			expBody:   `{"weather_details":[{"location":"Macragge","description":"Sunny","temperature":21.5}]}`,
+		},
+		"catch all path with response body": {
+			givenMethod: http.MethodGet,
+			givenURL:    "/v1/weather-details/segmentum/obscurus/Cadia",
+			mockData: &mockData{
+				inReq:  &testdata.WeatherRequest{Location: "segmentum/obscurus/Cadia"},
+				outRes: weatherResp,
+			},
+			expStatus: http.StatusOK,
+			expBody:   `[{"location":"Macragge","description":"Sunny","temperature":21.5}]`,
+		},
+		"invalid body": {
+			givenMethod: http.MethodPost,
+			givenURL:    "/v1/weather/Macragge",
+			givenBody:   `{"location":`,
+			expStatus:   http.StatusBadRequest,
+			expBody:     `"error":"invalid_argument"`,
+		},
+		"lit error": {
+			givenMethod: http.MethodGet,
+			givenURL:    "/v1/weather/Terra",
+			mockData: &mockData{
+				inReq:  &testdata.WeatherRequest{Location: "Terra"},
+				outRes: (*testdata.WeatherResponse)(nil),
+				outErr: HttpError{Status: http.StatusNotFound, Code: "weather_not_found", Desc: "Weather not found"},
+			},
+			expStatus: http.StatusNotFound,
+			expBody:   `{"error":"weather_not_found","error_description":"Weather not found"}`,
+		},
+		"validation error": {
+			givenMethod: http.MethodGet,
+			givenURL:    "/v1/weather/Terra",
+			mockData: &mockData{
+				inReq:  &testdata.WeatherRequest{Location: "Terra"},
+				outRes: (*testdata.WeatherResponse)(nil),
+				outErr: ValidationError{"date": "date is required"},
+			},
+			expStatus: http.StatusBadRequest,
+			expBody:   `{"date":"date is required"}`,
+		},
+		"grpc status error": {
+			givenMethod: http.MethodGet,
+			givenURL:    "/v1/weather/Terra",
+			mockData: &mockData{
+				inReq:  &testdata.WeatherRequest{Location: "Terra"},
+				outRes: (*testdata.WeatherResponse)(nil),
+				outErr: status.Error(codes.Unavailable, "weather station is down"),
+			},
+			expStatus: http.StatusServiceUnavailable,
+			expBody:   `{"error":"unavailable","error_description":"weather station is down"}`,
+		},
+		"unexpected error": {
+			givenMethod: http.MethodGet,
+			givenURL:    "/v1/weather/Terra",
+			mockData: &mockData{
+				inReq:  &testdata.WeatherRequest{Location: "Terra"},
+				outRes: (*testdata.WeatherResponse)(nil),
+				outErr: errors.New("connection refused"),
+			},
+			expStatus: http.StatusInternalServerError,
+			expBody:   `{"error":"internal_server_error","error_description":"Something went wrong"}`,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			weatherSvc := new(weatherService)
+			if tc.mockData != nil {
+				hasAuthMetadata := mock.MatchedBy(func(ctx context.Context) bool {
+					md, _ := metadata.FromIncomingContext(ctx)
+					return len(md.Get("authorization")) == 1 && md.Get("authorization")[0] == "Bearer token"
+				})
+				weatherSvc.On("GetWeatherInfo", hasAuthMetadata, mock.MatchedBy(func(req *testdata.WeatherRequest) bool {
+					return proto.Equal(tc.mockData.inReq, req)
+				})).Return(tc.mockData.outRes, tc.mockData.outErr)
+			}
+
+			gw := NewGRPCGateway(GatewayWithHTTPRule(testdata.WeatherService_GetWeatherInfo_FullMethodName, rule))
+			testdata.RegisterWeatherServiceServer(gw, weatherSvc)
+
+			r, hdl := NewRouter()
+			r.Use(func(c Context) {
+				c.Header("X-Middleware", "applied")
+				c.Next()
+			})
+			require.NoError(t, gw.Mount(r))
+
+			req := httptest.NewRequest(tc.givenMethod, tc.givenURL, strings.NewReader(tc.givenBody))
+			req.Header.Set("Authorization", "Bearer token")
+			w := httptest.NewRecorder()
+
+			// When
+			hdl.ServeHTTP(w, req)
+
+			// Then
+			require.Equal(t, tc.expStatus, w.Code)
+			if tc.expStatus == http.StatusOK {
+				require.JSONEq(t, tc.expBody, w.Body.String()) // protojson output is not stable byte by byte
+			} else {
+				require.Contains(t, w.Body.String(), tc.expBody)
+			}
+			require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			require.Equal(t, "applied", w.Header().Get("X-Middleware"))
+			weatherSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGRPCGateway_Annotations(t *testing.T) {
+	// Given
+	sd := newAnnotatedWeatherService(t, &annotations.HttpRule{
+		Pattern: &annotations.HttpRule_Get{Get: "/v1/locations/{location}/weather"},
+	})
+
+	weatherSvc := new(weatherService)
+	weatherSvc.On("GetWeatherInfo", mock.Anything, mock.MatchedBy(func(req *testdata.WeatherRequest) bool {
+		return req.GetLocation() == "Macragge"
+	})).Return(&testdata.WeatherResponse{}, nil)
+
+	var grpcInterceptorCalled bool
+	gw := NewGRPCGateway(GatewayWithUnaryInterceptors(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		grpcInterceptorCalled = true
+		require.Equal(t, testdata.WeatherService_GetWeatherInfo_FullMethodName, info.FullMethod)
+		return handler(ctx, req)
+	}))
+	gw.RegisterServiceWithDescriptor(&testdata.WeatherService_ServiceDesc, weatherSvc, sd)
+
+	r, hdl := NewRouter()
+	require.NoError(t, gw.Mount(r))
+
+	w := httptest.NewRecorder()
+
+	// When
+	hdl.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/locations/Macragge/weather", nil))
+
+	// Then
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{}`, w.Body.String())
+	require.True(t, grpcInterceptorCalled)
+	weatherSvc.AssertExpectations(t)
+}
+
+func TestGRPCGateway_Mount(t *testing.T) {
+	tcs := map[string]struct {
+		givenRule *annotations.HttpRule
+		expErr    string
+	}{
+		"no rule": {},
+		"unknown path field": {
+			givenRule: &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: "/v1/weather/{city}"}},
+			expErr:    "grpc gateway: /weather.WeatherService/GetWeatherInfo: path /v1/weather/{city}: field city not found in weather.WeatherRequest",
+		},
+		"unknown body field": {
+			givenRule: &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: "/v1/weather"}, Body: "city"},
+			expErr:    "grpc gateway: /weather.WeatherService/GetWeatherInfo: body field city not found in weather.WeatherRequest",
+		},
+		"unknown response field": {
+			givenRule: &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: "/v1/weather"}, ResponseBody: "details"},
+			expErr:    "grpc gateway: /weather.WeatherService/GetWeatherInfo: response body field details not found in weather.WeatherResponse",
+		},
+		"no pattern": {
+			givenRule: &annotations.HttpRule{},
+			expErr:    "grpc gateway: /weather.WeatherService/GetWeatherInfo: http rule has no pattern",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			var opts []GatewayOption
+			if tc.givenRule != nil {
+				opts = append(opts, GatewayWithHTTPRule(testdata.WeatherService_GetWeatherInfo_FullMethodName, tc.givenRule))
+			}
+			gw := NewGRPCGateway(opts...)
+			testdata.RegisterWeatherServiceServer(gw, new(weatherService))
+			r, _ := NewRouter()
+
+			// When
+			err := gw.Mount(r)
+
+			// Then
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_parsePathTemplate(t *testing.T) {
+	tcs := map[string]struct {
+		givenTmpl string
+		expPath   string
+		expParams []gatewayPathParam
+		expErr    string
+	}{
+		"literal": {
+			givenTmpl: "/v1/weather",
+			expPath:   "/v1/weather",
+		},
+		"variables": {
+			givenTmpl: "/v1/locations/{location.city}/weather/{date=*}",
+			expPath:   "/v1/locations/:p0/weather/:p1",
+			expParams: []gatewayPathParam{{name: "p0", field: "location.city"}, {name: "p1", field: "date"}},
+		},
+		"wildcards": {
+			givenTmpl: "/v1/*/weather/{location=**}",
+			expPath:   "/v1/:p0/weather/*p1",
+			expParams: []gatewayPathParam{{name: "p0"}, {name: "p1", field: "location", catchAll: true}},
+		},
+		"relative path": {
+			givenTmpl: "v1/weather",
+			expErr:    "path v1/weather must start with /",
+		},
+		"catch all not last": {
+			givenTmpl: "/v1/{location=**}/weather",
+			expErr:    "path /v1/{location=**}/weather: ** must be the last segment",
+		},
+		"nested pattern": {
+			givenTmpl: "/v1/{name=locations/*}",
+			expErr:    "path /v1/{name=locations/*}: unsupported segment {name=locations",
+		},
+		"custom verb": {
+			givenTmpl: "/v1/weather:forecast",
+			expErr:    "path /v1/weather:forecast: unsupported segment weather:forecast",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			path, params, err := parsePathTemplate(tc.givenTmpl)
+
+			// Then
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expPath, path)
+			require.Equal(t, tc.expParams, params)
+		})
+	}
+}
+
+func TestMultiRegistrar(t *testing.T) {
+	// Given
+	srv, err := NewGRPCServerWithOptions(context.Background(), ":0")
+	require.NoError(t, err)
+	gw := NewGRPCGateway()
+
+	// When
+	testdata.RegisterWeatherServiceServer(MultiRegistrar(srv.Registrar(), gw), new(weatherService))
+
+	// Then
+	require.Equal(t, []string{"weather.WeatherService"}, srv.serviceNames())
+	require.Len(t, gw.services, 1)
+}
+
+// newAnnotatedWeatherService builds the descriptor of a weather service annotated with the given HTTP rule
+func newAnnotatedWeatherService(t *testing.T, rule *annotations.HttpRule) protoreflect.ServiceDescriptor {
+	methodOpts := &descriptorpb.MethodOptions{}
+	proto.SetExtension(methodOpts, annotations.E_Http, rule)
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("weather/v1/gateway.proto"),
+		Package:    proto.String("weather"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{testdata.File_grpcclient_testdata_weather_proto.Path()},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("AnnotatedWeatherService"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("GetWeatherInfo"),
+				InputType:  proto.String(".weather.WeatherRequest"),
+				OutputType: proto.String(".weather.WeatherResponse"),
+				Options:    methodOpts,
+			}},
+		}},
+	}, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	return fd.Services().Get(0)
+}
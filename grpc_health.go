@@ -0,0 +1,116 @@
+package lit
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/viebiz/lit/monitoring"
+)
+
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+)
+
+// healthCheck is a dependency check affecting the serving status of the given services
+type healthCheck struct {
+	name     string
+	check    ReadinessCheck
+	services []string // Empty means all services
+}
+
+// grpcHealth drives the serving status of the health service using the dependency checks
+type grpcHealth struct {
+	server   *health.Server
+	monitor  *monitoring.Monitor
+	checks   []healthCheck
+	interval time.Duration
+
+	failing map[string]bool // Keeps the last result by check name to only log the changes
+}
+
+func newGRPCHealth(m *monitoring.Monitor, checks []healthCheck, interval time.Duration) *grpcHealth {
+	if interval <= 0 {
+		interval = defaultHealthCheckInterval
+	}
+
+	return &grpcHealth{
+		server:   health.NewServer(),
+		monitor:  m,
+		checks:   checks,
+		interval: interval,
+		failing:  map[string]bool{},
+	}
+}
+
+// run updates the serving status of the given services periodically until ctx is done
+func (h *grpcHealth) run(ctx context.Context, services []string) {
+	h.update(ctx, services)
+	if len(h.checks) == 0 {
+		return // Nothing changes the status
+	}
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.update(ctx, services)
+		}
+	}
+}
+
+// update runs all checks and sets the serving status of the overall server ("") and each service
+func (h *grpcHealth) update(ctx context.Context, services []string) {
+	notServing := map[string]bool{}
+	for _, chk := range h.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, h.interval)
+		err := chk.check(checkCtx)
+		cancel()
+
+		h.logChange(chk.name, err)
+		if err == nil {
+			continue
+		}
+
+		notServing[""] = true
+		targets := chk.services
+		if len(targets) == 0 {
+			targets = services
+		}
+		for _, svc := range targets {
+			notServing[svc] = true
+		}
+	}
+
+	for _, svc := range append([]string{""}, services...) {
+		st := healthpb.HealthCheckResponse_SERVING
+		if notServing[svc] {
+			st = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+
+		h.server.SetServingStatus(svc, st)
+	}
+}
+
+func (h *grpcHealth) logChange(name string, err error) {
+	wasFailing := h.failing[name]
+	switch {
+	case err != nil && !wasFailing:
+		h.monitor.Warnf("Health check %s failed: %v", name, err)
+	case err == nil && wasFailing:
+		h.monitor.Infof("Health check %s recovered", name)
+	}
+
+	h.failing[name] = err != nil
+}
+
+// shutdown sets all services to NOT_SERVING and ignores further updates
+func (h *grpcHealth) shutdown() {
+	h.server.Shutdown()
+}
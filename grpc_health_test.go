@@ -0,0 +1,130 @@
+package lit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/viebiz/lit/grpcclient/testdata"
+	"github.com/viebiz/lit/monitoring"
+)
+
+func TestNewGRPCServerWithOptions_HealthAndReflection(t *testing.T) {
+	tcs := map[string]struct {
+		givenOpts   []GRPCOption
+		expServices []string
+	}{
+		"default": {
+			expServices: []string{"weather.WeatherService"},
+		},
+		"health": {
+			givenOpts:   []GRPCOption{WithHealthService()},
+			expServices: []string{"grpc.health.v1.Health", "weather.WeatherService"},
+		},
+		"reflection": {
+			givenOpts:   []GRPCOption{WithReflection()},
+			expServices: []string{"grpc.reflection.v1.ServerReflection", "grpc.reflection.v1alpha.ServerReflection", "weather.WeatherService"},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// When
+			srv, err := NewGRPCServerWithOptions(context.Background(), ":0", tc.givenOpts...)
+			require.NoError(t, err)
+			testdata.RegisterWeatherServiceServer(srv.Registrar(), new(weatherService))
+
+			// Then
+			var services []string
+			for name := range srv.grpcServer.GetServiceInfo() {
+				services = append(services, name)
+			}
+			require.ElementsMatch(t, tc.expServices, services)
+			require.Equal(t, []string{"weather.WeatherService"}, srv.serviceNames())
+		})
+	}
+}
+
+func TestGRPCHealth_Update(t *testing.T) {
+	failed := func(ctx context.Context) error { return errors.New("connection refused") }
+	ok := func(ctx context.Context) error { return nil }
+
+	tcs := map[string]struct {
+		givenChecks []healthCheck
+		expStatus   map[string]healthpb.HealthCheckResponse_ServingStatus
+	}{
+		"no checks": {
+			expStatus: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"":                 healthpb.HealthCheckResponse_SERVING,
+				"weather.Weather":  healthpb.HealthCheckResponse_SERVING,
+				"weather.Forecast": healthpb.HealthCheckResponse_SERVING,
+			},
+		},
+		"all checks passed": {
+			givenChecks: []healthCheck{{name: "postgres", check: ok}, {name: "redis", check: ok}},
+			expStatus: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"":                 healthpb.HealthCheckResponse_SERVING,
+				"weather.Weather":  healthpb.HealthCheckResponse_SERVING,
+				"weather.Forecast": healthpb.HealthCheckResponse_SERVING,
+			},
+		},
+		"global check failed": {
+			givenChecks: []healthCheck{{name: "postgres", check: failed}, {name: "redis", check: ok}},
+			expStatus: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"":                 healthpb.HealthCheckResponse_NOT_SERVING,
+				"weather.Weather":  healthpb.HealthCheckResponse_NOT_SERVING,
+				"weather.Forecast": healthpb.HealthCheckResponse_NOT_SERVING,
+			},
+		},
+		"per service check failed": {
+			givenChecks: []healthCheck{{name: "redis", check: failed, services: []string{"weather.Forecast"}}},
+			expStatus: map[string]healthpb.HealthCheckResponse_ServingStatus{
+				"":                 healthpb.HealthCheckResponse_NOT_SERVING,
+				"weather.Weather":  healthpb.HealthCheckResponse_SERVING,
+				"weather.Forecast": healthpb.HealthCheckResponse_NOT_SERVING,
+			},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			m, err := monitoring.New(monitoring.Config{Writer: io.Discard})
+			require.NoError(t, err)
+			h := newGRPCHealth(m, tc.givenChecks, 0)
+
+			// When
+			h.update(context.Background(), []string{"weather.Weather", "weather.Forecast"})
+
+			// Then
+			for svc, expStatus := range tc.expStatus {
+				resp, err := h.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: svc})
+				require.NoError(t, err)
+				require.Equal(t, expStatus, resp.GetStatus(), "service: %s", svc)
+			}
+		})
+	}
+}
+
+func TestGRPCHealth_Shutdown(t *testing.T) {
+	// Given
+	h := newGRPCHealth(nil, nil, 0)
+	h.update(context.Background(), []string{"weather.Weather"})
+
+	// When
+	h.shutdown()
+	h.update(context.Background(), []string{"weather.Weather"}) // Should be ignored after shutdown
+
+	// Then
+	for _, svc := range []string{"", "weather.Weather"} {
+		resp, err := h.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: svc})
+		require.NoError(t, err)
+		require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+	}
+}
@@ -0,0 +1,131 @@
+package lit
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/viebiz/lit/monitoring"
+)
+
+const (
+	grpcTooManyRequestsCode = "too_many_requests"
+	grpcTooManyRequestsDesc = "Too many requests"
+)
+
+// methodDeadlines applies the default deadline of the method to the calls without deadline
+type methodDeadlines struct {
+	defaultTimeout time.Duration
+	methods        map[string]time.Duration
+}
+
+func (d methodDeadlines) enabled() bool {
+	return d.defaultTimeout > 0 || len(d.methods) > 0
+}
+
+// withDeadline returns the context with the deadline of the method if the client sent none
+func (d methodDeadlines) withDeadline(ctx context.Context, fullMethod string) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+
+	timeout, ok := d.methods[fullMethod]
+	if !ok {
+		timeout = d.defaultTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (d methodDeadlines) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cancel := d.withDeadline(ctx, info.FullMethod)
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
+
+func (d methodDeadlines) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := d.withDeadline(ss.Context(), info.FullMethod)
+		defer cancel()
+
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// methodLimiter limits the in-flight calls by method, using a buffered channel as semaphore
+type methodLimiter struct {
+	slots map[string]chan struct{}
+}
+
+func newMethodLimiter(limits map[string]int) methodLimiter {
+	slots := make(map[string]chan struct{}, len(limits))
+	for method, limit := range limits {
+		slots[method] = make(chan struct{}, limit)
+	}
+
+	return methodLimiter{slots: slots}
+}
+
+// acquire takes a slot of the method without blocking, the returned release must be called when the call ends
+func (l methodLimiter) acquire(fullMethod string) (func(), bool) {
+	slot, ok := l.slots[fullMethod]
+	if !ok {
+		return func() {}, true // Not limited
+	}
+
+	select {
+	case slot <- struct{}{}:
+		return func() { <-slot }, true
+	default:
+		return nil, false
+	}
+}
+
+func (l methodLimiter) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		release, ok := l.acquire(info.FullMethod)
+		if !ok {
+			return nil, l.rejectErr(ctx, info.FullMethod)
+		}
+		defer release()
+
+		return handler(ctx, req)
+	}
+}
+
+func (l methodLimiter) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, ok := l.acquire(info.FullMethod)
+		if !ok {
+			return l.rejectErr(ss.Context(), info.FullMethod)
+		}
+		defer release()
+
+		return handler(srv, ss)
+	}
+}
+
+func (l methodLimiter) rejectErr(ctx context.Context, fullMethod string) error {
+	monitoring.FromContext(ctx).Warnf("gRPC method %s reached the concurrency limit %d", fullMethod, cap(l.slots[fullMethod]))
+
+	return newGRPCStatusError(codes.ResourceExhausted, grpcTooManyRequestsDesc, grpcTooManyRequestsCode, nil)
+}
+
+// contextServerStream overrides the context of the stream
+type contextServerStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,180 @@
+package lit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMethodDeadlines_unaryInterceptor(t *testing.T) {
+	deadlines := methodDeadlines{
+		defaultTimeout: 5 * time.Second,
+		methods:        map[string]time.Duration{"/weather.WeatherService/GetWeatherInfo": time.Second},
+	}
+
+	tcs := map[string]struct {
+		givenDeadlines methodDeadlines
+		givenMethod    string
+		givenTimeout   time.Duration
+		expTimeout     time.Duration
+		expDeadline    bool
+	}{
+		"method deadline": {
+			givenDeadlines: deadlines,
+			givenMethod:    "/weather.WeatherService/GetWeatherInfo",
+			expTimeout:     time.Second,
+			expDeadline:    true,
+		},
+		"default deadline": {
+			givenDeadlines: deadlines,
+			givenMethod:    "/weather.WeatherService/ListStations",
+			expTimeout:     5 * time.Second,
+			expDeadline:    true,
+		},
+		"client deadline is kept": {
+			givenDeadlines: deadlines,
+			givenMethod:    "/weather.WeatherService/GetWeatherInfo",
+			givenTimeout:   time.Minute,
+			expTimeout:     time.Minute,
+			expDeadline:    true,
+		},
+		"no default deadline": {
+			givenDeadlines: methodDeadlines{methods: deadlines.methods},
+			givenMethod:    "/weather.WeatherService/ListStations",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+			if tc.givenTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.givenTimeout)
+				defer cancel()
+			}
+
+			var deadline time.Time
+			var hasDeadline bool
+			handler := func(ctx context.Context, req any) (any, error) {
+				deadline, hasDeadline = ctx.Deadline()
+				return "ok", nil
+			}
+
+			// When
+			start := time.Now()
+			rs, err := tc.givenDeadlines.unaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.givenMethod}, handler)
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, "ok", rs)
+			require.Equal(t, tc.expDeadline, hasDeadline)
+			if tc.expDeadline {
+				require.WithinDuration(t, start.Add(tc.expTimeout), deadline, 100*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestMethodDeadlines_streamInterceptor(t *testing.T) {
+	// Given
+	deadlines := methodDeadlines{defaultTimeout: time.Second}
+
+	var hasDeadline bool
+	handler := func(srv any, ss grpc.ServerStream) error {
+		_, hasDeadline = ss.Context().Deadline()
+		return nil
+	}
+
+	// When
+	err := deadlines.streamInterceptor()(nil, &contextServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/weather.WeatherService/StreamWeather"}, handler)
+
+	// Then
+	require.NoError(t, err)
+	require.True(t, hasDeadline)
+}
+
+func TestMethodLimiter_unaryInterceptor(t *testing.T) {
+	tcs := map[string]struct {
+		givenMethod   string
+		givenInFlight int
+		expErr        error
+	}{
+		"under the limit": {
+			givenMethod:   "/weather.WeatherService/GetWeatherInfo",
+			givenInFlight: 1,
+		},
+		"not limited method": {
+			givenMethod:   "/weather.WeatherService/ListStations",
+			givenInFlight: 5,
+		},
+		"error - limit reached": {
+			givenMethod:   "/weather.WeatherService/GetWeatherInfo",
+			givenInFlight: 2,
+			expErr:        status.Error(codes.ResourceExhausted, "Too many requests"),
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			limiter := newMethodLimiter(map[string]int{"/weather.WeatherService/GetWeatherInfo": 2})
+			intercept := limiter.unaryInterceptor()
+			info := &grpc.UnaryServerInfo{FullMethod: tc.givenMethod}
+
+			// Hold the in-flight calls until the checked call is done
+			blocked, started := make(chan struct{}), make(chan struct{})
+			for i := 0; i < tc.givenInFlight; i++ {
+				go func() {
+					_, _ = intercept(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+						started <- struct{}{}
+						<-blocked
+						return nil, nil
+					})
+				}()
+				<-started
+			}
+			defer close(blocked)
+
+			// When
+			rs, err := intercept(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+				return "ok", nil
+			})
+
+			// Then
+			if tc.expErr != nil {
+				require.EqualError(t, err, tc.expErr.Error())
+				require.Equal(t, codes.ResourceExhausted, status.Code(err))
+				require.Nil(t, rs)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "ok", rs)
+			}
+		})
+	}
+}
+
+func TestMethodLimiter_release(t *testing.T) {
+	// Given
+	limiter := newMethodLimiter(map[string]int{"/weather.WeatherService/StreamWeather": 1})
+	intercept := limiter.streamInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/weather.WeatherService/StreamWeather"}
+	handler := func(srv any, ss grpc.ServerStream) error { return nil }
+
+	// When
+	for i := 0; i < 3; i++ {
+		err := intercept(nil, &contextServerStream{ctx: context.Background()}, info, handler)
+
+		// Then
+		require.NoError(t, err) // The slot is released after each call
+	}
+}
@@ -3,13 +3,30 @@ package lit
 import (
 	"context"
 	"crypto/tls"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 )
 
+// GRPCOption is an optional config used to modify the GRPCServer's behaviour
 type GRPCOption func(option *[]grpc.ServerOption)
 
+// grpcConfigOption carries the configurations of the GRPCServer that are not a grpc.ServerOption, e.g. the health
+// service, among the grpc.ServerOption appended by the GRPCOption
+type grpcConfigOption struct {
+	grpc.EmptyServerOption
+	apply func(*grpcConfig)
+}
+
+// withConfig returns the GRPCOption applying the configurations of the GRPCServer
+func withConfig(apply func(*grpcConfig)) GRPCOption {
+	return func(opts *[]grpc.ServerOption) {
+		*opts = append(*opts, grpcConfigOption{apply: apply})
+	}
+}
+
 func WithTLSConfig(tlsConfig *tls.Config) GRPCOption {
 	return func(opts *[]grpc.ServerOption) {
 		*opts = append(*opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
@@ -17,10 +34,203 @@ func WithTLSConfig(tlsConfig *tls.Config) GRPCOption {
 }
 
 func WithDefaultInterceptors(ctx context.Context) GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		cfg.unaryInterceptors = append(cfg.unaryInterceptors, unaryServerInterceptor(ctx))
+		cfg.streamInterceptors = append(cfg.streamInterceptors, streamServerInterceptor(ctx))
+	})
+}
+
+// WithUnaryInterceptors adds unary interceptors, chained after the ones added before, e.g. guard.AuthGuard.UnaryServerInterceptor
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		cfg.unaryInterceptors = append(cfg.unaryInterceptors, interceptors...)
+	})
+}
+
+// WithStreamInterceptors adds stream interceptors, chained after the ones added before, e.g. guard.AuthGuard.StreamServerInterceptor
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		cfg.streamInterceptors = append(cfg.streamInterceptors, interceptors...)
+	})
+}
+
+// WithHealthService registers the standard grpc.health.v1.Health service
+// All registered services are reported as SERVING until a health check fails
+func WithHealthService() GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		cfg.healthEnabled = true
+	})
+}
+
+// WithHealthCheck registers the health service and adds a dependency check, e.g. pinging postgres or redis
+// The given services are reported as NOT_SERVING while the check fails, all services if none is given
+func WithHealthCheck(name string, check ReadinessCheck, services ...string) GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		cfg.healthEnabled = true
+		cfg.healthChecks = append(cfg.healthChecks, healthCheck{name: name, check: check, services: services})
+	})
+}
+
+// WithHealthCheckInterval overrides the default interval between health checks
+func WithHealthCheckInterval(interval time.Duration) GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		cfg.healthCheckInterval = interval
+	})
+}
+
+// WithReflection registers the gRPC server reflection service, e.g. for grpcurl debugging
+func WithReflection() GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		cfg.reflectionEnabled = true
+	})
+}
+
+// WithKeepaliveEnforcementPolicy rejects clients pinging more often than minTime by closing the connection with GOAWAY
+// permitWithoutStream allows the pings when there is no active stream
+func WithKeepaliveEnforcementPolicy(minTime time.Duration, permitWithoutStream bool) GRPCOption {
+	return func(opts *[]grpc.ServerOption) {
+		*opts = append(*opts, grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             minTime,
+			PermitWithoutStream: permitWithoutStream,
+		}))
+	}
+}
+
+// WithKeepalivePing makes the server ping idle connections after interval, and close them if no ack is received within timeout
+func WithKeepalivePing(interval, timeout time.Duration) GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		cfg.keepaliveParams.Time = interval
+		cfg.keepaliveParams.Timeout = timeout
+	})
+}
+
+// WithConnectionAge closes connections older than maxAge, after letting the pending RPCs finish within grace
+// It helps to rebalance the long-lived connections behind a load balancer
+func WithConnectionAge(maxAge, grace time.Duration) GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		cfg.keepaliveParams.MaxConnectionAge = maxAge
+		cfg.keepaliveParams.MaxConnectionAgeGrace = grace
+	})
+}
+
+// WithMaxConnectionIdle closes connections without any RPC for the given duration
+func WithMaxConnectionIdle(idle time.Duration) GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		cfg.keepaliveParams.MaxConnectionIdle = idle
+	})
+}
+
+// WithMaxRecvMsgSize overrides the max message size in bytes the server can receive, default is 4MB
+func WithMaxRecvMsgSize(size int) GRPCOption {
+	return func(opts *[]grpc.ServerOption) {
+		*opts = append(*opts, grpc.MaxRecvMsgSize(size))
+	}
+}
+
+// WithMaxSendMsgSize overrides the max message size in bytes the server can send, default is unlimited
+func WithMaxSendMsgSize(size int) GRPCOption {
+	return func(opts *[]grpc.ServerOption) {
+		*opts = append(*opts, grpc.MaxSendMsgSize(size))
+	}
+}
+
+// WithMaxConcurrentStreams limits the number of concurrent streams, i.e. RPCs, of each connection
+func WithMaxConcurrentStreams(n uint32) GRPCOption {
 	return func(opts *[]grpc.ServerOption) {
-		*opts = append(*opts,
-			grpc.ChainUnaryInterceptor(unaryServerInterceptor(ctx)),
-			//grpc.ChainStreamInterceptor(streamServerInterceptor(ctx)), // TODO: Implement later
-		)
+		*opts = append(*opts, grpc.MaxConcurrentStreams(n))
 	}
 }
+
+// WithDefaultDeadline applies the deadline to all calls without deadline sent by the client
+func WithDefaultDeadline(timeout time.Duration) GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		cfg.deadlines.defaultTimeout = timeout
+	})
+}
+
+// WithMethodDeadline applies the deadline to the calls of the method without deadline sent by the client
+// It takes precedence over WithDefaultDeadline
+func WithMethodDeadline(fullMethod string, timeout time.Duration) GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		if cfg.deadlines.methods == nil {
+			cfg.deadlines.methods = map[string]time.Duration{}
+		}
+		cfg.deadlines.methods[fullMethod] = timeout
+	})
+}
+
+// WithMethodConcurrencyLimit limits the number of in-flight calls of the method
+// Calls over the limit are rejected immediately with ResourceExhausted, a limit <= 0 is ignored
+func WithMethodConcurrencyLimit(fullMethod string, limit int) GRPCOption {
+	return withConfig(func(cfg *grpcConfig) {
+		if limit <= 0 {
+			return
+		}
+		if cfg.concurrencyLimits == nil {
+			cfg.concurrencyLimits = map[string]int{}
+		}
+		cfg.concurrencyLimits[fullMethod] = limit
+	})
+}
+
+// grpcConfig is configurations of the GRPCServer
+type grpcConfig struct {
+	serverOpts          []grpc.ServerOption
+	unaryInterceptors   []grpc.UnaryServerInterceptor
+	streamInterceptors  []grpc.StreamServerInterceptor
+	healthEnabled       bool
+	healthChecks        []healthCheck
+	healthCheckInterval time.Duration
+	reflectionEnabled   bool
+	keepaliveParams     keepalive.ServerParameters
+	deadlines           methodDeadlines
+	concurrencyLimits   map[string]int
+}
+
+// newGRPCConfig returns the configurations of the GRPCServer applied by the options
+func newGRPCConfig(opts ...GRPCOption) grpcConfig {
+	var serverOpts []grpc.ServerOption
+	for _, opt := range opts {
+		opt(&serverOpts)
+	}
+
+	cfg := grpcConfig{}
+	for _, opt := range serverOpts {
+		if cfgOpt, ok := opt.(grpcConfigOption); ok {
+			cfgOpt.apply(&cfg)
+			continue
+		}
+		cfg.serverOpts = append(cfg.serverOpts, opt)
+	}
+
+	return cfg
+}
+
+// buildServerOptions returns grpc.ServerOption of the config, with the interceptors chained in order
+// The deadline and concurrency limit interceptors are the innermost ones, so rejected calls are still traced
+func (cfg grpcConfig) buildServerOptions() []grpc.ServerOption {
+	opts := cfg.serverOpts
+	if cfg.keepaliveParams != (keepalive.ServerParameters{}) {
+		opts = append(opts, grpc.KeepaliveParams(cfg.keepaliveParams))
+	}
+
+	unaryInterceptors, streamInterceptors := cfg.unaryInterceptors, cfg.streamInterceptors
+	if cfg.deadlines.enabled() {
+		unaryInterceptors = append(unaryInterceptors, cfg.deadlines.unaryInterceptor())
+		streamInterceptors = append(streamInterceptors, cfg.deadlines.streamInterceptor())
+	}
+	if len(cfg.concurrencyLimits) > 0 {
+		limiter := newMethodLimiter(cfg.concurrencyLimits)
+		unaryInterceptors = append(unaryInterceptors, limiter.unaryInterceptor())
+		streamInterceptors = append(streamInterceptors, limiter.streamInterceptor())
+	}
+
+	if len(unaryInterceptors) > 0 {
+		opts = append(opts, grpc.ChainUnaryInterceptor(unaryInterceptors...))
+	}
+	if len(streamInterceptors) > 0 {
+		opts = append(opts, grpc.ChainStreamInterceptor(streamInterceptors...))
+	}
+
+	return opts
+}
@@ -0,0 +1,136 @@
+package lit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+func TestGRPCOption_Interceptors(t *testing.T) {
+	// Given
+	var calls []string
+	unary := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			calls = append(calls, name)
+			return handler(ctx, req)
+		}
+	}
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, ss)
+	}
+
+	cfg := newGRPCConfig(
+		WithDefaultInterceptors(context.Background()),
+		WithUnaryInterceptors(unary("auth"), unary("audit")),
+		WithStreamInterceptors(stream),
+	)
+
+	// When
+	_, err := cfg.unaryInterceptors[1](context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		return cfg.unaryInterceptors[2](ctx, req, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+			return nil, nil
+		})
+	})
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, cfg.unaryInterceptors, 3)
+	require.Len(t, cfg.streamInterceptors, 2)
+	require.Equal(t, []string{"auth", "audit"}, calls)
+	require.Len(t, cfg.buildServerOptions(), 2)
+}
+
+func TestGRPCOption_ServerLimits(t *testing.T) {
+	tcs := map[string]struct {
+		givenOpts      []GRPCOption
+		expKeepalive   keepalive.ServerParameters
+		expServerOpts  int
+		expBuiltOpts   int
+		expDeadlines   methodDeadlines
+		expConcurrency map[string]int
+	}{
+		"no option": {},
+		"keepalive and connection age": {
+			givenOpts: []GRPCOption{
+				WithKeepaliveEnforcementPolicy(time.Minute, true),
+				WithKeepalivePing(2*time.Hour, 20*time.Second),
+				WithConnectionAge(30*time.Minute, time.Minute),
+				WithMaxConnectionIdle(15 * time.Minute),
+			},
+			expKeepalive: keepalive.ServerParameters{
+				MaxConnectionIdle:     15 * time.Minute,
+				MaxConnectionAge:      30 * time.Minute,
+				MaxConnectionAgeGrace: time.Minute,
+				Time:                  2 * time.Hour,
+				Timeout:               20 * time.Second,
+			},
+			expServerOpts: 1,
+			expBuiltOpts:  2,
+		},
+		"message sizes and streams": {
+			givenOpts: []GRPCOption{
+				WithMaxRecvMsgSize(8 << 20),
+				WithMaxSendMsgSize(8 << 20),
+				WithMaxConcurrentStreams(100),
+			},
+			expServerOpts: 3,
+			expBuiltOpts:  3,
+		},
+		"caller defined option": {
+			givenOpts: []GRPCOption{
+				func(opts *[]grpc.ServerOption) {
+					*opts = append(*opts, grpc.ConnectionTimeout(time.Second))
+				},
+				WithHealthService(),
+			},
+			expServerOpts: 1,
+			expBuiltOpts:  1,
+		},
+		"deadlines and concurrency limits": {
+			givenOpts: []GRPCOption{
+				WithDefaultDeadline(5 * time.Second),
+				WithMethodDeadline("/weather.WeatherService/GetWeatherInfo", time.Second),
+				WithMethodConcurrencyLimit("/weather.WeatherService/StreamWeather", 10),
+			},
+			expDeadlines: methodDeadlines{
+				defaultTimeout: 5 * time.Second,
+				methods:        map[string]time.Duration{"/weather.WeatherService/GetWeatherInfo": time.Second},
+			},
+			expConcurrency: map[string]int{"/weather.WeatherService/StreamWeather": 10},
+			expBuiltOpts:   2,
+		},
+		"zero concurrency limit is ignored": {
+			givenOpts: []GRPCOption{
+				WithMethodConcurrencyLimit("/weather.WeatherService/StreamWeather", 0),
+			},
+		},
+		"negative concurrency limit is ignored": {
+			givenOpts: []GRPCOption{
+				WithMethodConcurrencyLimit("/weather.WeatherService/StreamWeather", 10),
+				WithMethodConcurrencyLimit("/weather.WeatherService/GetWeatherInfo", -1),
+			},
+			expConcurrency: map[string]int{"/weather.WeatherService/StreamWeather": 10},
+			expBuiltOpts:   2,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given & When
+			cfg := newGRPCConfig(tc.givenOpts...)
+
+			// Then
+			require.Equal(t, tc.expKeepalive, cfg.keepaliveParams)
+			require.Len(t, cfg.serverOpts, tc.expServerOpts)
+			require.Equal(t, tc.expDeadlines, cfg.deadlines)
+			require.Equal(t, tc.expConcurrency, cfg.concurrencyLimits)
+			require.Len(t, cfg.buildServerOptions(), tc.expBuiltOpts)
+		})
+	}
+}
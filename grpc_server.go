@@ -6,14 +6,21 @@ import (
 	"fmt"
 	"net"
 	"os/signal"
+	"sort"
+	"strings"
 	"syscall"
 
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/viebiz/lit/monitoring"
 )
 
 type GRPCServer struct {
 	grpcServer *grpc.Server
 	addr       string
+	health     *grpcHealth
 }
 
 func NewGRPCServer(ctx context.Context, addr string) (GRPCServer, error) {
@@ -25,17 +32,25 @@ func NewGRPCServer(ctx context.Context, addr string) (GRPCServer, error) {
 }
 
 func NewGRPCServerWithOptions(ctx context.Context, addr string, opts ...GRPCOption) (GRPCServer, error) {
-	var serverOpts []grpc.ServerOption
-	for _, opt := range opts {
-		opt(&serverOpts)
-	}
+	cfg := newGRPCConfig(opts...)
 
-	grpcServer := grpc.NewServer(serverOpts...)
+	grpcServer := grpc.NewServer(cfg.buildServerOptions()...)
 
-	return GRPCServer{
+	srv := GRPCServer{
 		grpcServer: grpcServer,
 		addr:       addr,
-	}, nil
+	}
+
+	if cfg.healthEnabled {
+		srv.health = newGRPCHealth(monitoring.FromContext(ctx), cfg.healthChecks, cfg.healthCheckInterval)
+		healthpb.RegisterHealthServer(grpcServer, srv.health.server)
+	}
+
+	if cfg.reflectionEnabled {
+		reflection.Register(grpcServer)
+	}
+
+	return srv, nil
 }
 
 func (srv GRPCServer) Run() error {
@@ -65,6 +80,10 @@ func (srv GRPCServer) start(ctx context.Context) error {
 		}
 	}()
 
+	if srv.health != nil {
+		go srv.health.run(ctx, srv.serviceNames())
+	}
+
 	select {
 	case err := <-startupErr:
 		if !errors.Is(err, grpc.ErrServerStopped) {
@@ -81,5 +100,24 @@ func (srv GRPCServer) stop() {
 	fmt.Printf("attempting to shutdown gracefully\n")
 	defer fmt.Println("server shutdown successfully")
 
+	// Report NOT_SERVING first, so that clients stop sending new requests while draining
+	if srv.health != nil {
+		srv.health.shutdown()
+	}
+
 	srv.grpcServer.GracefulStop()
 }
+
+// serviceNames returns the registered application services, excluding health and reflection services
+func (srv GRPCServer) serviceNames() []string {
+	var names []string
+	for name := range srv.grpcServer.GetServiceInfo() {
+		if name == healthpb.Health_ServiceDesc.ServiceName || strings.HasPrefix(name, "grpc.reflection.") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
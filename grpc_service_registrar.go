@@ -11,3 +11,17 @@ type ServiceRegistrar interface {
 func (srv GRPCServer) Registrar() ServiceRegistrar {
 	return srv.grpcServer
 }
+
+// MultiRegistrar returns a ServiceRegistrar registering the services on all given registrars,
+// e.g. on both GRPCServer and GRPCGateway
+func MultiRegistrar(registrars ...ServiceRegistrar) ServiceRegistrar {
+	return multiRegistrar(registrars)
+}
+
+type multiRegistrar []ServiceRegistrar
+
+func (m multiRegistrar) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	for _, r := range m {
+		r.RegisterService(desc, impl)
+	}
+}
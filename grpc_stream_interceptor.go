@@ -2,14 +2,39 @@ package lit
 
 import (
 	"context"
+	"fmt"
+	"runtime/debug"
 
 	"google.golang.org/grpc"
+
+	"github.com/viebiz/lit/monitoring"
+	"github.com/viebiz/lit/monitoring/instrumentgrpc"
 )
 
-func streamServerInterceptor(ctx context.Context) grpc.StreamServerInterceptor {
-	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		// TODO: implement logic
+func streamServerInterceptor(rootCtx context.Context) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		// Start tracing for incoming stream call, the wrapped stream carries the monitor and span in its context
+		ss, endInstrumentation := instrumentgrpc.StartStreamIncomingCall(ss, monitoring.FromContext(rootCtx), info.FullMethod)
+		defer func() {
+			if p := recover(); p != nil {
+				rcvErr, ok := p.(error)
+				if !ok {
+					rcvErr = fmt.Errorf("%v", p)
+				}
+
+				monitoring.FromContext(ss.Context()).Errorf(rcvErr, "Caught a panic: %s", debug.Stack())
+				err = grpcDefaultInternalErr()
+				endInstrumentation(err)
+			}
+		}()
+
+		err = handler(srv, ss)
+
+		// Translate lit.Error to gRPC status error
+		err = toGRPCError(ss.Context(), err)
+
+		endInstrumentation(err)
 
-		return handler(srv, ss)
+		return err
 	}
 }
@@ -0,0 +1,140 @@
+package lit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/viebiz/lit/grpcclient/testdata"
+	"github.com/viebiz/lit/monitoring"
+	"github.com/viebiz/lit/monitoring/tracing/mocktracer"
+)
+
+func Test_streamServerInterceptor(t *testing.T) {
+	tp := mocktracer.Start()
+	defer tp.Stop()
+
+	type args struct {
+		givenHandler func(srv any, ss grpc.ServerStream) error
+		expErr       error
+		expEvents    int
+		expAttrs     []attribute.KeyValue
+	}
+	tcs := map[string]args{
+		"success": {
+			givenHandler: func(srv any, ss grpc.ServerStream) error {
+				// The monitor must be available in stream context
+				require.NotNil(t, monitoring.FromContext(ss.Context()))
+
+				req := new(testdata.WeatherRequest)
+				if err := ss.RecvMsg(req); err != nil {
+					return err
+				}
+				if err := ss.SendMsg(&testdata.WeatherDetail{Location: "Hive City, Necromunda"}); err != nil {
+					return err
+				}
+				return ss.SendMsg(&testdata.WeatherDetail{Location: "Macragge"})
+			},
+			expEvents: 3,
+			expAttrs: []attribute.KeyValue{
+				semconv.RPCSystemGRPC,
+				semconv.RPCService("weather.WeatherService"),
+				semconv.RPCMethod("StreamWeather"),
+				attribute.Int64("rpc.grpc.messages_sent", 2),
+				attribute.Int64("rpc.grpc.messages_received", 1),
+				semconv.RPCGRPCStatusCodeOk,
+			},
+		},
+		"handler error": {
+			givenHandler: func(srv any, ss grpc.ServerStream) error {
+				return status.Error(codes.NotFound, "weather not found")
+			},
+			expErr: status.Error(codes.NotFound, "weather not found"),
+			expAttrs: []attribute.KeyValue{
+				semconv.RPCSystemGRPC,
+				semconv.RPCService("weather.WeatherService"),
+				semconv.RPCMethod("StreamWeather"),
+				attribute.Int64("rpc.grpc.messages_sent", 0),
+				attribute.Int64("rpc.grpc.messages_received", 0),
+				semconv.RPCGRPCStatusCodeKey.Int(int(codes.NotFound)),
+			},
+		},
+		"panic": {
+			givenHandler: func(srv any, ss grpc.ServerStream) error {
+				panic("simulated panic")
+			},
+			expErr: status.Error(codes.Internal, "Something went wrong"),
+			expAttrs: []attribute.KeyValue{
+				semconv.RPCSystemGRPC,
+				semconv.RPCService("weather.WeatherService"),
+				semconv.RPCMethod("StreamWeather"),
+				attribute.Int64("rpc.grpc.messages_sent", 0),
+				attribute.Int64("rpc.grpc.messages_received", 0),
+				semconv.RPCGRPCStatusCodeKey.Int(int(codes.Internal)),
+			},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			tp.Reset()
+			logBuffer := bytes.NewBuffer(nil)
+			m, err := monitoring.New(monitoring.Config{Writer: logBuffer})
+			require.NoError(t, err)
+
+			intercept := streamServerInterceptor(monitoring.SetInContext(context.Background(), m))
+			info := &grpc.StreamServerInfo{
+				FullMethod:     testdata.WeatherService_StreamWeather_FullMethodName,
+				IsServerStream: true,
+			}
+
+			// When
+			err = intercept(nil, &fakeServerStream{ctx: context.Background()}, info, tc.givenHandler)
+
+			// Then
+			if tc.expErr != nil {
+				require.EqualError(t, err, tc.expErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			span := tp.GetLatestSpan()
+			require.Equal(t, "grpc.stream_incoming_call", span.Name)
+			require.ElementsMatch(t, tc.expAttrs, span.Attributes)
+			require.Len(t, span.Events, tc.expEvents)
+		})
+	}
+}
+
+// fakeServerStream is an in-memory grpc.ServerStream which returns a single request message
+type fakeServerStream struct {
+	grpc.ServerStream
+
+	ctx      context.Context
+	received bool
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream) SendMsg(any) error {
+	return nil
+}
+
+func (s *fakeServerStream) RecvMsg(any) error {
+	if s.received {
+		return io.EOF
+	}
+	s.received = true
+	return nil
+}
@@ -0,0 +1,167 @@
+package lit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/viebiz/lit/grpcclient"
+	"github.com/viebiz/lit/monitoring"
+	"github.com/viebiz/lit/monitoring/tracing/mocktracer"
+)
+
+const (
+	grpcTestBufferSize = 1024 * 1024
+	grpcTestTarget     = "passthrough:///bufnet"
+)
+
+// GRPCTestServer is a GRPCServer with all default interceptors running on an in-memory bufconn listener.
+// The server-side monitoring logs and spans are captured for assertions.
+// It overrides the global tracer provider, so tests using it must not run in parallel.
+type GRPCTestServer struct {
+	srv    GRPCServer
+	lis    *bufconn.Listener
+	cancel context.CancelFunc
+	tp     mocktracer.TracerProviderMock
+	logs   *syncBuffer
+}
+
+// NewGRPCServerForTest starts a GRPCServer with the services registered by register and returns a grpcclient.Conn dialled to it.
+// The client interceptors of grpcclient are included. Close must be called at the end of the test.
+//
+// Usage:
+//
+//	conn, ts, err := lit.NewGRPCServerForTest(func(r lit.ServiceRegistrar) {
+//		pb.RegisterWeatherServiceServer(r, impl)
+//	})
+//	require.NoError(t, err)
+//	defer ts.Close()
+//
+//	resp, err := pb.NewWeatherServiceClient(conn).GetWeatherInfo(ts.ContextWithToken(ctx, "token"), req)
+func NewGRPCServerForTest(register func(ServiceRegistrar), opts ...GRPCOption) (grpcclient.Conn, *GRPCTestServer, error) {
+	logs := &syncBuffer{}
+	m, err := monitoring.New(monitoring.Config{Writer: logs, LogLevel: "debug"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(monitoring.SetInContext(context.Background(), m))
+
+	srv, err := NewGRPCServerWithOptions(ctx, "bufnet", append([]GRPCOption{WithDefaultInterceptors(ctx)}, opts...)...)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	register(srv.Registrar())
+
+	ts := &GRPCTestServer{
+		srv:    srv,
+		lis:    bufconn.Listen(grpcTestBufferSize),
+		cancel: cancel,
+		tp:     mocktracer.Start(),
+		logs:   logs,
+	}
+
+	go func() {
+		_ = srv.grpcServer.Serve(ts.lis) // Stopped by Close
+	}()
+
+	if srv.health != nil {
+		go srv.health.run(ctx, srv.serviceNames())
+	}
+
+	conn, err := grpcclient.NewConnection(ctx, grpcTestTarget,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ts.lis.DialContext(ctx)
+		}),
+	)
+	if err != nil {
+		ts.Close()
+		return nil, nil, err
+	}
+
+	return conn, ts, nil
+}
+
+// Close stops the server and resets the global tracer provider
+func (ts *GRPCTestServer) Close() {
+	ts.cancel()
+	if ts.srv.health != nil {
+		ts.srv.health.shutdown()
+	}
+	ts.srv.grpcServer.Stop()
+	ts.tp.Stop()
+}
+
+// ContextWithMetadata returns the context with the key-value pairs appended to the outgoing metadata
+func (ts *GRPCTestServer) ContextWithMetadata(ctx context.Context, kv ...string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// ContextWithToken returns the context with the bearer token in the outgoing authorization metadata
+func (ts *GRPCTestServer) ContextWithToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// Logs returns the server-side log entries written so far
+func (ts *GRPCTestServer) Logs() []map[string]any {
+	var entries []map[string]any
+
+	scanner := bufio.NewScanner(bytes.NewReader(ts.logs.Bytes()))
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // Skip the non JSON lines
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// Spans returns the spans ended so far, in order
+func (ts *GRPCTestServer) Spans() []mocktracer.SpanStub {
+	return ts.tp.GetSpans()
+}
+
+// ResetCaptured clears the captured logs and spans, e.g. between test cases
+func (ts *GRPCTestServer) ResetCaptured() {
+	ts.logs.Reset()
+	ts.tp.Reset()
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes by the server goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return bytes.Clone(b.buf.Bytes())
+}
+
+func (b *syncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf.Reset()
+}
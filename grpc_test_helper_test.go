@@ -0,0 +1,110 @@
+package lit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/viebiz/lit/grpcclient"
+	"github.com/viebiz/lit/grpcclient/testdata"
+)
+
+func TestNewGRPCServerForTest(t *testing.T) {
+	type mockData struct {
+		outRes *testdata.WeatherResponse
+		outErr error
+	}
+	tcs := map[string]struct {
+		mockData  mockData
+		expRes    *testdata.WeatherResponse
+		expErr    error
+		expLogMsg string
+	}{
+		"success": {
+			mockData: mockData{
+				outRes: &testdata.WeatherResponse{WeatherDetails: []*testdata.WeatherDetail{{Location: "Macragge"}}},
+			},
+			expRes: &testdata.WeatherResponse{WeatherDetails: []*testdata.WeatherDetail{{Location: "Macragge"}}},
+		},
+		"lit error": {
+			mockData: mockData{
+				outRes: (*testdata.WeatherResponse)(nil),
+				outErr: HttpError{Status: http.StatusNotFound, Code: "weather_not_found", Desc: "Weather not found"},
+			},
+			expErr: grpcclient.StatusError{Status: http.StatusNotFound, Code: "weather_not_found", Desc: "Weather not found"},
+		},
+		"unexpected error": {
+			mockData: mockData{
+				outRes: (*testdata.WeatherResponse)(nil),
+				outErr: errors.New("connection refused"),
+			},
+			expErr:    grpcclient.StatusError{Status: http.StatusInternalServerError, Code: "internal_server_error", Desc: "Something went wrong"},
+			expLogMsg: "got unexpected error",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			weatherSvc := new(weatherService)
+			weatherSvc.On("GetWeatherInfo", mock.MatchedBy(func(ctx context.Context) bool {
+				md, _ := metadata.FromIncomingContext(ctx)
+				return len(md.Get("authorization")) == 1 && md.Get("authorization")[0] == "Bearer token" &&
+					len(md.Get("x-tenant-id")) == 1 && md.Get("x-tenant-id")[0] == "ultramar"
+			}), mock.Anything).Return(tc.mockData.outRes, tc.mockData.outErr)
+
+			conn, ts, err := NewGRPCServerForTest(func(r ServiceRegistrar) {
+				testdata.RegisterWeatherServiceServer(r, weatherSvc)
+			})
+			require.NoError(t, err)
+			defer ts.Close()
+
+			ctx := ts.ContextWithToken(context.Background(), "token")
+			ctx = ts.ContextWithMetadata(ctx, "x-tenant-id", "ultramar")
+
+			// When
+			res, err := testdata.NewWeatherServiceClient(conn).GetWeatherInfo(ctx, &testdata.WeatherRequest{Location: "Macragge"})
+
+			// Then
+			if tc.expErr != nil {
+				var statusErr grpcclient.StatusError
+				require.ErrorAs(t, err, &statusErr)
+				require.EqualError(t, statusErr, tc.expErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.True(t, proto.Equal(tc.expRes, res))
+			}
+			weatherSvc.AssertExpectations(t)
+
+			var serverSpans int
+			for _, span := range ts.Spans() {
+				if span.Name == "grpc.unary_incoming_call" {
+					serverSpans++
+				}
+			}
+			require.Equal(t, 1, serverSpans)
+
+			if tc.expLogMsg != "" {
+				var found bool
+				for _, entry := range ts.Logs() {
+					if msg, _ := entry["msg"].(string); strings.HasPrefix(msg, tc.expLogMsg) {
+						found = true
+					}
+				}
+				require.True(t, found, "log %q not found in %v", tc.expLogMsg, ts.Logs())
+			}
+
+			// Captured data can be cleared between assertions
+			ts.ResetCaptured()
+			require.Empty(t, ts.Spans())
+			require.Empty(t, ts.Logs())
+		})
+	}
+}
@@ -29,14 +29,16 @@ func unaryServerInterceptor(rootCtx context.Context) grpc.UnaryServerInterceptor
 				}
 
 				monitoring.FromContext(ctx).Errorf(rcvErr, "Caught a panic: %s", debug.Stack())
-				endInstrumentation(rcvErr)
-
-				err = ErrDefaultInternal
+				err = grpcDefaultInternalErr()
+				endInstrumentation(err)
 			}
 		}()
 
 		rs, err = handler(ctx, req)
 
+		// Translate lit.Error to gRPC status error
+		err = toGRPCError(ctx, err)
+
 		endInstrumentation(err)
 
 		logIncomingGRPCCall(ctx, reqMeta, rs)
@@ -2,12 +2,65 @@ package grpcclient
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
 // NewUnauthenticatedConnection initializes and returns a new unauthenticated grpc clientConn for unary calls
-func NewUnauthenticatedConnection(ctx context.Context, addr string) (Conn, error) {
-	return initUnaryClient(ctx, addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func NewUnauthenticatedConnection(ctx context.Context, addr string, opts ...grpc.DialOption) (Conn, error) {
+	return initUnaryClient(ctx, addr, append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)...)
+}
+
+// NewConnection initializes and returns a new grpc clientConn with the given dial options, e.g. transport credentials or a custom dialer, and ConnOption
+// The client interceptors are always included
+func NewConnection(ctx context.Context, addr string, opts ...grpc.DialOption) (Conn, error) {
+	return initUnaryClient(ctx, addr, opts...)
+}
+
+// NewTLSConnection initializes and returns a new grpc clientConn secured by TLS
+// The server certificate is verified against the system root CAs when tlsCfg is nil
+func NewTLSConnection(ctx context.Context, addr string, tlsCfg *tls.Config, opts ...grpc.DialOption) (Conn, error) {
+	return initUnaryClient(ctx, addr, append([]grpc.DialOption{grpc.WithTransportCredentials(newTLSCredentials(tlsCfg))}, opts...)...)
+}
+
+// NewMTLSConnection initializes and returns a new grpc clientConn secured by mutual TLS, presenting the client certificate
+// The server certificate is verified against rootCAs, or the system root CAs when rootCAs is nil
+func NewMTLSConnection(ctx context.Context, addr string, cert tls.Certificate, rootCAs *x509.CertPool, opts ...grpc.DialOption) (Conn, error) {
+	return NewTLSConnection(ctx, addr, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      rootCAs,
+		MinVersion:   tls.VersionTLS12,
+	}, opts...)
+}
+
+// NewClientCredentialsConnection initializes and returns a new grpc clientConn secured by TLS,
+// authenticating each call with an OAuth2 client credentials access token.
+// The token is cached and refreshed before it expires.
+func NewClientCredentialsConnection(ctx context.Context, addr string, tlsCfg *tls.Config, cfg ClientCredentialsConfig, opts ...grpc.DialOption) (Conn, error) {
+	perRPC, err := newClientCredentialsToken(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTLSConnection(ctx, addr, tlsCfg, append([]grpc.DialOption{grpc.WithPerRPCCredentials(perRPC)}, opts...)...)
+}
+
+// NewTokenForwardingConnection initializes and returns a new grpc clientConn secured by TLS,
+// forwarding the bearer token of the caller to the downstream service on each call.
+// The token is taken from iam.SetAccessTokenInContext, which is set by the guard middlewares and interceptors,
+// or else from the incoming gRPC metadata.
+func NewTokenForwardingConnection(ctx context.Context, addr string, tlsCfg *tls.Config, opts ...grpc.DialOption) (Conn, error) {
+	return NewTLSConnection(ctx, addr, tlsCfg, append([]grpc.DialOption{grpc.WithPerRPCCredentials(forwardedToken{})}, opts...)...)
+}
+
+func newTLSCredentials(tlsCfg *tls.Config) credentials.TransportCredentials {
+	if tlsCfg == nil {
+		tlsCfg = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	return credentials.NewTLS(tlsCfg)
 }
@@ -0,0 +1,119 @@
+package grpcclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/viebiz/lit/monitoring"
+)
+
+// ErrCircuitOpen is returned without calling the downstream service while the circuit is open, with the Unavailable
+// gRPC status so that it is told apart from the failures of the service
+var ErrCircuitOpen = StatusError{
+	Status:     http.StatusServiceUnavailable,
+	Code:       "circuit_open",
+	Desc:       "Circuit breaker is open",
+	grpcStatus: status.New(codes.Unavailable, "Circuit breaker is open"),
+}
+
+// breakerFailureCodes are the status codes counted as failures, the others are valid responses of a healthy service
+var breakerFailureCodes = map[codes.Code]bool{
+	codes.Unknown:           true,
+	codes.DeadlineExceeded:  true,
+	codes.ResourceExhausted: true,
+	codes.Internal:          true,
+	codes.Unavailable:       true,
+	codes.DataLoss:          true,
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+type circuitBreakerConfig struct {
+	threshold    int
+	openDuration time.Duration
+}
+
+// circuitBreaker opens after consecutive failures and lets a single probe call through after the open duration
+type circuitBreaker struct {
+	cfg circuitBreakerConfig
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+func newCircuitBreaker(cfg circuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{cfg: cfg, now: time.Now}
+}
+
+// allow reports whether the call can be sent
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.cfg.openDuration {
+			return false
+		}
+		b.state = circuitHalfOpen // Let this call through as the probe
+		return true
+	case circuitHalfOpen:
+		return false // The probe is in flight
+	default:
+		return true
+	}
+}
+
+// record updates the state with the result of the call, returning the new state if it changed
+func (b *circuitBreaker) record(failed bool) (circuitState, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prev := b.state
+	if !failed {
+		b.state, b.failures = circuitClosed, 0
+		return b.state, prev != b.state
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.cfg.threshold {
+		b.state, b.openedAt = circuitOpen, b.now()
+	}
+
+	return b.state, prev != b.state
+}
+
+func (b *circuitBreaker) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !b.allow() {
+			return ErrCircuitOpen
+		}
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if state, changed := b.record(err != nil && breakerFailureCodes[status.Code(err)]); changed {
+			switch state {
+			case circuitOpen:
+				monitoring.FromContext(ctx).Warnf("Circuit breaker of %s opened after %d consecutive failures", cc.Target(), b.cfg.threshold)
+			case circuitClosed:
+				monitoring.FromContext(ctx).Infof("Circuit breaker of %s closed", cc.Target())
+			}
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,123 @@
+package grpcclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/viebiz/lit/grpcclient/testdata"
+)
+
+func TestCircuitBreaker_unaryInterceptor(t *testing.T) {
+	type call struct {
+		elapsed time.Duration
+		outErr  error
+		expErr  error
+		expSent bool
+	}
+	unavailable := status.Error(codes.Unavailable, "weather station is down")
+	notFound := status.Error(codes.NotFound, "weather not found")
+
+	tcs := map[string]struct {
+		givenCalls []call
+	}{
+		"opens after consecutive failures": {
+			givenCalls: []call{
+				{outErr: unavailable, expErr: unavailable, expSent: true},
+				{outErr: unavailable, expErr: unavailable, expSent: true},
+				{expErr: ErrCircuitOpen},
+				{elapsed: 5 * time.Second, expErr: ErrCircuitOpen},
+			},
+		},
+		"success resets the failures": {
+			givenCalls: []call{
+				{outErr: unavailable, expErr: unavailable, expSent: true},
+				{expSent: true},
+				{outErr: unavailable, expErr: unavailable, expSent: true},
+				{expSent: true},
+			},
+		},
+		"business errors are not failures": {
+			givenCalls: []call{
+				{outErr: notFound, expErr: notFound, expSent: true},
+				{outErr: notFound, expErr: notFound, expSent: true},
+				{expSent: true},
+			},
+		},
+		"probe success closes the circuit": {
+			givenCalls: []call{
+				{outErr: unavailable, expErr: unavailable, expSent: true},
+				{outErr: unavailable, expErr: unavailable, expSent: true},
+				{elapsed: 10 * time.Second, expSent: true},
+				{expSent: true},
+			},
+		},
+		"probe failure opens the circuit again": {
+			givenCalls: []call{
+				{outErr: unavailable, expErr: unavailable, expSent: true},
+				{outErr: unavailable, expErr: unavailable, expSent: true},
+				{elapsed: 10 * time.Second, outErr: unavailable, expErr: unavailable, expSent: true},
+				{expErr: ErrCircuitOpen},
+			},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			now := time.Now()
+			breaker := newCircuitBreaker(circuitBreakerConfig{threshold: 2, openDuration: 10 * time.Second})
+			breaker.now = func() time.Time { return now }
+			intercept := breaker.unaryInterceptor()
+			cc, err := grpc.NewClient("passthrough:///weather", grpc.WithTransportCredentials(insecure.NewCredentials()))
+			require.NoError(t, err)
+
+			for idx, c := range tc.givenCalls {
+				now = now.Add(c.elapsed)
+				var sent bool
+				invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+					sent = true
+					return c.outErr
+				}
+
+				// When
+				err := intercept(context.Background(), "/weather.WeatherService/GetWeatherInfo", nil, nil, cc, invoker)
+
+				// Then
+				if c.expErr != nil {
+					require.ErrorIs(t, err, c.expErr, "call %d", idx)
+				} else {
+					require.NoError(t, err, "call %d", idx)
+				}
+				require.Equal(t, c.expSent, sent, "call %d", idx)
+			}
+		})
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	// Given
+	conn, err := NewUnauthenticatedConnection(context.Background(), "localhost:1", WithCircuitBreaker(1, time.Minute))
+	require.NoError(t, err)
+
+	// When
+	errs := make([]error, 2)
+	for idx := range errs {
+		errs[idx] = conn.Invoke(context.Background(), "/weather.WeatherService/GetWeatherInfo", &testdata.WeatherRequest{}, &testdata.WeatherResponse{})
+	}
+
+	// Then
+	var statusErr StatusError
+	require.ErrorAs(t, errs[0], &statusErr)
+	require.Equal(t, "unavailable", statusErr.Code)
+	require.ErrorIs(t, errs[1], ErrCircuitOpen)
+	require.Equal(t, codes.Unavailable, status.Code(errs[1]))
+}
@@ -10,14 +10,22 @@ import (
 )
 
 func initUnaryClient(ctx context.Context, addr string, opts ...grpc.DialOption) (Conn, error) {
-	svcInfo := monitoring.NewExternalServiceInfo(addr)
-
-	conn, err := grpc.NewClient(addr,
-		append(
-			commonUnaryClientDialOptions(svcInfo),
-			opts...,
-		)...,
-	)
+	cfg := connConfig{}
+	for _, opt := range opts {
+		if connOpt, ok := opt.(ConnOption); ok {
+			connOpt.apply(&cfg)
+			continue
+		}
+		cfg.dialOpts = append(cfg.dialOpts, opt)
+	}
+
+	dialOpts, err := cfg.buildDialOptions(monitoring.NewExternalServiceInfo(trimScheme(addr)))
+	if err != nil {
+		return nil, err
+	}
+
+	target, resolverOpts := cfg.target(addr)
+	conn, err := grpc.NewClient(target, append(dialOpts, resolverOpts...)...)
 	if err != nil {
 		return nil, pkgerrors.WithStack(err)
 	}
@@ -39,15 +47,46 @@ func (u clientConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method
 	return u.conn.NewStream(ctx, desc, method, opts...)
 }
 
-func commonUnaryClientDialOptions(svcInfo monitoring.ExternalServiceInfo) []grpc.DialOption {
-	return []grpc.DialOption{
-		// Explicitly disabling this as according to doc: Retry support is currently disabled by default, but will be enabled by default in the future.
-		grpc.WithDisableRetry(),
+// buildDialOptions returns the grpc.DialOption of the config, the interceptors are chained in order:
+// tracing and logging, default timeout, circuit breaker then hedging, so a call is traced once with all its attempts.
+// Streams are traced as well, the other interceptors only apply to unary calls.
+func (cfg connConfig) buildDialOptions(svcInfo monitoring.ExternalServiceInfo) ([]grpc.DialOption, error) {
+	opts := []grpc.DialOption{
 		grpc.WithDefaultCallOptions(
 			externalServiceInfoOption{info: svcInfo}, // Pass service information for tracing.
 		),
-		grpc.WithChainUnaryInterceptor(unaryClientInterceptor),
+		grpc.WithStatsHandler(attemptStatsHandler{}),
+	}
+
+	svcConfig, err := cfg.serviceConfig()
+	if err != nil {
+		return nil, err
 	}
+	if svcConfig != "" {
+		opts = append(opts, grpc.WithDefaultServiceConfig(svcConfig))
+	}
+	if cfg.retryPolicy == nil {
+		// Explicitly disabling this as according to doc: Retry support is currently disabled by default, but will be enabled by default in the future.
+		opts = append(opts, grpc.WithDisableRetry())
+	}
+
+	interceptors := []grpc.UnaryClientInterceptor{unaryClientInterceptor}
+	if cfg.defaultTimeout > 0 {
+		interceptors = append(interceptors, defaultTimeoutInterceptor(cfg.defaultTimeout))
+	}
+	if cfg.breaker != nil {
+		interceptors = append(interceptors, newCircuitBreaker(*cfg.breaker).unaryInterceptor())
+	}
+	if cfg.hedgingPolicy != nil {
+		if err := cfg.hedgingPolicy.validate(); err != nil {
+			return nil, err
+		}
+		interceptors = append(interceptors, hedgingInterceptor(*cfg.hedgingPolicy, cfg.hedgedMethods))
+	}
+	opts = append(opts, grpc.WithChainUnaryInterceptor(interceptors...))
+	opts = append(opts, grpc.WithChainStreamInterceptor(streamClientInterceptor))
+
+	return append(opts, cfg.dialOpts...), nil
 }
 
 // externalServiceInfoOption to keeps the external service info in UnaryClient for purpose monitor
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 
@@ -28,14 +29,19 @@ func unaryClientInterceptor(
 	}
 
 	ctx, end := instrumentgrpc.StartGRPCUnaryCallSegment(ctx, extSvcInfo.info, method)
+	ctx = withAttemptCounter(ctx) // Retries and hedged attempts are recorded as span events
 	defer func() {
 		end(err)
 	}()
 
+	// The request is logged after the call, so the chosen backend is known
+	var p peer.Peer
+	err = invoker(ctx, method, req, reply, clientConn, append(opts, grpc.Peer(&p))...)
+	ctx = instrumentgrpc.RecordGRPCPeer(ctx, p.Addr)
 	logRequestBody(ctx, req)
 
-	if err = invoker(ctx, method, req, reply, clientConn, opts...); err != nil {
-		return err
+	if err != nil {
+		return ConvertError(err) // Surface downstream gRPC status as lit.Error
 	}
 
 	return nil
@@ -60,3 +66,54 @@ func serializeProtoMessage(req any) string {
 
 	return string(b)
 }
+
+func streamClientInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	clientConn *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	var extSvcInfo externalServiceInfoOption
+	for _, opt := range opts {
+		if v, ok := opt.(externalServiceInfoOption); ok {
+			extSvcInfo = v
+			continue
+		}
+	}
+
+	ctx, wrap := instrumentgrpc.StartGRPCStreamCallSegment(ctx, extSvcInfo.info, method)
+	ctx = withAttemptCounter(ctx)
+
+	cs, err := streamer(ctx, desc, clientConn, method, opts...)
+	if err != nil {
+		monitoring.FromContext(ctx).Infof("grpc.outgoing_stream")
+		wrap(nil, desc, err)
+		return nil, ConvertError(err)
+	}
+
+	if p, ok := peer.FromContext(cs.Context()); ok {
+		ctx = instrumentgrpc.RecordGRPCPeer(ctx, p.Addr)
+	}
+	monitoring.FromContext(ctx).Infof("grpc.outgoing_stream")
+
+	return &clientStream{ClientStream: wrap(cs, desc, nil)}, nil
+}
+
+// clientStream surfaces downstream gRPC status of the stream as lit.Error, same as unary calls
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(msg any) error {
+	return ConvertError(s.ClientStream.SendMsg(msg))
+}
+
+func (s *clientStream) RecvMsg(msg any) error {
+	return ConvertError(s.ClientStream.RecvMsg(msg))
+}
+
+func (s *clientStream) CloseSend() error {
+	return ConvertError(s.ClientStream.CloseSend())
+}
@@ -4,17 +4,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/viebiz/lit/testutil"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/viebiz/lit/grpcclient/testdata"
 	"github.com/viebiz/lit/monitoring"
+	"github.com/viebiz/lit/monitoring/tracing/mocktracer"
 )
 
 func TestClientConn_Invoke(t *testing.T) {
@@ -76,7 +87,7 @@ func TestClientConn_Invoke(t *testing.T) {
 			expLog: []map[string]string{
 				{"level": "INFO", "ts": "2025-02-23T18:18:48.186+0700", "msg": "Sentry DSN not provided. Not using Sentry Error Reporting", "server.name": "lightning", "environment": "dev", "version": "1.0.0"},
 				{"level": "INFO", "ts": "2025-02-23T18:18:48.186+0700", "msg": "OTelExporter URL not provided. Not using Distributed Tracing", "server.name": "lightning", "environment": "dev", "version": "1.0.0"},
-				{"level": "INFO", "ts": "2025-02-23T18:18:48.186+0700", "msg": "grpc.outgoing_request", "grpc.request": `{"date":"M41.993.32"}`, "outgoing_span_id": "0000000000000000", "outgoing_trace_id": "00000000000000000000000000000000", "rpc.method": "GetWeatherInfo", "rpc.service": "weather.WeatherService", "rpc.system": "grpc", "server.address": "localhost:50052", "server.name": "lightning", "environment": "dev", "version": "1.0.0"},
+				{"level": "INFO", "ts": "2025-02-23T18:18:48.186+0700", "msg": "grpc.outgoing_request", "grpc.request": `{"date":"M41.993.32"}`, "network.peer.address": "127.0.0.1:50052", "outgoing_span_id": "0000000000000000", "outgoing_trace_id": "00000000000000000000000000000000", "rpc.method": "GetWeatherInfo", "rpc.service": "weather.WeatherService", "rpc.system": "grpc", "server.address": "localhost:50052", "server.name": "lightning", "environment": "dev", "version": "1.0.0"},
 			},
 		},
 	}
@@ -163,3 +174,138 @@ func (s *weatherService) GetWeatherInfo(ctx context.Context, req *testdata.Weath
 
 	return args.Get(0).(*testdata.WeatherResponse), args.Error(1)
 }
+
+func (s *weatherService) StreamWeather(req *testdata.WeatherRequest, stream grpc.ServerStreamingServer[testdata.WeatherDetail]) error {
+	args := s.Called(stream.Context(), req)
+	for _, detail := range args.Get(0).([]*testdata.WeatherDetail) {
+		if err := stream.Send(detail); err != nil {
+			return err
+		}
+	}
+	if wait, _ := args.Get(2).(chan struct{}); wait != nil {
+		<-wait // Keep the stream open
+	}
+
+	return args.Error(1)
+}
+
+func TestClientConn_NewStream(t *testing.T) {
+	tp := mocktracer.Start()
+	defer tp.Stop()
+
+	details := []*testdata.WeatherDetail{
+		{Location: "Hive City, Necromunda", Temperature: 42.7},
+		{Location: "Macragge's Northern Hemisphere", Temperature: -20.5},
+	}
+
+	type mockData struct {
+		outDetails []*testdata.WeatherDetail
+		outErr     error
+	}
+	tcs := map[string]struct {
+		mockData    mockData
+		givenCancel bool
+		expReceived int
+		expErr      error
+		expSpanErr  string
+	}{
+		"success": {
+			mockData:    mockData{outDetails: details},
+			expReceived: 2,
+		},
+		"error - downstream error": {
+			mockData:    mockData{outDetails: details[:1], outErr: status.Error(codes.NotFound, "weather not found")},
+			expReceived: 1,
+			expErr:      StatusError{Status: 404, Code: "not_found", Desc: "weather not found"},
+			expSpanErr:  "rpc error: code = NotFound desc = weather not found",
+		},
+		"error - context canceled": {
+			mockData:    mockData{outDetails: details[:1]},
+			givenCancel: true,
+			expReceived: 1,
+			expErr:      StatusError{Status: 499, Code: "canceled", Desc: "context canceled"},
+			expSpanErr:  "rpc error: code = Canceled desc = context canceled",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			tp.Reset()
+
+			var wait chan struct{}
+			if tc.givenCancel {
+				wait = make(chan struct{})
+				defer close(wait)
+			}
+
+			weatherSvc := new(weatherService)
+			weatherSvc.On("StreamWeather", mock.Anything, mock.Anything).Return(tc.mockData.outDetails, tc.mockData.outErr, wait)
+			addr := startWeatherServer(t, weatherSvc)
+
+			conn, err := NewUnauthenticatedConnection(context.Background(), addr)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// When
+			stream, err := testdata.NewWeatherServiceClient(conn).StreamWeather(ctx, &testdata.WeatherRequest{Location: "Segmentum Solar"})
+			require.NoError(t, err)
+
+			var received int
+			for {
+				_, err = stream.Recv()
+				if err != nil {
+					break
+				}
+				received++
+				if tc.givenCancel {
+					cancel()
+				}
+			}
+
+			// Then
+			if tc.expErr != nil {
+				require.EqualError(t, err, tc.expErr.Error())
+			} else {
+				require.ErrorIs(t, err, io.EOF)
+			}
+			require.Equal(t, tc.expReceived, received)
+
+			require.Eventually(t, func() bool { return len(tp.GetSpans()) == 1 }, time.Second, 10*time.Millisecond)
+			span := tp.GetLatestSpan()
+			require.Equal(t, "grpc.stream_outgoing_call", span.Name)
+			require.Equal(t, trace.SpanKindClient, span.SpanKind)
+			require.Contains(t, span.Attributes, attribute.Int64("rpc.grpc.messages_sent", 1))
+			require.Contains(t, span.Attributes, attribute.Int64("rpc.grpc.messages_received", int64(tc.expReceived)))
+			if tc.expSpanErr != "" {
+				require.Equal(t, otelcodes.Error, span.Status.Code)
+				require.Equal(t, tc.expSpanErr, span.Status.Description)
+			} else {
+				require.Equal(t, otelcodes.Unset, span.Status.Code)
+			}
+
+			var sentEvents, receivedEvents, closeSendEvents int
+			for _, event := range span.Events {
+				switch {
+				case event.Name == "close_send":
+					closeSendEvents++
+				case event.Name == "message" && slices.Contains(event.Attributes, semconv.RPCMessageTypeSent):
+					sentEvents++
+				case event.Name == "message" && slices.Contains(event.Attributes, semconv.RPCMessageTypeReceived):
+					receivedEvents++
+				}
+			}
+			require.Equal(t, 1, sentEvents)
+			require.Equal(t, 1, closeSendEvents) // Server streaming client closes send after the request
+			require.Equal(t, tc.expReceived, receivedEvents)
+
+			// Trace context is propagated to the server
+			weatherSvc.AssertCalled(t, "StreamWeather", mock.MatchedBy(func(ctx context.Context) bool {
+				md, _ := metadata.FromIncomingContext(ctx)
+				return len(md.Get("traceparent")) == 1
+			}), mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/viebiz/lit/iam"
+	"github.com/viebiz/lit/internal/oauth2"
+)
+
+const (
+	metadataAuthorization = "authorization"
+	bearerPrefix          = "Bearer"
+
+	defaultTokenRefreshBefore = time.Minute
+	defaultTokenTimeout       = 10 * time.Second
+)
+
+var (
+	ErrMissingTokenURL      = errors.New("missing token url")
+	ErrMissingClientID      = errors.New("missing client id")
+	ErrInvalidTokenResponse = oauth2.ErrInvalidTokenResponse
+)
+
+// ClientCredentialsConfig is the configuration of the OAuth2 client credentials grant
+// Refer https://datatracker.ietf.org/doc/html/rfc6749#section-4.4
+type ClientCredentialsConfig struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// Audience is sent as audience parameter when it is not empty, as required by some providers e.g. Auth0
+	Audience string
+	// RefreshBefore is how long before the expiry the token is refreshed, at most half of the token lifetime,
+	// default is 1 minute. The tokens without expires_in are refreshed as if they lived 5 minutes
+	RefreshBefore time.Duration
+	// HTTPClient is used to request the token, default is http.Client with 10s timeout
+	HTTPClient *http.Client
+}
+
+// clientCredentialsToken is a credentials.PerRPCCredentials fetching and caching the OAuth2 client credentials access token
+type clientCredentialsToken struct {
+	cfg ClientCredentialsConfig
+	now func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+func newClientCredentialsToken(cfg ClientCredentialsConfig) (*clientCredentialsToken, error) {
+	if cfg.TokenURL == "" {
+		return nil, ErrMissingTokenURL
+	}
+	if cfg.ClientID == "" {
+		return nil, ErrMissingClientID
+	}
+	if cfg.RefreshBefore <= 0 {
+		cfg.RefreshBefore = defaultTokenRefreshBefore
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: defaultTokenTimeout}
+	}
+
+	return &clientCredentialsToken{cfg: cfg, now: time.Now}, nil
+}
+
+func (c *clientCredentialsToken) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	token, err := c.getToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{metadataAuthorization: bearerPrefix + " " + token}, nil
+}
+
+func (c *clientCredentialsToken) RequireTransportSecurity() bool {
+	return true
+}
+
+// getToken returns the cached token, or requests a new one when it is about to expire
+// The lock is held while requesting, so concurrent calls share the same request
+func (c *clientCredentialsToken) getToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && c.now().Before(c.refreshAt) {
+		return c.token, nil
+	}
+
+	token, expiresIn, err := c.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.token = token
+	c.refreshAt = c.now().Add(oauth2.ReuseDuration(expiresIn, c.cfg.RefreshBefore))
+
+	return c.token, nil
+}
+
+func (c *clientCredentialsToken) requestToken(ctx context.Context) (string, time.Duration, error) {
+	return oauth2.RequestToken(ctx, c.cfg.HTTPClient, oauth2.TokenRequest{
+		TokenURL:     c.cfg.TokenURL,
+		Scopes:       c.cfg.Scopes,
+		Audience:     c.cfg.Audience,
+		Authenticate: oauth2.BasicAuth(c.cfg.ClientID, c.cfg.ClientSecret),
+	})
+}
+
+// forwardedToken is a credentials.PerRPCCredentials forwarding the bearer token of the caller
+type forwardedToken struct{}
+
+func (forwardedToken) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	if token := iam.GetAccessTokenFromContext(ctx); token != "" {
+		return map[string]string{metadataAuthorization: bearerPrefix + " " + token}, nil
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(metadataAuthorization); len(values) > 0 && strings.HasPrefix(values[0], bearerPrefix+" ") {
+			return map[string]string{metadataAuthorization: values[0]}, nil
+		}
+	}
+
+	return nil, nil // Nothing to forward, e.g. background jobs
+}
+
+func (forwardedToken) RequireTransportSecurity() bool {
+	return true
+}
+
+var (
+	_ credentials.PerRPCCredentials = (*clientCredentialsToken)(nil)
+	_ credentials.PerRPCCredentials = forwardedToken{}
+)
@@ -0,0 +1,385 @@
+package grpcclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/viebiz/lit/grpcclient/testdata"
+	"github.com/viebiz/lit/iam"
+)
+
+func TestClientCredentialsToken_GetRequestMetadata(t *testing.T) {
+	type tokenResponse struct {
+		status int
+		body   string
+	}
+	tcs := map[string]struct {
+		givenResponses []tokenResponse
+		givenElapsed   time.Duration
+		expMetadata    []map[string]string
+		expRequests    int
+		expErr         error
+	}{
+		"cached token": {
+			givenResponses: []tokenResponse{
+				{status: http.StatusOK, body: `{"access_token":"token-1","token_type":"Bearer","expires_in":3600}`},
+			},
+			givenElapsed: 30 * time.Minute,
+			expMetadata: []map[string]string{
+				{"authorization": "Bearer token-1"},
+				{"authorization": "Bearer token-1"},
+			},
+			expRequests: 1,
+		},
+		"refresh before expiry": {
+			givenResponses: []tokenResponse{
+				{status: http.StatusOK, body: `{"access_token":"token-1","token_type":"bearer","expires_in":3600}`},
+				{status: http.StatusOK, body: `{"access_token":"token-2","token_type":"bearer","expires_in":3600}`},
+			},
+			givenElapsed: 59*time.Minute + 30*time.Second,
+			expMetadata: []map[string]string{
+				{"authorization": "Bearer token-1"},
+				{"authorization": "Bearer token-2"},
+			},
+			expRequests: 2,
+		},
+		"no expires_in": {
+			givenResponses: []tokenResponse{
+				{status: http.StatusOK, body: `{"access_token":"token-1","token_type":"Bearer"}`},
+			},
+			givenElapsed: time.Minute,
+			expMetadata: []map[string]string{
+				{"authorization": "Bearer token-1"},
+				{"authorization": "Bearer token-1"},
+				{"authorization": "Bearer token-1"},
+			},
+			expRequests: 1,
+		},
+		"expires_in shorter than refresh before": {
+			givenResponses: []tokenResponse{
+				{status: http.StatusOK, body: `{"access_token":"token-1","token_type":"Bearer","expires_in":30}`},
+			},
+			givenElapsed: 5 * time.Second,
+			expMetadata: []map[string]string{
+				{"authorization": "Bearer token-1"},
+				{"authorization": "Bearer token-1"},
+				{"authorization": "Bearer token-1"},
+			},
+			expRequests: 1,
+		},
+		"error - unauthorized client": {
+			givenResponses: []tokenResponse{
+				{status: http.StatusUnauthorized, body: `{"error":"invalid_client"}`},
+			},
+			expRequests: 1,
+			expErr:      errors.New(`invalid token response: status 401, body {"error":"invalid_client"}`),
+		},
+		"error - missing access token": {
+			givenResponses: []tokenResponse{
+				{status: http.StatusOK, body: `{"token_type":"Bearer","expires_in":3600}`},
+			},
+			expRequests: 1,
+			expErr:      errors.New("invalid token response: missing bearer access token"),
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			var requests int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+				require.Equal(t, "weather.read weather.write", r.PostForm.Get("scope"))
+				require.Equal(t, "https://weather.imperium", r.PostForm.Get("audience"))
+				clientID, secret, ok := r.BasicAuth()
+				require.True(t, ok)
+				require.Equal(t, "mechanicus", clientID)
+				require.Equal(t, "omnissiah", secret)
+
+				rs := tc.givenResponses[requests]
+				requests++
+				w.WriteHeader(rs.status)
+				_, _ = w.Write([]byte(rs.body))
+			}))
+			defer srv.Close()
+
+			creds, err := newClientCredentialsToken(ClientCredentialsConfig{
+				TokenURL:     srv.URL,
+				ClientID:     "mechanicus",
+				ClientSecret: "omnissiah",
+				Scopes:       []string{"weather.read", "weather.write"},
+				Audience:     "https://weather.imperium",
+			})
+			require.NoError(t, err)
+
+			now := time.Now()
+			creds.now = func() time.Time { return now }
+
+			// When
+			var mds []map[string]string
+			for range tc.expMetadata {
+				md, err := creds.GetRequestMetadata(context.Background())
+				require.NoError(t, err)
+				mds = append(mds, md)
+				now = now.Add(tc.givenElapsed)
+			}
+			if tc.expErr != nil {
+				_, err = creds.GetRequestMetadata(context.Background())
+			}
+
+			// Then
+			if tc.expErr != nil {
+				require.EqualError(t, err, tc.expErr.Error())
+				require.ErrorIs(t, err, ErrInvalidTokenResponse)
+			} else {
+				require.Equal(t, tc.expMetadata, mds)
+			}
+			require.Equal(t, tc.expRequests, requests)
+			require.True(t, creds.RequireTransportSecurity())
+		})
+	}
+}
+
+func TestNewClientCredentialsToken(t *testing.T) {
+	tcs := map[string]struct {
+		givenCfg ClientCredentialsConfig
+		expErr   error
+	}{
+		"valid": {
+			givenCfg: ClientCredentialsConfig{TokenURL: "https://auth.imperium/oauth/token", ClientID: "mechanicus"},
+		},
+		"error - missing token url": {
+			givenCfg: ClientCredentialsConfig{ClientID: "mechanicus"},
+			expErr:   ErrMissingTokenURL,
+		},
+		"error - missing client id": {
+			givenCfg: ClientCredentialsConfig{TokenURL: "https://auth.imperium/oauth/token"},
+			expErr:   ErrMissingClientID,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			creds, err := newClientCredentialsToken(tc.givenCfg)
+
+			// Then
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, defaultTokenRefreshBefore, creds.cfg.RefreshBefore)
+			require.NotNil(t, creds.cfg.HTTPClient)
+		})
+	}
+}
+
+func TestForwardedToken_GetRequestMetadata(t *testing.T) {
+	tcs := map[string]struct {
+		givenCtx    context.Context
+		expMetadata map[string]string
+	}{
+		"token in context": {
+			givenCtx: iam.SetAccessTokenInContext(
+				metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer incoming-token")),
+				"context-token",
+			),
+			expMetadata: map[string]string{"authorization": "Bearer context-token"},
+		},
+		"token in incoming metadata": {
+			givenCtx:    metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer incoming-token")),
+			expMetadata: map[string]string{"authorization": "Bearer incoming-token"},
+		},
+		"not bearer token": {
+			givenCtx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Basic dXNlcjpwYXNz")),
+		},
+		"no token": {
+			givenCtx: context.Background(),
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			md, err := forwardedToken{}.GetRequestMetadata(tc.givenCtx)
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, tc.expMetadata, md)
+		})
+	}
+}
+
+func TestNewSecureConnections(t *testing.T) {
+	caCert, serverCert, clientCert := newTestCertificates(t)
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(caCert)
+
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"m2m-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	t.Cleanup(tokenSrv.Close)
+
+	tcs := map[string]struct {
+		givenClientAuth tls.ClientAuthType
+		givenCtx        context.Context
+		givenConnect    func(addr string) (Conn, error)
+		expAuth         string
+		expErr          bool
+	}{
+		"tls": {
+			givenConnect: func(addr string) (Conn, error) {
+				return NewTLSConnection(context.Background(), addr, &tls.Config{RootCAs: rootCAs})
+			},
+		},
+		"mtls": {
+			givenClientAuth: tls.RequireAndVerifyClientCert,
+			givenConnect: func(addr string) (Conn, error) {
+				return NewMTLSConnection(context.Background(), addr, clientCert, rootCAs)
+			},
+		},
+		"client credentials": {
+			givenConnect: func(addr string) (Conn, error) {
+				return NewClientCredentialsConnection(context.Background(), addr, &tls.Config{RootCAs: rootCAs}, ClientCredentialsConfig{
+					TokenURL: tokenSrv.URL,
+					ClientID: "mechanicus",
+				})
+			},
+			expAuth: "Bearer m2m-token",
+		},
+		"token forwarding": {
+			givenCtx: iam.SetAccessTokenInContext(context.Background(), "user-token"),
+			givenConnect: func(addr string) (Conn, error) {
+				return NewTokenForwardingConnection(context.Background(), addr, &tls.Config{RootCAs: rootCAs})
+			},
+			expAuth: "Bearer user-token",
+		},
+		"error - mtls without client certificate": {
+			givenClientAuth: tls.RequireAndVerifyClientCert,
+			givenConnect: func(addr string) (Conn, error) {
+				return NewTLSConnection(context.Background(), addr, &tls.Config{RootCAs: rootCAs})
+			},
+			expErr: true,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			clientCAs := x509.NewCertPool()
+			clientCAs.AddCert(caCert)
+			lis, err := net.Listen("tcp", "localhost:0")
+			require.NoError(t, err)
+
+			weatherSvc := new(weatherService)
+			var gotAuth string
+			weatherSvc.On("GetWeatherInfo", mock.MatchedBy(func(ctx context.Context) bool {
+				md, _ := metadata.FromIncomingContext(ctx)
+				if values := md.Get("authorization"); len(values) > 0 {
+					gotAuth = values[0]
+				}
+				return true
+			}), mock.Anything).Return(&testdata.WeatherResponse{}, nil).Maybe()
+
+			grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+				Certificates: []tls.Certificate{serverCert},
+				ClientAuth:   tc.givenClientAuth,
+				ClientCAs:    clientCAs,
+			})))
+			testdata.RegisterWeatherServiceServer(grpcServer, weatherSvc)
+			go func() {
+				_ = grpcServer.Serve(lis)
+			}()
+			defer grpcServer.Stop()
+
+			ctx := tc.givenCtx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			conn, err := tc.givenConnect(lis.Addr().String())
+			require.NoError(t, err)
+
+			// When
+			_, err = testdata.NewWeatherServiceClient(conn).GetWeatherInfo(ctx, &testdata.WeatherRequest{Location: "Macragge"})
+
+			// Then
+			if tc.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expAuth, gotAuth)
+		})
+	}
+}
+
+// newTestCertificates generates a CA, a server certificate for localhost and a client certificate signed by the CA
+func newTestCertificates(t *testing.T) (*x509.Certificate, tls.Certificate, tls.Certificate) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Imperium CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage) tls.Certificate {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "localhost"},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+
+		return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	}
+
+	return caCert, issue(2, x509.ExtKeyUsageServerAuth), issue(3, x509.ExtKeyUsageClientAuth)
+}
@@ -0,0 +1,157 @@
+package grpcclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcCodeToHTTPStatus maps gRPC codes to HTTP status codes
+// following https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto
+var grpcCodeToHTTPStatus = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499, // Client Closed Request
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// StatusError represents a downstream gRPC status error.
+// It satisfies lit.Error, so it is rendered as the corresponding HTTP error when returned from lit handlers.
+type StatusError struct {
+	Status int    `json:"-"`
+	Code   string `json:"error"`
+	Desc   string `json:"error_description"`
+
+	grpcStatus *status.Status
+}
+
+func (e StatusError) StatusCode() int {
+	return e.Status
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("Status: [%d], Code: [%s], Desc: [%s]", e.Status, e.Code, e.Desc)
+}
+
+// GRPCStatus returns the original gRPC status, so that status.FromError keeps working
+func (e StatusError) GRPCStatus() *status.Status {
+	return e.grpcStatus
+}
+
+// ValidationError represents a downstream google.rpc.BadRequest error, keyed by field.
+// It is rendered with the same shape as lit.ValidationError.
+type ValidationError struct {
+	Fields map[string]string
+
+	grpcStatus *status.Status
+}
+
+func (v ValidationError) Error() string {
+	errStr := ""
+	for field, reason := range v.Fields {
+		errStr += field + ": " + reason + "\n"
+	}
+
+	return errStr
+}
+
+func (v ValidationError) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+// GRPCStatus returns the original gRPC status, so that status.FromError keeps working
+func (v ValidationError) GRPCStatus() *status.Status {
+	return v.grpcStatus
+}
+
+// MarshalJSON renders the field violations only, same as lit.ValidationError
+func (v ValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Fields)
+}
+
+// ConvertError converts the gRPC status error returned by downstream to lit.Error compatible error,
+// it is used by the client interceptors and the gRPC gateway of lit
+// Errors which are not gRPC status errors are returned as is
+func ConvertError(err error) error {
+	switch err.(type) {
+	case nil:
+		return nil
+	case StatusError, ValidationError:
+		return err // Already converted, e.g. ErrCircuitOpen
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	statusCode, ok := grpcCodeToHTTPStatus[st.Code()]
+	if !ok {
+		statusCode = http.StatusInternalServerError
+	}
+
+	code := toSnakeCase(st.Code().String())
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			if len(d.GetFieldViolations()) == 0 {
+				continue
+			}
+
+			violations := ValidationError{
+				Fields:     make(map[string]string, len(d.GetFieldViolations())),
+				grpcStatus: st,
+			}
+			for _, v := range d.GetFieldViolations() {
+				violations.Fields[v.GetField()] = v.GetDescription()
+			}
+			return violations
+		case *errdetails.ErrorInfo:
+			if d.GetReason() != "" {
+				code = d.GetReason()
+			}
+		}
+	}
+
+	return StatusError{
+		Status:     statusCode,
+		Code:       code,
+		Desc:       st.Message(),
+		grpcStatus: st,
+	}
+}
+
+// toSnakeCase converts gRPC code name to snake case, e.g. NotFound to not_found
+func toSnakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package grpcclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_convertError(t *testing.T) {
+	type args struct {
+		givenErr func() error
+		expErr   error
+		expCode  codes.Code
+		expJSON  string
+	}
+	tcs := map[string]args{
+		"nil": {
+			givenErr: func() error { return nil },
+		},
+		"not a gRPC status error": {
+			givenErr: func() error { return errors.New("simulated error") },
+			expErr:   errors.New("simulated error"),
+			expCode:  codes.Unknown,
+		},
+		"status without details": {
+			givenErr: func() error { return status.Error(codes.NotFound, "weather not found") },
+			expErr:   StatusError{Status: http.StatusNotFound, Code: "not_found", Desc: "weather not found"},
+			expCode:  codes.NotFound,
+			expJSON:  `{"error":"not_found","error_description":"weather not found"}`,
+		},
+		"status with ErrorInfo": {
+			givenErr: func() error {
+				st, _ := status.New(codes.Unauthenticated, "Access token is required").
+					WithDetails(&errdetails.ErrorInfo{Reason: "unauthorized"})
+				return st.Err()
+			},
+			expErr:  StatusError{Status: http.StatusUnauthorized, Code: "unauthorized", Desc: "Access token is required"},
+			expCode: codes.Unauthenticated,
+		},
+		"status with BadRequest": {
+			givenErr: func() error {
+				st, _ := status.New(codes.InvalidArgument, "Invalid request").
+					WithDetails(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+						{Field: "date", Description: "date is required"},
+					}})
+				return st.Err()
+			},
+			expErr:  ValidationError{Fields: map[string]string{"date": "date is required"}},
+			expCode: codes.InvalidArgument,
+			expJSON: `{"date":"date is required"}`,
+		},
+		"already converted": {
+			givenErr: func() error { return ErrCircuitOpen },
+			expErr:   StatusError{Status: http.StatusServiceUnavailable, Code: "circuit_open", Desc: "Circuit breaker is open"},
+			expCode:  codes.Unavailable,
+		},
+		"deadline exceeded": {
+			givenErr: func() error { return status.Error(codes.DeadlineExceeded, "context deadline exceeded") },
+			expErr:   StatusError{Status: http.StatusGatewayTimeout, Code: "deadline_exceeded", Desc: "context deadline exceeded"},
+			expCode:  codes.DeadlineExceeded,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			err := ConvertError(tc.givenErr())
+
+			// Then
+			if tc.expErr == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Equal(t, tc.expCode, status.Code(err))
+			if tc.expJSON != "" {
+				b, err := json.Marshal(err)
+				require.NoError(t, err)
+				require.JSONEq(t, tc.expJSON, string(b))
+			}
+
+			var statusErr StatusError
+			if errors.As(err, &statusErr) {
+				statusErr.grpcStatus = nil
+				err = statusErr
+			}
+			var validationErr ValidationError
+			if errors.As(err, &validationErr) {
+				validationErr.grpcStatus = nil
+				err = validationErr
+			}
+			require.Equal(t, tc.expErr, err)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package grpcclient
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// ConnOption is an optional configuration of the grpc clientConn. It is a grpc.DialOption, so it is passed to the
+// constructors along with the raw grpc.DialOption, e.g. transport credentials or a custom dialer
+type ConnOption struct {
+	grpc.EmptyDialOption
+	apply func(*connConfig)
+}
+
+// WithRetryPolicy enables the built-in gRPC retry of all methods, following the service config retry policy
+// Refer https://github.com/grpc/proposal/blob/master/A6-client-retries.md
+func WithRetryPolicy(policy RetryPolicy) ConnOption {
+	return ConnOption{apply: func(cfg *connConfig) {
+		cfg.retryPolicy = &policy
+	}}
+}
+
+// WithHedgingPolicy sends hedged calls for the given methods, e.g. /weather.WeatherService/GetWeatherInfo
+// Only idempotent methods should be hedged, as the downstream may handle the same call multiple times.
+// The hedged methods are not retried.
+func WithHedgingPolicy(policy HedgingPolicy, fullMethods ...string) ConnOption {
+	return ConnOption{apply: func(cfg *connConfig) {
+		cfg.hedgingPolicy = &policy
+		cfg.hedgedMethods = append(cfg.hedgedMethods, fullMethods...)
+	}}
+}
+
+// WithDefaultTimeout applies the timeout to the calls without deadline
+func WithDefaultTimeout(timeout time.Duration) ConnOption {
+	return ConnOption{apply: func(cfg *connConfig) {
+		cfg.defaultTimeout = timeout
+	}}
+}
+
+// WithCircuitBreaker opens the circuit after the given consecutive failures, failing fast with ErrCircuitOpen
+// A probe call is let through after openDuration, closing the circuit if it succeeds
+func WithCircuitBreaker(consecutiveFailures int, openDuration time.Duration) ConnOption {
+	return ConnOption{apply: func(cfg *connConfig) {
+		cfg.breaker = &circuitBreakerConfig{
+			threshold:    consecutiveFailures,
+			openDuration: openDuration,
+		}
+	}}
+}
+
+// WithRoundRobin balances the calls across all addresses resolved for the target instead of pinning to the first one,
+// e.g. the pods behind a headless Kubernetes service with the dns:///svc.namespace.svc.cluster.local:50051 target
+func WithRoundRobin() ConnOption {
+	return ConnOption{apply: func(cfg *connConfig) {
+		cfg.lbPolicy = lbPolicyRoundRobin
+	}}
+}
+
+// WithWeightedRoundRobin balances the calls weighted by the load reported by the backends with ORCA,
+// the backends not reporting their load are given the average weight
+// Refer https://github.com/grpc/proposal/blob/master/A58-client-side-weighted-round-robin-lb-policy.md
+func WithWeightedRoundRobin() ConnOption {
+	return ConnOption{apply: func(cfg *connConfig) {
+		cfg.lbPolicy = lbPolicyWeightedRoundRobin
+	}}
+}
+
+// WithClientHealthCheck skips the backends whose grpc.health.v1.Health status of serviceName is not SERVING
+// An empty serviceName checks the overall health of the server. It requires a balancing option e.g. WithRoundRobin.
+func WithClientHealthCheck(serviceName string) ConnOption {
+	return ConnOption{apply: func(cfg *connConfig) {
+		cfg.healthCheckService = &serviceName
+	}}
+}
+
+// WithDNSRefreshInterval re-resolves the DNS target periodically, so new backends are picked up without waiting for a connection failure
+// gRPC does not resolve more often than every 30s
+func WithDNSRefreshInterval(interval time.Duration) ConnOption {
+	return ConnOption{apply: func(cfg *connConfig) {
+		cfg.dnsRefreshInterval = interval
+	}}
+}
+
+// WithStaticAddresses resolves the target to the given fixed addresses, e.g. to test the load balancing
+func WithStaticAddresses(addrs ...string) ConnOption {
+	return ConnOption{apply: func(cfg *connConfig) {
+		cfg.staticAddrs = append(cfg.staticAddrs, addrs...)
+	}}
+}
+
+// connConfig is configurations of the grpc clientConn
+type connConfig struct {
+	dialOpts       []grpc.DialOption
+	retryPolicy    *RetryPolicy
+	hedgingPolicy  *HedgingPolicy
+	hedgedMethods  []string
+	defaultTimeout time.Duration
+	breaker        *circuitBreakerConfig
+
+	lbPolicy           string
+	healthCheckService *string
+	dnsRefreshInterval time.Duration
+	staticAddrs        []string
+}
+
+// serviceConfig returns the service config JSON of the load balancing, health checking and retry policy,
+// or empty if none is enabled
+func (cfg connConfig) serviceConfig() (string, error) {
+	type methodName struct {
+		Service string `json:"service,omitempty"`
+		Method  string `json:"method,omitempty"`
+	}
+	type methodConfig struct {
+		Name        []methodName        `json:"name"`
+		RetryPolicy *retryServiceConfig `json:"retryPolicy,omitempty"`
+	}
+	type healthCheckConfig struct {
+		ServiceName string `json:"serviceName"`
+	}
+	var svcConfig struct {
+		LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig,omitempty"`
+		HealthCheckConfig   *healthCheckConfig    `json:"healthCheckConfig,omitempty"`
+		MethodConfig        []methodConfig        `json:"methodConfig,omitempty"`
+	}
+
+	if cfg.lbPolicy != "" {
+		svcConfig.LoadBalancingConfig = []map[string]struct{}{{cfg.lbPolicy: {}}}
+	}
+	if cfg.healthCheckService != nil {
+		svcConfig.HealthCheckConfig = &healthCheckConfig{ServiceName: *cfg.healthCheckService}
+	}
+
+	if cfg.retryPolicy != nil {
+		policy, err := cfg.retryPolicy.toServiceConfig()
+		if err != nil {
+			return "", err
+		}
+
+		svcConfig.MethodConfig = []methodConfig{{Name: []methodName{{}}, RetryPolicy: &policy}}
+		if len(cfg.hedgedMethods) > 0 {
+			// The method specific config overrides the default one, so the hedged methods are not retried
+			hedged := methodConfig{}
+			for _, fullMethod := range cfg.hedgedMethods {
+				svc, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+				hedged.Name = append(hedged.Name, methodName{Service: svc, Method: method})
+			}
+			svcConfig.MethodConfig = append(svcConfig.MethodConfig, hedged)
+		}
+	}
+
+	if svcConfig.LoadBalancingConfig == nil && svcConfig.HealthCheckConfig == nil && svcConfig.MethodConfig == nil {
+		return "", nil
+	}
+
+	b, err := json.Marshal(svcConfig)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// serviceConfigCode returns the service config name of the code, e.g. DEADLINE_EXCEEDED
+func serviceConfigCode(c codes.Code) string {
+	return strings.ToUpper(toSnakeCase(c.String()))
+}
+
+// serviceConfigDuration returns the service config duration, e.g. 0.1s
+func serviceConfigDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
@@ -0,0 +1,101 @@
+package grpcclient
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/balancer/weightedroundrobin" // Register weighted_round_robin balancer
+	_ "google.golang.org/grpc/health"                      // Register the client health checking
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+const (
+	lbPolicyRoundRobin         = "round_robin"
+	lbPolicyWeightedRoundRobin = "weighted_round_robin"
+
+	staticScheme = "lit-static"
+	dnsScheme    = "dns"
+)
+
+// target returns the target to dial and the resolver options of the config
+func (cfg connConfig) target(addr string) (string, []grpc.DialOption) {
+	if len(cfg.staticAddrs) > 0 {
+		addrs := make([]resolver.Address, len(cfg.staticAddrs))
+		for idx, a := range cfg.staticAddrs {
+			addrs[idx] = resolver.Address{Addr: a}
+		}
+
+		r := manual.NewBuilderWithScheme(staticScheme)
+		r.InitialState(resolver.State{Addresses: addrs})
+
+		return staticScheme + ":///" + trimScheme(addr), []grpc.DialOption{grpc.WithResolvers(r)}
+	}
+
+	if cfg.dnsRefreshInterval > 0 {
+		return addr, []grpc.DialOption{grpc.WithResolvers(refreshingBuilder{
+			Builder:  resolver.Get(dnsScheme),
+			interval: cfg.dnsRefreshInterval,
+		})}
+	}
+
+	return addr, nil
+}
+
+// trimScheme returns the address of the target without the resolver scheme, e.g. svc:50051 of dns:///svc:50051
+func trimScheme(target string) string {
+	if _, addr, ok := strings.Cut(target, ":///"); ok {
+		return addr
+	}
+
+	return target
+}
+
+// refreshingBuilder builds resolvers of the wrapped scheme re-resolving periodically
+type refreshingBuilder struct {
+	resolver.Builder
+
+	interval time.Duration
+}
+
+func (b refreshingBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	r, err := b.Builder.Build(target, cc, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rr := &refreshingResolver{Resolver: r, stop: make(chan struct{})}
+	go rr.run(b.interval)
+
+	return rr, nil
+}
+
+type refreshingResolver struct {
+	resolver.Resolver
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func (r *refreshingResolver) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.Resolver.ResolveNow(resolver.ResolveNowOptions{})
+		}
+	}
+}
+
+func (r *refreshingResolver) Close() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+	r.Resolver.Close()
+}
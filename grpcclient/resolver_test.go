@@ -0,0 +1,230 @@
+package grpcclient
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/resolver"
+
+	"github.com/viebiz/lit/grpcclient/testdata"
+	"github.com/viebiz/lit/monitoring/tracing/mocktracer"
+)
+
+func TestConnConfig_serviceConfig_loadBalancing(t *testing.T) {
+	tcs := map[string]struct {
+		givenOpts []ConnOption
+		expConfig string
+	}{
+		"round robin": {
+			givenOpts: []ConnOption{WithRoundRobin()},
+			expConfig: `{"loadBalancingConfig":[{"round_robin":{}}]}`,
+		},
+		"weighted round robin with health check": {
+			givenOpts: []ConnOption{WithWeightedRoundRobin(), WithClientHealthCheck("weather.WeatherService")},
+			expConfig: `{"loadBalancingConfig":[{"weighted_round_robin":{}}],"healthCheckConfig":{"serviceName":"weather.WeatherService"}}`,
+		},
+		"round robin with retry": {
+			givenOpts: []ConnOption{WithRoundRobin(), WithClientHealthCheck(""), WithRetryPolicy(RetryPolicy{MaxAttempts: 2})},
+			expConfig: `{"loadBalancingConfig":[{"round_robin":{}}],"healthCheckConfig":{"serviceName":""},"methodConfig":[{"name":[{}],"retryPolicy":{"maxAttempts":2,"initialBackoff":"0.1s","maxBackoff":"1s","backoffMultiplier":2,"retryableStatusCodes":["UNAVAILABLE"]}}]}`,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			cfg := connConfig{}
+			for _, opt := range tc.givenOpts {
+				opt.apply(&cfg)
+			}
+
+			// When
+			svcConfig, err := cfg.serviceConfig()
+
+			// Then
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expConfig, svcConfig)
+		})
+	}
+}
+
+func TestWithRoundRobin(t *testing.T) {
+	tp := mocktracer.Start()
+	defer tp.Stop()
+
+	tcs := map[string]struct {
+		givenHealthCheck bool
+		givenServing     []bool
+		expCalls         []int
+	}{
+		"round robin": {
+			givenServing: []bool{true, true},
+			expCalls:     []int{2, 2},
+		},
+		"round robin without health check": {
+			givenServing: []bool{true, false},
+			expCalls:     []int{2, 2},
+		},
+		"unhealthy backend is skipped": {
+			givenHealthCheck: true,
+			givenServing:     []bool{true, false},
+			expCalls:         []int{4, 0},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			tp.Reset()
+
+			var addrs []string
+			var svcs []*weatherService
+			for _, serving := range tc.givenServing {
+				svc := new(weatherService)
+				svc.On("GetWeatherInfo", mock.Anything, mock.Anything).Return(&testdata.WeatherResponse{}, nil).Maybe()
+				svcs = append(svcs, svc)
+
+				healthSrv := health.NewServer()
+				if !serving {
+					healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+				}
+				addrs = append(addrs, startHealthWeatherServer(t, svc, healthSrv))
+			}
+
+			opts := []grpc.DialOption{WithRoundRobin(), WithStaticAddresses(addrs...)}
+			if tc.givenHealthCheck {
+				opts = append(opts, WithClientHealthCheck(""))
+			}
+			conn, err := NewUnauthenticatedConnection(context.Background(), "weather", opts...)
+			require.NoError(t, err)
+
+			// Wait until the serving backends are connected, as only the ready ones are picked
+			client := testdata.NewWeatherServiceClient(conn)
+			require.Eventually(t, func() bool {
+				_, err := client.GetWeatherInfo(context.Background(), &testdata.WeatherRequest{}, grpc.WaitForReady(true))
+				require.NoError(t, err)
+				for idx, svc := range svcs {
+					if tc.expCalls[idx] > 0 && len(svc.Calls) == 0 {
+						return false
+					}
+				}
+				return true
+			}, 5*time.Second, 10*time.Millisecond)
+			for _, svc := range svcs {
+				svc.Calls = nil
+			}
+			tp.Reset()
+
+			// When
+			for range 4 {
+				_, err := client.GetWeatherInfo(context.Background(), &testdata.WeatherRequest{}, grpc.WaitForReady(true))
+				require.NoError(t, err)
+			}
+
+			// Then
+			for idx, svc := range svcs {
+				svc.AssertNumberOfCalls(t, "GetWeatherInfo", tc.expCalls[idx])
+			}
+
+			spans := tp.GetSpans()
+			require.Len(t, spans, 4)
+			for _, span := range spans {
+				var peerAddr string
+				for _, attr := range span.Attributes {
+					if attr.Key == "network.peer.address" {
+						peerAddr = attr.Value.AsString()
+					}
+				}
+				require.Contains(t, addrs, peerAddr)
+				require.Contains(t, span.Attributes, attribute.String("server.address", "weather:"))
+			}
+		})
+	}
+}
+
+func TestRefreshingBuilder(t *testing.T) {
+	// Given
+	inner := &countingResolver{}
+	b := refreshingBuilder{
+		Builder:  fakeBuilder{r: inner},
+		interval: 10 * time.Millisecond,
+	}
+
+	// When
+	r, err := b.Build(resolver.Target{}, nil, resolver.BuildOptions{})
+	require.NoError(t, err)
+
+	// Then
+	require.Eventually(t, func() bool { return inner.resolved.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	r.Close()
+	r.Close() // Safe to close twice
+	require.True(t, inner.closed.Load())
+}
+
+func Test_trimScheme(t *testing.T) {
+	tcs := map[string]struct {
+		givenTarget string
+		expAddr     string
+	}{
+		"address":       {givenTarget: "localhost:50051", expAddr: "localhost:50051"},
+		"dns target":    {givenTarget: "dns:///weather.imperium.svc.cluster.local:50051", expAddr: "weather.imperium.svc.cluster.local:50051"},
+		"static target": {givenTarget: "lit-static:///weather", expAddr: "weather"},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expAddr, trimScheme(tc.givenTarget))
+		})
+	}
+}
+
+// startHealthWeatherServer starts the weather service with the health service on a random local port
+func startHealthWeatherServer(t *testing.T, svc testdata.WeatherServiceServer, healthSrv *health.Server) string {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	testdata.RegisterWeatherServiceServer(grpcServer, svc)
+	healthpb.RegisterHealthServer(grpcServer, healthSrv)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	return lis.Addr().String()
+}
+
+type fakeBuilder struct {
+	resolver.Builder
+
+	r resolver.Resolver
+}
+
+func (b fakeBuilder) Build(resolver.Target, resolver.ClientConn, resolver.BuildOptions) (resolver.Resolver, error) {
+	return b.r, nil
+}
+
+type countingResolver struct {
+	resolved atomic.Int32
+	closed   atomic.Bool
+}
+
+func (r *countingResolver) ResolveNow(resolver.ResolveNowOptions) {
+	r.resolved.Add(1)
+}
+
+func (r *countingResolver) Close() {
+	r.closed.Store(true)
+}
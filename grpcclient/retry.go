@@ -0,0 +1,237 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/viebiz/lit/monitoring/instrumentgrpc"
+)
+
+const (
+	maxCallAttempts          = 5 // Same as the limit of gRPC
+	defaultInitialBackoff    = 100 * time.Millisecond
+	defaultMaxBackoff        = time.Second
+	defaultBackoffMultiplier = 2
+)
+
+var (
+	ErrInvalidRetryPolicy   = errors.New("invalid retry policy")
+	ErrInvalidHedgingPolicy = errors.New("invalid hedging policy")
+)
+
+// RetryPolicy is the retry policy of the calls
+type RetryPolicy struct {
+	// MaxAttempts is the max number of attempts including the original call, between 2 and 5
+	MaxAttempts int
+	// InitialBackoff, MaxBackoff and BackoffMultiplier define the exponential backoff between attempts, with jitter
+	// Default are 100ms, 1s and 2
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+	// RetryableCodes are the status codes to retry, default is Unavailable
+	RetryableCodes []codes.Code
+}
+
+type retryServiceConfig struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+func (p RetryPolicy) toServiceConfig() (retryServiceConfig, error) {
+	if p.MaxAttempts < 2 || p.MaxAttempts > maxCallAttempts {
+		return retryServiceConfig{}, ErrInvalidRetryPolicy
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaultInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+	if p.BackoffMultiplier <= 0 {
+		p.BackoffMultiplier = defaultBackoffMultiplier
+	}
+	if len(p.RetryableCodes) == 0 {
+		p.RetryableCodes = []codes.Code{codes.Unavailable}
+	}
+
+	rs := retryServiceConfig{
+		MaxAttempts:       p.MaxAttempts,
+		InitialBackoff:    serviceConfigDuration(p.InitialBackoff),
+		MaxBackoff:        serviceConfigDuration(p.MaxBackoff),
+		BackoffMultiplier: p.BackoffMultiplier,
+	}
+	for _, c := range p.RetryableCodes {
+		rs.RetryableStatusCodes = append(rs.RetryableStatusCodes, serviceConfigCode(c))
+	}
+
+	return rs, nil
+}
+
+// HedgingPolicy is the hedging policy of the idempotent calls
+type HedgingPolicy struct {
+	// MaxAttempts is the max number of concurrent attempts including the original call, between 2 and 5
+	MaxAttempts int
+	// HedgingDelay is the delay before sending the next attempt if no response is received yet
+	HedgingDelay time.Duration
+	// NonFatalCodes are the status codes sending the next attempt immediately instead of failing the call
+	NonFatalCodes []codes.Code
+}
+
+func (p HedgingPolicy) validate() error {
+	if p.MaxAttempts < 2 || p.MaxAttempts > maxCallAttempts || p.HedgingDelay < 0 {
+		return ErrInvalidHedgingPolicy
+	}
+
+	return nil
+}
+
+// hedgingInterceptor sends up to MaxAttempts concurrent attempts of the hedged methods, returning the first successful or fatal result
+func hedgingInterceptor(policy HedgingPolicy, fullMethods []string) grpc.UnaryClientInterceptor {
+	hedged := make(map[string]bool, len(fullMethods))
+	for _, m := range fullMethods {
+		hedged[m] = true
+	}
+	nonFatal := make(map[codes.Code]bool, len(policy.NonFatalCodes))
+	for _, c := range policy.NonFatalCodes {
+		nonFatal[c] = true
+	}
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		replyMsg, ok := reply.(proto.Message)
+		if !hedged[method] || !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel() // Cancel the pending attempts once the result is known
+
+		type result struct {
+			reply proto.Message
+			peer  *peer.Peer
+			err   error
+		}
+		results := make(chan result, policy.MaxAttempts)
+		attempt := func() {
+			attemptReply := replyMsg.ProtoReflect().New().Interface()
+			attemptOpts, attemptPeer := withAttemptPeer(opts)
+			err := invoker(ctx, method, req, attemptReply, cc, attemptOpts...)
+			results <- result{reply: attemptReply, peer: attemptPeer, err: err}
+		}
+
+		go attempt()
+		sent, pending := 1, 1
+		timer := time.NewTimer(policy.HedgingDelay)
+		defer timer.Stop()
+
+		var lastErr error
+		for pending > 0 {
+			select {
+			case rs := <-results:
+				pending--
+				if rs.err == nil || !nonFatal[status.Code(rs.err)] {
+					setPeer(opts, rs.peer)
+					if rs.err == nil {
+						proto.Merge(replyMsg, rs.reply)
+					}
+					return rs.err
+				}
+				lastErr = rs.err
+				if sent < policy.MaxAttempts {
+					go attempt() // Non fatal error, send the next attempt without waiting
+					sent++
+					pending++
+				}
+			case <-timer.C:
+				if sent < policy.MaxAttempts {
+					go attempt()
+					sent++
+					pending++
+					timer.Reset(policy.HedgingDelay)
+				}
+			}
+		}
+
+		return lastErr
+	}
+}
+
+// withAttemptPeer returns the call options with a peer of the attempt, as the concurrent attempts cannot share the peer of the call
+func withAttemptPeer(opts []grpc.CallOption) ([]grpc.CallOption, *peer.Peer) {
+	attemptPeer := &peer.Peer{}
+	attemptOpts := make([]grpc.CallOption, 0, len(opts)+1)
+	for _, opt := range opts {
+		if _, ok := opt.(grpc.PeerCallOption); !ok {
+			attemptOpts = append(attemptOpts, opt)
+		}
+	}
+
+	return append(attemptOpts, grpc.Peer(attemptPeer)), attemptPeer
+}
+
+// setPeer sets the peer of the attempt to the peer of the call
+func setPeer(opts []grpc.CallOption, p *peer.Peer) {
+	for _, opt := range opts {
+		if po, ok := opt.(grpc.PeerCallOption); ok {
+			*po.PeerAddr = *p
+		}
+	}
+}
+
+// defaultTimeoutInterceptor applies the timeout to the calls without deadline
+func defaultTimeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+type attemptCounterKey struct{}
+
+// withAttemptCounter returns the context counting the attempts of the call, including retries and hedged attempts
+func withAttemptCounter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, attemptCounterKey{}, new(atomic.Int32))
+}
+
+// attemptStatsHandler records the attempts of the calls as span events, as the gRPC retries are not visible to interceptors
+type attemptStatsHandler struct{}
+
+func (attemptStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (attemptStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	begin, ok := s.(*stats.Begin)
+	if !ok || !begin.IsClient() {
+		return
+	}
+
+	counter, ok := ctx.Value(attemptCounterKey{}).(*atomic.Int32)
+	if !ok {
+		return
+	}
+
+	instrumentgrpc.RecordGRPCCallAttempt(ctx, int(counter.Add(1)), begin.IsTransparentRetryAttempt)
+}
+
+func (attemptStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (attemptStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
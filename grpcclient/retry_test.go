@@ -0,0 +1,306 @@
+package grpcclient
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/viebiz/lit/grpcclient/testdata"
+	"github.com/viebiz/lit/monitoring/tracing/mocktracer"
+)
+
+func TestConnConfig_serviceConfig(t *testing.T) {
+	tcs := map[string]struct {
+		givenOpts []ConnOption
+		expConfig string
+		expErr    error
+	}{
+		"retry disabled": {},
+		"default retry policy": {
+			givenOpts: []ConnOption{WithRetryPolicy(RetryPolicy{MaxAttempts: 3})},
+			expConfig: `{"methodConfig":[{"name":[{}],"retryPolicy":{"maxAttempts":3,"initialBackoff":"0.1s","maxBackoff":"1s","backoffMultiplier":2,"retryableStatusCodes":["UNAVAILABLE"]}}]}`,
+		},
+		"retry policy with hedged methods": {
+			givenOpts: []ConnOption{
+				WithRetryPolicy(RetryPolicy{
+					MaxAttempts:       4,
+					InitialBackoff:    50 * time.Millisecond,
+					MaxBackoff:        2 * time.Second,
+					BackoffMultiplier: 1.5,
+					RetryableCodes:    []codes.Code{codes.Unavailable, codes.DeadlineExceeded},
+				}),
+				WithHedgingPolicy(HedgingPolicy{MaxAttempts: 2}, "/weather.WeatherService/GetWeatherInfo"),
+			},
+			expConfig: `{"methodConfig":[{"name":[{}],"retryPolicy":{"maxAttempts":4,"initialBackoff":"0.05s","maxBackoff":"2s","backoffMultiplier":1.5,"retryableStatusCodes":["UNAVAILABLE","DEADLINE_EXCEEDED"]}},{"name":[{"service":"weather.WeatherService","method":"GetWeatherInfo"}]}]}`,
+		},
+		"error - invalid max attempts": {
+			givenOpts: []ConnOption{WithRetryPolicy(RetryPolicy{MaxAttempts: 6})},
+			expErr:    ErrInvalidRetryPolicy,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			cfg := connConfig{}
+			for _, opt := range tc.givenOpts {
+				opt.apply(&cfg)
+			}
+
+			// When
+			svcConfig, err := cfg.serviceConfig()
+
+			// Then
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expConfig == "" {
+				require.Empty(t, svcConfig)
+			} else {
+				require.JSONEq(t, tc.expConfig, svcConfig)
+			}
+		})
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	tp := mocktracer.Start()
+	defer tp.Stop()
+
+	tcs := map[string]struct {
+		givenFailures int
+		givenPolicy   RetryPolicy
+		expAttempts   []int
+		expErr        error
+	}{
+		"success after retries": {
+			givenFailures: 2,
+			givenPolicy:   RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+			expAttempts:   []int{2, 3},
+		},
+		"error - attempts exhausted": {
+			givenFailures: 2,
+			givenPolicy:   RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+			expAttempts:   []int{2},
+			expErr:        StatusError{Status: 503, Code: "unavailable", Desc: "weather station is down"},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			tp.Reset()
+
+			weatherSvc := new(weatherService)
+			weatherSvc.On("GetWeatherInfo", mock.Anything, mock.Anything).
+				Return((*testdata.WeatherResponse)(nil), status.Error(codes.Unavailable, "weather station is down")).
+				Times(tc.givenFailures)
+			weatherSvc.On("GetWeatherInfo", mock.Anything, mock.Anything).
+				Return(&testdata.WeatherResponse{WeatherDetails: []*testdata.WeatherDetail{{Location: "Macragge"}}}, nil).
+				Maybe()
+
+			addr := startWeatherServer(t, weatherSvc)
+			conn, err := NewUnauthenticatedConnection(context.Background(), addr, WithRetryPolicy(tc.givenPolicy))
+			require.NoError(t, err)
+
+			// When
+			_, err = testdata.NewWeatherServiceClient(conn).GetWeatherInfo(context.Background(), &testdata.WeatherRequest{})
+
+			// Then
+			if tc.expErr != nil {
+				require.EqualError(t, err, tc.expErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			span := tp.GetLatestSpan()
+			require.Equal(t, "grpc.unary_outgoing_call", span.Name)
+			var attempts []int
+			for _, event := range span.Events {
+				if event.Name != "retry_attempt" {
+					continue // The error is recorded as exception event
+				}
+				require.Contains(t, event.Attributes, attribute.Bool("rpc.grpc.transparent_retry", false))
+				for _, attr := range event.Attributes {
+					if attr.Key == "rpc.grpc.attempt" {
+						attempts = append(attempts, int(attr.Value.AsInt64()))
+					}
+				}
+			}
+			require.Equal(t, tc.expAttempts, attempts)
+		})
+	}
+}
+
+func TestHedgingInterceptor(t *testing.T) {
+	policy := HedgingPolicy{
+		MaxAttempts:   3,
+		HedgingDelay:  20 * time.Millisecond,
+		NonFatalCodes: []codes.Code{codes.Unavailable},
+	}
+	slow := func(ctx context.Context) (string, error) {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(time.Second):
+			return "slow", nil
+		}
+	}
+
+	tcs := map[string]struct {
+		givenMethod   string
+		givenAttempts []func(ctx context.Context) (string, error)
+		expLocation   string
+		expErr        error
+		expCalls      int32
+	}{
+		"not hedged method": {
+			givenMethod: "/weather.WeatherService/DeleteWeather",
+			givenAttempts: []func(ctx context.Context) (string, error){
+				func(ctx context.Context) (string, error) { return "Macragge", nil },
+			},
+			expLocation: "Macragge",
+			expCalls:    1,
+		},
+		"hedged after delay": {
+			givenMethod: "/weather.WeatherService/GetWeatherInfo",
+			givenAttempts: []func(ctx context.Context) (string, error){
+				slow,
+				func(ctx context.Context) (string, error) { return "Macragge", nil },
+			},
+			expLocation: "Macragge",
+			expCalls:    2,
+		},
+		"non fatal error sends the next attempt": {
+			givenMethod: "/weather.WeatherService/GetWeatherInfo",
+			givenAttempts: []func(ctx context.Context) (string, error){
+				func(ctx context.Context) (string, error) { return "", status.Error(codes.Unavailable, "down") },
+				func(ctx context.Context) (string, error) { return "Macragge", nil },
+			},
+			expLocation: "Macragge",
+			expCalls:    2,
+		},
+		"error - fatal error": {
+			givenMethod: "/weather.WeatherService/GetWeatherInfo",
+			givenAttempts: []func(ctx context.Context) (string, error){
+				func(ctx context.Context) (string, error) { return "", status.Error(codes.NotFound, "not found") },
+			},
+			expErr:   status.Error(codes.NotFound, "not found"),
+			expCalls: 1,
+		},
+		"error - all attempts failed": {
+			givenMethod: "/weather.WeatherService/GetWeatherInfo",
+			givenAttempts: []func(ctx context.Context) (string, error){
+				func(ctx context.Context) (string, error) { return "", status.Error(codes.Unavailable, "down") },
+				func(ctx context.Context) (string, error) { return "", status.Error(codes.Unavailable, "down") },
+				func(ctx context.Context) (string, error) { return "", status.Error(codes.Unavailable, "still down") },
+			},
+			expErr:   status.Error(codes.Unavailable, "still down"),
+			expCalls: 3,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			var calls atomic.Int32
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				location, err := tc.givenAttempts[calls.Add(1)-1](ctx)
+				if err != nil {
+					return err
+				}
+				proto.Merge(reply.(proto.Message), &testdata.WeatherResponse{WeatherDetails: []*testdata.WeatherDetail{{Location: location}}})
+				return nil
+			}
+			reply := &testdata.WeatherResponse{}
+
+			// When
+			err := hedgingInterceptor(policy, []string{"/weather.WeatherService/GetWeatherInfo"})(
+				context.Background(), tc.givenMethod, &testdata.WeatherRequest{}, reply, nil, invoker)
+
+			// Then
+			if tc.expErr != nil {
+				require.EqualError(t, err, tc.expErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Len(t, reply.WeatherDetails, 1)
+				require.Equal(t, tc.expLocation, reply.WeatherDetails[0].Location)
+			}
+			require.Equal(t, tc.expCalls, calls.Load())
+		})
+	}
+}
+
+func TestDefaultTimeoutInterceptor(t *testing.T) {
+	tcs := map[string]struct {
+		givenTimeout time.Duration
+		expTimeout   time.Duration
+	}{
+		"timeout applied": {
+			expTimeout: time.Second,
+		},
+		"caller deadline kept": {
+			givenTimeout: time.Minute,
+			expTimeout:   time.Minute,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+			if tc.givenTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.givenTimeout)
+				defer cancel()
+			}
+
+			var deadline time.Time
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				deadline, _ = ctx.Deadline()
+				return nil
+			}
+
+			// When
+			start := time.Now()
+			err := defaultTimeoutInterceptor(time.Second)(ctx, "/weather.WeatherService/GetWeatherInfo", nil, nil, nil, invoker)
+
+			// Then
+			require.NoError(t, err)
+			require.WithinDuration(t, start.Add(tc.expTimeout), deadline, 100*time.Millisecond)
+		})
+	}
+}
+
+// startWeatherServer starts the weather service on a random local port, stopped at the end of the test
+func startWeatherServer(t *testing.T, svc testdata.WeatherServiceServer, opts ...grpc.ServerOption) string {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer(opts...)
+	testdata.RegisterWeatherServiceServer(grpcServer, svc)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	return lis.Addr().String()
+}
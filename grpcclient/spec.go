@@ -6,7 +6,7 @@ import (
 	"google.golang.org/grpc"
 )
 
-// Conn defines a gRPC unary client connection interface.
+// Conn defines a gRPC client connection interface.
 type Conn interface {
 	Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error
 
@@ -4,6 +4,10 @@ import (
 	"errors"
 	"net/http"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/viebiz/lit"
 )
 
@@ -22,3 +26,13 @@ var (
 func unauthorizedErr(err error) lit.HttpError {
 	return lit.HttpError{Status: http.StatusUnauthorized, Code: unAuthorizedKey, Desc: err.Error()}
 }
+
+// grpcStatusErr converts the HTTP error to gRPC status error, the error code is carried by google.rpc.ErrorInfo
+func grpcStatusErr(code codes.Code, err lit.HttpError) error {
+	st, detailErr := status.New(code, err.Desc).WithDetails(&errdetails.ErrorInfo{Reason: err.Code})
+	if detailErr != nil {
+		return status.Error(code, err.Desc) // Should never happen as the details are valid proto messages
+	}
+
+	return st.Err()
+}
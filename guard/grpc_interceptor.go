@@ -0,0 +1,172 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/viebiz/lit"
+	"github.com/viebiz/lit/iam"
+	"github.com/viebiz/lit/monitoring"
+)
+
+const (
+	metadataAuthorization = "authorization"
+)
+
+// UnaryServerInterceptor authenticates and authorizes the unary calls following the rules
+// The authenticated iam.UserProfile or iam.M2MProfile is available in the handler context
+func (guard AuthGuard) UnaryServerInterceptor(rules GRPCRules) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := guard.authorizeGRPC(ctx, rules, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates and authorizes the stream calls following the rules
+// The authenticated iam.UserProfile or iam.M2MProfile is available in the stream context
+func (guard AuthGuard) StreamServerInterceptor(rules GRPCRules) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := guard.authorizeGRPC(ss.Context(), rules, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (guard AuthGuard) authorizeGRPC(ctx context.Context, rules GRPCRules, fullMethod string) (context.Context, error) {
+	// 1. Find the rule of the method, deny by default
+	rule, ok := rules.lookup(fullMethod)
+	if !ok {
+		monitoring.FromContext(ctx).Warnf("Missing auth rule for gRPC method %s", fullMethod)
+		return ctx, grpcStatusErr(codes.PermissionDenied, errForbidden)
+	}
+
+	if rule.Public {
+		return ctx, nil
+	}
+
+	// 2. Get access token from metadata
+	tokenStr := getGRPCTokenString(ctx)
+	if tokenStr == "" {
+		return ctx, grpcStatusErr(codes.Unauthenticated, errMissingAccessToken)
+	}
+
+	// 3. Validate access token
+	tk, err := guard.validator.Validate(tokenStr)
+	if err != nil {
+		return ctx, grpcAuthErr(ctx, err)
+	}
+
+	// 4. Extract profile from token claims and check the rule
+	ctx = iam.SetAccessTokenInContext(ctx, tokenStr)
+	if rule.M2M {
+		return guard.authorizeGRPCM2M(ctx, rule, tk.Claims)
+	}
+
+	return guard.authorizeGRPCUser(ctx, rule, tk.Claims)
+}
+
+func (guard AuthGuard) authorizeGRPCM2M(ctx context.Context, rule GRPCRule, claims iam.Claims) (context.Context, error) {
+	profile, err := iam.ExtractM2MProfileFromClaims(claims)
+	if err != nil {
+		return ctx, grpcAuthErr(ctx, err)
+	}
+
+	ctx = iam.SetM2MProfileInContext(ctx, profile)
+	ctx = monitoring.InjectField(ctx, m2mIDKey, profile.ID())
+
+	if len(rule.Scopes) > 0 && !profile.HasAnyScope(rule.Scopes...) {
+		return ctx, grpcStatusErr(codes.PermissionDenied, errForbidden)
+	}
+
+	return ctx, nil
+}
+
+func (guard AuthGuard) authorizeGRPCUser(ctx context.Context, rule GRPCRule, claims iam.Claims) (context.Context, error) {
+	profile, err := iam.ExtractUserProfileFromClaims(claims)
+	if err != nil {
+		return ctx, grpcAuthErr(ctx, err)
+	}
+
+	ctx = iam.SetUserProfileInContext(ctx, profile)
+	ctx = monitoring.InjectFields(ctx, map[string]string{
+		userIDKey: profile.ID(),
+		roleKey:   profile.GetRoleString(),
+	})
+
+	if rule.Resource == "" {
+		return ctx, nil
+	}
+
+	// TODO: Multiple role not supported yet, use the first role, same as RolePermissionHandler
+	var r string
+	for _, role := range profile.GetRoles() {
+		r = role
+		break
+	}
+
+	if err := guard.enforcer.Enforce(r, rule.Resource, rule.Action.String()); err != nil {
+		return ctx, grpcAuthErr(ctx, err)
+	}
+
+	return ctx, nil
+}
+
+// getGRPCTokenString returns the bearer token of the authorization metadata
+func getGRPCTokenString(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(metadataAuthorization)
+	if len(values) == 0 {
+		return ""
+	}
+
+	authHeaderParts := strings.Split(values[0], " ")
+	if len(authHeaderParts) != 2 || authHeaderParts[0] != authorizationBearerPrefix {
+		return ""
+	}
+
+	return authHeaderParts[1]
+}
+
+// grpcAuthErr converts the iam error to gRPC status error, same as responseErr does for HTTP
+func grpcAuthErr(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, iam.ErrMissingRequiredClaim),
+		errors.Is(err, iam.ErrTokenExpired),
+		errors.Is(err, iam.ErrInvalidToken):
+		return grpcStatusErr(codes.Unauthenticated, unauthorizedErr(err))
+
+	case errors.Is(err, iam.ErrActionIsNotAllowed):
+		return grpcStatusErr(codes.PermissionDenied, errForbidden)
+
+	default:
+		monitoring.FromContext(ctx).Errorf(err, "Got unexpected error")
+		return grpcStatusErr(codes.Internal, lit.ErrDefaultInternal)
+	}
+}
+
+// authServerStream overrides the context of the stream with the authenticated one
+type authServerStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
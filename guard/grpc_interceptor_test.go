@@ -0,0 +1,241 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/viebiz/lit/iam"
+	"github.com/viebiz/lit/jwt"
+)
+
+func TestAuthGuard_UnaryServerInterceptor(t *testing.T) {
+	rules := GRPCRules{
+		"/weather.WeatherService/GetWeatherInfo": {Resource: "weather", Action: ActionRead},
+		"/weather.WeatherService/Ping":           {Public: true},
+		"/weather.StationService/*":              {M2M: true, Scopes: []string{"station.read"}},
+	}
+	userToken := jwt.Token[iam.Claims]{
+		Claims: iam.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{Subject: "imperium|space_marine"},
+			ExtraClaims:      map[string]interface{}{"roles": []string{"primarch"}},
+		},
+	}
+	m2mToken := func(scope string) jwt.Token[iam.Claims] {
+		return jwt.Token[iam.Claims]{
+			Claims: iam.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "mechanicus"},
+				ExtraClaims:      map[string]interface{}{"scope": scope},
+			},
+		}
+	}
+
+	type validateMock struct {
+		outToken jwt.Token[iam.Claims]
+		outErr   error
+	}
+	type enforceMock struct {
+		outErr error
+	}
+	tcs := map[string]struct {
+		givenMethod     string
+		givenAuth       string
+		validateMock    *validateMock
+		enforceMock     *enforceMock
+		expUserProfile  iam.UserProfile
+		expM2MProfile   iam.M2MProfile
+		expErr          error
+		expHandlerCalls int
+	}{
+		"public method": {
+			givenMethod:     "/weather.WeatherService/Ping",
+			expHandlerCalls: 1,
+		},
+		"user, permitted": {
+			givenMethod:     "/weather.WeatherService/GetWeatherInfo",
+			givenAuth:       "Bearer user-token",
+			validateMock:    &validateMock{outToken: userToken},
+			enforceMock:     &enforceMock{},
+			expUserProfile:  iam.NewUserProfile("imperium|space_marine", []string{"primarch"}, nil),
+			expHandlerCalls: 1,
+		},
+		"m2m, has scope": {
+			givenMethod:     "/weather.StationService/ListStations",
+			givenAuth:       "Bearer m2m-token",
+			validateMock:    &validateMock{outToken: m2mToken("station.read station.write")},
+			expM2MProfile:   iam.NewM2MProfile("mechanicus", []string{"station.read", "station.write"}),
+			expHandlerCalls: 1,
+		},
+		"error - missing rule": {
+			givenMethod: "/weather.WeatherService/DeleteWeather",
+			givenAuth:   "Bearer user-token",
+			expErr:      status.Error(codes.PermissionDenied, "Permission denied"),
+		},
+		"error - missing access token": {
+			givenMethod: "/weather.WeatherService/GetWeatherInfo",
+			expErr:      status.Error(codes.Unauthenticated, "Access token is required"),
+		},
+		"error - not bearer token": {
+			givenMethod: "/weather.WeatherService/GetWeatherInfo",
+			givenAuth:   "Basic dXNlcjpwYXNz",
+			expErr:      status.Error(codes.Unauthenticated, "Access token is required"),
+		},
+		"error - token expired": {
+			givenMethod:  "/weather.WeatherService/GetWeatherInfo",
+			givenAuth:    "Bearer user-token",
+			validateMock: &validateMock{outErr: iam.ErrTokenExpired},
+			expErr:       status.Error(codes.Unauthenticated, "token expired"),
+		},
+		"error - missing role claim": {
+			givenMethod:  "/weather.WeatherService/GetWeatherInfo",
+			givenAuth:    "Bearer m2m-token",
+			validateMock: &validateMock{outToken: m2mToken("station.read")},
+			expErr:       status.Error(codes.Unauthenticated, "missing required claim"),
+		},
+		"error - action is not allowed": {
+			givenMethod:  "/weather.WeatherService/GetWeatherInfo",
+			givenAuth:    "Bearer user-token",
+			validateMock: &validateMock{outToken: userToken},
+			enforceMock:  &enforceMock{outErr: iam.ErrActionIsNotAllowed},
+			expErr:       status.Error(codes.PermissionDenied, "Permission denied"),
+		},
+		"error - missing scope": {
+			givenMethod:  "/weather.StationService/ListStations",
+			givenAuth:    "Bearer m2m-token",
+			validateMock: &validateMock{outToken: m2mToken("station.write")},
+			expErr:       status.Error(codes.PermissionDenied, "Permission denied"),
+		},
+		"error - unexpected": {
+			givenMethod:  "/weather.WeatherService/GetWeatherInfo",
+			givenAuth:    "Bearer user-token",
+			validateMock: &validateMock{outErr: errors.New("simulate server error")},
+			expErr:       status.Error(codes.Internal, "Something went wrong"),
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+			if tc.givenAuth != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tc.givenAuth))
+			}
+
+			validator := new(iam.MockValidator)
+			if tc.validateMock != nil {
+				validator.On("Validate", tc.givenAuth[len("Bearer "):]).
+					Return(tc.validateMock.outToken, tc.validateMock.outErr)
+			}
+
+			enforcer := new(iam.MockEnforcer)
+			if tc.enforceMock != nil {
+				enforcer.On("Enforce", "primarch", "weather", "R").Return(tc.enforceMock.outErr)
+			}
+
+			var handlerCalls int
+			handler := func(ctx context.Context, req any) (any, error) {
+				handlerCalls++
+				require.Equal(t, tc.expUserProfile, iam.GetUserProfileFromContext(ctx))
+				require.ElementsMatch(t, tc.expM2MProfile.GetScopes(), iam.GetM2MProfileFromContext(ctx).GetScopes())
+				require.Equal(t, tc.expM2MProfile.ID(), iam.GetM2MProfileFromContext(ctx).ID())
+				require.Equal(t, strings.TrimPrefix(tc.givenAuth, "Bearer "), iam.GetAccessTokenFromContext(ctx))
+				return "ok", nil
+			}
+
+			intercept := New(validator, enforcer).UnaryServerInterceptor(rules)
+
+			// When
+			rs, err := intercept(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.givenMethod}, handler)
+
+			// Then
+			if tc.expErr != nil {
+				require.EqualError(t, err, tc.expErr.Error())
+				require.Nil(t, rs)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "ok", rs)
+			}
+			require.Equal(t, tc.expHandlerCalls, handlerCalls)
+			validator.AssertExpectations(t)
+			enforcer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthGuard_StreamServerInterceptor(t *testing.T) {
+	rules := GRPCRules{
+		"/weather.WeatherService/*": {M2M: true, Scopes: []string{"weather.read"}},
+	}
+
+	tcs := map[string]struct {
+		givenAuth string
+		expErr    error
+	}{
+		"success": {
+			givenAuth: "Bearer m2m-token",
+		},
+		"error - missing access token": {
+			expErr: status.Error(codes.Unauthenticated, "Access token is required"),
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			ctx := context.Background()
+			validator := new(iam.MockValidator)
+			if tc.givenAuth != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tc.givenAuth))
+				validator.On("Validate", "m2m-token").Return(jwt.Token[iam.Claims]{
+					Claims: iam.Claims{
+						RegisteredClaims: jwt.RegisteredClaims{Subject: "mechanicus"},
+						ExtraClaims:      map[string]interface{}{"scope": "weather.read"},
+					},
+				}, nil)
+			}
+
+			var profile iam.M2MProfile
+			handler := func(srv any, ss grpc.ServerStream) error {
+				profile = iam.GetM2MProfileFromContext(ss.Context())
+				return nil
+			}
+
+			intercept := New(validator, nil).StreamServerInterceptor(rules)
+
+			// When
+			err := intercept(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/weather.WeatherService/StreamWeather"}, handler)
+
+			// Then
+			if tc.expErr != nil {
+				require.EqualError(t, err, tc.expErr.Error())
+				require.Empty(t, profile.ID())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "mechanicus", profile.ID())
+			}
+			validator.AssertExpectations(t)
+		})
+	}
+}
+
+// fakeServerStream is a grpc.ServerStream only carrying the context
+type fakeServerStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
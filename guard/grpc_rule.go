@@ -0,0 +1,76 @@
+package guard
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	grpcRuleWildcard = "*"
+)
+
+// GRPCRule is the authentication and authorization rule of a gRPC method
+type GRPCRule struct {
+	// Public skips the authentication, e.g. for health checks
+	Public bool
+
+	// M2M authenticates machine-to-machine tokens instead of user tokens
+	M2M bool
+
+	// Scopes requires any of the scopes in the M2M token
+	Scopes []string
+
+	// Resource and Action are enforced with the user role through iam.Enforcer, skipped if Resource is empty
+	Resource string
+	Action   Action
+}
+
+// GRPCRules maps the full method, e.g. /weather.WeatherService/GetWeatherInfo, to its rule
+// "/weather.WeatherService/*" applies to all methods of the service and "*" to all methods.
+// Methods without rule are denied.
+type GRPCRules map[string]GRPCRule
+
+// lookup returns the rule of the method, the most specific one wins
+func (rules GRPCRules) lookup(fullMethod string) (GRPCRule, bool) {
+	if rule, ok := rules[fullMethod]; ok {
+		return rule, true
+	}
+
+	if idx := strings.LastIndex(fullMethod, "/"); idx > 0 {
+		if rule, ok := rules[fullMethod[:idx+1]+grpcRuleWildcard]; ok {
+			return rule, true
+		}
+	}
+
+	rule, ok := rules[grpcRuleWildcard]
+	return rule, ok
+}
+
+// GRPCRulesFromMethodOptions builds the rules of the given services from a custom method option.
+// The convert function maps the option value to the rule, it is only called for methods having the option, e.g.
+//
+//	// extend google.protobuf.MethodOptions { AuthRule auth = 50001; }
+//	rules := guard.GRPCRulesFromMethodOptions(pb.E_Auth, func(v any) guard.GRPCRule {
+//		r := v.(*pb.AuthRule)
+//		return guard.GRPCRule{M2M: true, Scopes: r.GetScopes()}
+//	}, pb.File_weather_proto.Services().Get(0))
+func GRPCRulesFromMethodOptions(xt protoreflect.ExtensionType, convert func(value any) GRPCRule, services ...protoreflect.ServiceDescriptor) GRPCRules {
+	rules := GRPCRules{}
+	for _, sd := range services {
+		methods := sd.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			md := methods.Get(i)
+
+			opts := md.Options()
+			if opts == nil || !proto.HasExtension(opts, xt) {
+				continue
+			}
+
+			rules["/"+string(sd.FullName())+"/"+string(md.Name())] = convert(proto.GetExtension(opts, xt))
+		}
+	}
+
+	return rules
+}
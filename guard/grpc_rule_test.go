@@ -0,0 +1,157 @@
+package guard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestGRPCRules_lookup(t *testing.T) {
+	rules := GRPCRules{
+		"/weather.WeatherService/GetWeatherInfo": {Resource: "weather", Action: ActionRead},
+		"/weather.WeatherService/*":              {M2M: true},
+		"*":                                      {Public: true},
+	}
+
+	tcs := map[string]struct {
+		givenRules  GRPCRules
+		givenMethod string
+		expRule     GRPCRule
+		expFound    bool
+	}{
+		"exact method": {
+			givenRules:  rules,
+			givenMethod: "/weather.WeatherService/GetWeatherInfo",
+			expRule:     GRPCRule{Resource: "weather", Action: ActionRead},
+			expFound:    true,
+		},
+		"service wildcard": {
+			givenRules:  rules,
+			givenMethod: "/weather.WeatherService/StreamWeather",
+			expRule:     GRPCRule{M2M: true},
+			expFound:    true,
+		},
+		"global wildcard": {
+			givenRules:  rules,
+			givenMethod: "/grpc.health.v1.Health/Check",
+			expRule:     GRPCRule{Public: true},
+			expFound:    true,
+		},
+		"not found": {
+			givenRules:  GRPCRules{"/weather.WeatherService/*": {M2M: true}},
+			givenMethod: "/grpc.health.v1.Health/Check",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			rule, found := tc.givenRules.lookup(tc.givenMethod)
+
+			// Then
+			require.Equal(t, tc.expFound, found)
+			require.Equal(t, tc.expRule, rule)
+		})
+	}
+}
+
+func TestGRPCRulesFromMethodOptions(t *testing.T) {
+	// Given
+	xt, sd := newAuthRuleService(t)
+
+	// When
+	rules := GRPCRulesFromMethodOptions(xt, func(value any) GRPCRule {
+		scopes := value.(protoreflect.ProtoMessage).ProtoReflect()
+		list := scopes.Get(scopes.Descriptor().Fields().ByName("scopes")).List()
+
+		rule := GRPCRule{M2M: true}
+		for i := 0; i < list.Len(); i++ {
+			rule.Scopes = append(rule.Scopes, list.Get(i).String())
+		}
+		return rule
+	}, sd)
+
+	// Then
+	require.Equal(t, GRPCRules{
+		"/weather.WeatherService/GetWeatherInfo": {M2M: true, Scopes: []string{"weather.read"}},
+	}, rules)
+}
+
+// newAuthRuleService builds a custom auth rule method option and a service having one annotated method
+func newAuthRuleService(t *testing.T) (protoreflect.ExtensionType, protoreflect.ServiceDescriptor) {
+	optFile, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("auth/options.proto"),
+		Package:    proto.String("auth"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("AuthRule"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("scopes"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				JsonName: proto.String("scopes"),
+			}},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("rule"),
+			Number:   proto.Int32(50001),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(".auth.AuthRule"),
+			Extendee: proto.String(".google.protobuf.MethodOptions"),
+			JsonName: proto.String("rule"),
+		}},
+	}, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	xt := dynamicpb.NewExtensionType(optFile.Extensions().Get(0))
+	ruleMsg := dynamicpb.NewMessage(optFile.Messages().Get(0))
+	scopes := ruleMsg.Mutable(ruleMsg.Descriptor().Fields().ByName("scopes")).List()
+	scopes.Append(protoreflect.ValueOfString("weather.read"))
+
+	annotated := &descriptorpb.MethodOptions{}
+	proto.SetExtension(annotated, xt, ruleMsg)
+
+	files := new(protoregistry.Files)
+	require.NoError(t, files.RegisterFile(optFile))
+
+	svcFile, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("weather/service.proto"),
+		Package:    proto.String("weather"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"auth/options.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("WeatherRequest")},
+			{Name: proto.String("WeatherResponse")},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("WeatherService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{
+					Name:       proto.String("GetWeatherInfo"),
+					InputType:  proto.String(".weather.WeatherRequest"),
+					OutputType: proto.String(".weather.WeatherResponse"),
+					Options:    annotated,
+				},
+				{
+					Name:       proto.String("StreamWeather"),
+					InputType:  proto.String(".weather.WeatherRequest"),
+					OutputType: proto.String(".weather.WeatherResponse"),
+				},
+			},
+		}},
+	}, files)
+	require.NoError(t, err)
+
+	return xt, svcFile.Services().Get(0)
+}
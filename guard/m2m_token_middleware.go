@@ -36,6 +36,7 @@ func (guard AuthGuard) AuthenticateM2MMiddleware() lit.HandlerFunc {
 		// 4. Inject user information to request context
 		ctx := c.Request().Context()
 		ctx = iam.SetM2MProfileInContext(ctx, profile)
+		ctx = iam.SetAccessTokenInContext(ctx, tokenStr)
 		ctx = monitoring.InjectField(ctx, m2mIDKey, profile.ID())
 		c.SetRequestContext(ctx)
 
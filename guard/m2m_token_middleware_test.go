@@ -160,6 +160,7 @@ func TestAuthenticateM2MMiddleware(t *testing.T) {
 				require.Equal(t, expResult, respRecord.Body.Bytes())
 			} else {
 				require.Equal(t, tc.expResult, actualProfile)
+				require.Equal(t, tc.givenToken, iam.GetAccessTokenFromContext(ctx.Request().Context()))
 			}
 			mockInstance.AssertExpectations(t)
 		})
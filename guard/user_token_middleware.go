@@ -42,6 +42,7 @@ func (guard AuthGuard) AuthenticateUserMiddleware() lit.HandlerFunc {
 		// 4. Inject user information to request context
 		ctx := c.Request().Context()
 		ctx = iam.SetUserProfileInContext(ctx, profile)
+		ctx = iam.SetAccessTokenInContext(ctx, tokenStr)
 		ctx = monitoring.InjectFields(ctx, map[string]string{
 			userIDKey: profile.ID(),
 			roleKey:   profile.GetRoleString(),
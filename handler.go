@@ -5,13 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/http/pprof"
 
 	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
 	pkgerrors "github.com/pkg/errors"
 )
 
-// Handler defines a http.Handler that adds default readiness, liveness, profiling, test routes and cors policy
+// Handler defines a http.Handler that adds default liveness, profiling routes and cors policy
+// The liveness and profiling routes are moved to the AdminServer if HandlerWithAdminServer is given
 func Handler(
 	rootCtx context.Context,
 	corsConf CORSConfig,
@@ -30,35 +31,33 @@ func Handler(
 		rtr.ginRouter.Use(cors.New(corsConf.cfg))
 	}
 
-	r.Handle(http.MethodGet, "/_/healthz", WrapF(LivenessHandlerFunc))
+	if cfg.admin != nil {
+		// Internal routes are served by the admin server, only expose the public routes for listing
+		if rtr, ok := r.(router); ok {
+			if engine, ok := rtr.ginRouter.(*gin.Engine); ok {
+				cfg.admin.setPublicRoutes(engine.Routes)
+			}
+		}
+	} else {
+		r.Handle(http.MethodGet, "/_/healthz", WrapF(LivenessHandlerFunc))
 
-	// Usage on pprof refer to : https: //pkg.go.dev/net/http/pprof
-	// by default profilingDisabled is false
-	if !cfg.profilingDisabled {
-		const prefix = "/_/profile"
-		r.Handle(http.MethodGet, prefix+"/", WrapF(pprof.Index))
-		r.Handle(http.MethodGet, prefix+"/cmdline", WrapF(pprof.Cmdline))
-		r.Handle(http.MethodGet, prefix+"/profile", WrapF(pprof.Profile))
-		r.Handle(http.MethodPost, prefix+"/symbol", WrapF(pprof.Symbol))
-		r.Handle(http.MethodGet, prefix+"/symbol", WrapF(pprof.Symbol))
-		r.Handle(http.MethodGet, prefix+"/trace", WrapF(pprof.Trace))
-		r.Handle(http.MethodGet, prefix+"/allocs", WrapH(pprof.Handler("allocs")))
-		r.Handle(http.MethodGet, prefix+"/block", WrapH(pprof.Handler("block")))
-		r.Handle(http.MethodGet, prefix+"/goroutine", WrapH(pprof.Handler("goroutine")))
-		r.Handle(http.MethodGet, prefix+"/heap", WrapH(pprof.Handler("heap")))
-		r.Handle(http.MethodGet, prefix+"/mutex", WrapH(pprof.Handler("mutex")))
-		r.Handle(http.MethodGet, prefix+"/threadcreate", WrapH(pprof.Handler("threadcreate")))
+		// by default profilingDisabled is false
+		if !cfg.profilingDisabled {
+			registerProfilingRoutes(r)
+		}
 	}
 
 	r.Use(rootMiddleware(rootCtx))
 
 	// This route will help in testing integrations with monitoring system.
-	r.Get("/_/test-monitor", func(c Context) error {
-		c.JSON(http.StatusOK, map[string]string{
-			"message": "Test Invoked",
+	if cfg.testMonitorEnabled {
+		r.Get("/_/test-monitor", func(c Context) error {
+			c.JSON(http.StatusOK, map[string]string{
+				"message": "Test Invoked",
+			})
+			return pkgerrors.WithStack(errors.New("test error"))
 		})
-		return pkgerrors.WithStack(errors.New("test error"))
-	})
+	}
 
 	// Setup application router
 	routerFunc(r)
@@ -75,5 +74,7 @@ func LivenessHandlerFunc(w http.ResponseWriter, _ *http.Request) {
 
 // handlerConfig is configurations of the Handler
 type handlerConfig struct {
-	profilingDisabled bool
+	profilingDisabled  bool
+	testMonitorEnabled bool
+	admin              *AdminServer
 }
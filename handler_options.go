@@ -9,3 +9,18 @@ func HandlerWithProfilingDisabled() HandlerOption {
 		c.profilingDisabled = true
 	}
 }
+
+// HandlerWithTestMonitorEnabled registers the /_/test-monitor route, which always fails to test monitoring integrations
+func HandlerWithTestMonitorEnabled() HandlerOption {
+	return func(c *handlerConfig) {
+		c.testMonitorEnabled = true
+	}
+}
+
+// HandlerWithAdminServer moves the liveness and profiling routes to the given AdminServer,
+// and exposes the Handler's routes listing on it
+func HandlerWithAdminServer(admin *AdminServer) HandlerOption {
+	return func(c *handlerConfig) {
+		c.admin = admin
+	}
+}
@@ -35,7 +35,7 @@ func TestHandler(t *testing.T) {
 
 		"Health Check":           {method: "GET", url: "/_/healthz", expectedStatus: http.StatusOK, expectedBody: "ok\n"},
 		"Custom Route":           {method: "GET", url: "/test-route", expectedStatus: http.StatusOK, expectedBody: `{"message":"Hello, World!"}`},
-		"Test Monitor":           {method: "GET", url: "/_/test-monitor", expectedStatus: http.StatusOK, expectedBody: `{"message":"Test Invoked"}`},
+		"Test Monitor":           {method: "GET", url: "/_/test-monitor", expectedStatus: http.StatusNotFound, expectedBody: ""},
 		"Profiling Route":        {method: "GET", url: "/_/profile/", expectedStatus: http.StatusOK, expectedBody: ""},
 		"Allocs Profiling Route": {method: "GET", url: "/_/profile/allocs", expectedStatus: http.StatusOK, expectedBody: ""},
 		"Non-existent Route":     {method: "GET", url: "/not-found", expectedStatus: http.StatusNotFound, expectedBody: ""},
@@ -89,6 +89,66 @@ func TestHandlerWithProfilingDisabled(t *testing.T) {
 	}
 }
 
+func TestHandlerWithTestMonitorEnabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Create a test HTTP handler with test monitor route enabled
+	handler := Handler(
+		context.Background(),
+		NewCORSConfig([]string{"*"}),
+		mockRouter,
+		HandlerWithTestMonitorEnabled(),
+	)
+
+	// Create a test HTTP server
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL+"/_/test-monitor", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHandlerWithAdminServer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Create a test HTTP handler with internal routes moved to admin server
+	admin := NewAdminServer(":0")
+	handler := Handler(
+		context.Background(),
+		NewCORSConfig([]string{"*"}),
+		mockRouter,
+		HandlerWithAdminServer(admin),
+	)
+
+	// Create a test HTTP server
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	tests := map[string]struct {
+		url            string
+		expectedStatus int
+	}{
+		"Health Check":    {url: "/_/healthz", expectedStatus: http.StatusNotFound},
+		"Profiling Route": {url: "/_/profile/", expectedStatus: http.StatusNotFound},
+		"Custom Route":    {url: "/test-route", expectedStatus: http.StatusOK},
+	}
+
+	for scenario, tc := range tests {
+		t.Run(scenario, func(t *testing.T) {
+			req, err := http.NewRequest("GET", server.URL+tc.url, nil)
+			require.NoError(t, err)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
 func mockRouter(r Router) {
 	r.Get("/test-route", func(c Context) error {
 		c.JSON(http.StatusOK, map[string]string{"message": "Hello, World!"})
@@ -63,3 +63,32 @@ func NewWithAPIKey(cfg Config, pool *SharedCustomPool, apiKeyCfg APIKeyConfig, o
 	c.header.values[apiKeyCfg.Key] = apiKeyCfg.Value
 	return c, nil
 }
+
+// NewWithOAuth2ClientCredentials creates and returns a new Client instance authenticated with the access token of
+// the OAuth2 client credentials grant. The token is cached until shortly before it expires, and requested again once
+// when the service responds 401
+func NewWithOAuth2ClientCredentials(cfg Config, pool *SharedCustomPool, oauthCfg OAuth2ClientCredentialsConfig, opts ...ClientOption) (*Client, error) {
+	c, err := newClient(pool.Client, cfg.URL, cfg.Method, cfg.ServiceName, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.tokenSource, err = newTokenSource(oauthCfg, pool.Client); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewWithOAuth2PrivateKeyJWT creates and returns a new Client instance like NewWithOAuth2ClientCredentials, but
+// authenticates to the token endpoint with a client assertion signed by the private key instead of the client secret
+func NewWithOAuth2PrivateKeyJWT(cfg Config, pool *SharedCustomPool, oauthCfg OAuth2PrivateKeyJWTConfig, opts ...ClientOption) (*Client, error) {
+	c, err := newClient(pool.Client, cfg.URL, cfg.Method, cfg.ServiceName, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.tokenSource, err = newPrivateKeyJWTTokenSource(oauthCfg, pool.Client); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
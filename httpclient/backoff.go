@@ -0,0 +1,74 @@
+package httpclient
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff computes how long to wait before a retry
+type Backoff interface {
+	// Next returns the wait before the retry, retry starts from 1 and prev is the previous wait (0 for the first retry)
+	Next(retry int, prev time.Duration) time.Duration
+}
+
+// NewExponentialJitterBackoff returns the exponential backoff with full jitter, waiting a random duration
+// between 0 and min(max, base * 2^(retry-1))
+// Refer https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func NewExponentialJitterBackoff(base, max time.Duration) Backoff {
+	return exponentialJitterBackoff{base: base, max: max}
+}
+
+// NewConstantBackoff returns the backoff waiting the same duration before each retry
+func NewConstantBackoff(d time.Duration) Backoff {
+	return constantBackoff{d: d}
+}
+
+// NewDecorrelatedJitterBackoff returns the decorrelated jitter backoff, waiting a random duration
+// between base and min(max, prev * 3)
+// Refer https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+func NewDecorrelatedJitterBackoff(base, max time.Duration) Backoff {
+	return decorrelatedJitterBackoff{base: base, max: max}
+}
+
+type exponentialJitterBackoff struct {
+	base, max time.Duration
+}
+
+func (b exponentialJitterBackoff) Next(retry int, _ time.Duration) time.Duration {
+	ceil := b.max
+	if shift := retry - 1; shift < 62 { // Avoid overflow
+		if d := b.base << shift; d > 0 && d < ceil {
+			ceil = d
+		}
+	}
+	if ceil <= 0 {
+		return 0
+	}
+
+	return rand.N(ceil + 1)
+}
+
+type constantBackoff struct {
+	d time.Duration
+}
+
+func (b constantBackoff) Next(int, time.Duration) time.Duration {
+	return b.d
+}
+
+type decorrelatedJitterBackoff struct {
+	base, max time.Duration
+}
+
+func (b decorrelatedJitterBackoff) Next(_ int, prev time.Duration) time.Duration {
+	if prev < b.base {
+		prev = b.base
+	}
+
+	ceil := min(b.max, prev*3)
+	if ceil <= b.base {
+		return ceil
+	}
+
+	return b.base + rand.N(ceil-b.base+1)
+}
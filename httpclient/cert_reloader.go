@@ -0,0 +1,77 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"os"
+	"sync"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/viebiz/lit/monitoring"
+)
+
+// certReloader loads the client certificate from the files, reloading it when the files change
+type certReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// getClientCertificate implements tls.Config.GetClientCertificate
+func (r *certReloader) getClientCertificate(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if r.cert != nil && now.Sub(r.checkedAt) < r.interval {
+		return r.cert, nil
+	}
+	r.checkedAt = now
+
+	modTime, err := r.latestModTime()
+	if err == nil && r.cert != nil && modTime.Equal(r.modTime) {
+		return r.cert, nil
+	}
+	if err == nil {
+		var cert tls.Certificate
+		if cert, err = tls.LoadX509KeyPair(r.certFile, r.keyFile); err == nil {
+			r.cert, r.modTime = &cert, modTime
+			return r.cert, nil
+		}
+	}
+
+	if r.cert != nil { // Keep the current certificate, e.g. while the files are being rotated
+		ctx := context.Background()
+		if info != nil {
+			ctx = info.Context()
+		}
+		monitoring.FromContext(ctx).Warnf("[ext_http_req] failed to reload client certificate, keeping the previous one: (%+v)", err)
+		return r.cert, nil
+	}
+
+	return nil, pkgerrors.WithStack(err)
+}
+
+// latestModTime returns the latest modification time of the certificate & key files
+func (r *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,131 @@
+package httpclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertReloader_getClientCertificate(t *testing.T) {
+	tcs := map[string]struct {
+		givenRotation func(t *testing.T, certFile, keyFile string)
+		givenElapsed  time.Duration
+		expCN         string
+	}{
+		"cached within interval": {
+			givenRotation: func(t *testing.T, certFile, keyFile string) {
+				writeTestCertificate(t, certFile, keyFile, "client-v2", time.Now().Add(time.Hour))
+			},
+			givenElapsed: 30 * time.Second,
+			expCN:        "client-v1",
+		},
+		"reloaded after rotation": {
+			givenRotation: func(t *testing.T, certFile, keyFile string) {
+				writeTestCertificate(t, certFile, keyFile, "client-v2", time.Now().Add(time.Hour))
+			},
+			givenElapsed: time.Minute,
+			expCN:        "client-v2",
+		},
+		"unchanged files": {
+			givenElapsed: time.Minute,
+			expCN:        "client-v1",
+		},
+		"previous certificate kept when rotation is incomplete": {
+			givenRotation: func(t *testing.T, certFile, keyFile string) {
+				require.NoError(t, os.WriteFile(certFile, []byte("partial"), 0o600))
+				require.NoError(t, os.Chtimes(certFile, time.Now().Add(time.Hour), time.Now().Add(time.Hour)))
+			},
+			givenElapsed: time.Minute,
+			expCN:        "client-v1",
+		},
+		"previous certificate kept when files are removed": {
+			givenRotation: func(t *testing.T, certFile, keyFile string) {
+				require.NoError(t, os.Remove(keyFile))
+			},
+			givenElapsed: time.Minute,
+			expCN:        "client-v1",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			dir := t.TempDir()
+			certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+			writeTestCertificate(t, certFile, keyFile, "client-v1", time.Now())
+
+			now := time.Now()
+			r := &certReloader{certFile: certFile, keyFile: keyFile, interval: time.Minute, now: func() time.Time { return now }}
+			_, err := r.getClientCertificate(nil)
+			require.NoError(t, err)
+			if tc.givenRotation != nil {
+				tc.givenRotation(t, certFile, keyFile)
+			}
+			now = now.Add(tc.givenElapsed)
+
+			// When
+			cert, err := r.getClientCertificate(nil)
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, tc.expCN, cert.Leaf.Subject.CommonName)
+		})
+	}
+}
+
+func TestCertReloader_getClientCertificate_Error(t *testing.T) {
+	// Given
+	dir := t.TempDir()
+	r := &certReloader{certFile: filepath.Join(dir, "client.crt"), keyFile: filepath.Join(dir, "client.key"), now: time.Now}
+
+	// When
+	cert, err := r.getClientCertificate(nil)
+
+	// Then
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.Nil(t, cert)
+}
+
+// writeTestCertificate writes a self-signed client certificate & its key, modified at modTime
+func writeTestCertificate(t *testing.T, certFile, keyFile, cn string, modTime time.Time) {
+	certPEM, keyPEM := newTestCertificate(t, cn)
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+// newTestCertificate returns a PEM encoded self-signed client certificate & its key
+func newTestCertificate(t *testing.T, cn string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
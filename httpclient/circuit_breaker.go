@@ -0,0 +1,258 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/viebiz/lit/monitoring"
+	"github.com/viebiz/lit/monitoring/instrumenthttp"
+)
+
+const (
+	defaultCircuitOpenDuration   = 30 * time.Second
+	defaultCircuitWindow         = time.Minute
+	defaultCircuitMinRequests    = 10
+	defaultCircuitHalfOpenProbes = 1
+)
+
+// circuitBreakers holds the circuit breaker of each service name, so that all the clients calling
+// the same service share it
+var circuitBreakers sync.Map
+
+// CircuitBreakerFallback returns the response used in place of the call while the circuit is open.
+// err is ErrCircuitOpen
+type CircuitBreakerFallback func(ctx context.Context, p Payload, err error) (Response, error)
+
+// CircuitBreakerConfig holds the config of the circuit breaker of a service
+type CircuitBreakerConfig struct {
+	// Open the circuit after this number of consecutive failed calls. Setting to 0 disables it
+	ConsecutiveFailures int
+	// Open the circuit when the rate of failed calls in the window reaches this value (0 to 1).
+	// Setting to 0 disables it
+	ErrorRateThreshold float64
+	// Min number of calls in the window before the error rate is evaluated
+	// Default: 10
+	MinRequests int
+	// Length of the window the error rate is evaluated on
+	// Default: 1m
+	Window time.Duration
+	// How long the circuit stays open before letting probe calls through
+	// Default: 30s
+	OpenDuration time.Duration
+	// Number of probe calls let through while half-open, all of them have to succeed to close the circuit
+	// Default: 1
+	HalfOpenProbes int
+	// Fallback is called instead of returning ErrCircuitOpen while the circuit is open
+	// Default: nil
+	Fallback CircuitBreakerFallback
+}
+
+// IsValid checks if the config is valid or not
+func (cfg CircuitBreakerConfig) IsValid() error {
+	if cfg.ConsecutiveFailures <= 0 && cfg.ErrorRateThreshold <= 0 {
+		return pkgerrors.Wrap(ErrCircuitBreakerConfigInvalid, "either ConsecutiveFailures or ErrorRateThreshold is required")
+	}
+	if cfg.ConsecutiveFailures < 0 {
+		return pkgerrors.Wrap(ErrCircuitBreakerConfigInvalid, "ConsecutiveFailures should not be less than zero")
+	}
+	if cfg.ErrorRateThreshold < 0 || cfg.ErrorRateThreshold > 1 {
+		return pkgerrors.Wrap(ErrCircuitBreakerConfigInvalid, "ErrorRateThreshold should be between 0 and 1")
+	}
+	if cfg.MinRequests < 0 || cfg.Window < 0 || cfg.OpenDuration < 0 || cfg.HalfOpenProbes < 0 {
+		return pkgerrors.Wrap(ErrCircuitBreakerConfigInvalid, "MinRequests, Window, OpenDuration and HalfOpenProbes should not be less than zero")
+	}
+	return nil
+}
+
+func (cfg CircuitBreakerConfig) withDefaults() CircuitBreakerConfig {
+	if cfg.MinRequests == 0 {
+		cfg.MinRequests = defaultCircuitMinRequests
+	}
+	if cfg.Window == 0 {
+		cfg.Window = defaultCircuitWindow
+	}
+	if cfg.OpenDuration == 0 {
+		cfg.OpenDuration = defaultCircuitOpenDuration
+	}
+	if cfg.HalfOpenProbes == 0 {
+		cfg.HalfOpenProbes = defaultCircuitHalfOpenProbes
+	}
+	return cfg
+}
+
+// WithCircuitBreaker method fast-fails the calls with ErrCircuitOpen, or serves them from cfg.Fallback, once the service
+// is failing. The circuit breaker is shared by all the clients with the same service name, the thresholds of the first
+// client created win while the fallback stays per client.
+// A call fails when it returns an error (except the cancellation of the caller context) or a 5xx status code
+func WithCircuitBreaker(cfg CircuitBreakerConfig) ClientOption {
+	return func(c *Client) {
+		c.circuitBreakerCfg = &cfg
+	}
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// callOutcome is the result of a call let through by the circuit breaker
+type callOutcome int
+
+const (
+	callSucceeded callOutcome = iota
+	callFailed
+	callIgnored // The call ended without telling about the service health, i.e. canceled by the caller
+)
+
+// circuitBreaker opens on consecutive failures or on the error rate of a fixed window,
+// and lets a number of probe calls through once the open duration elapsed
+type circuitBreaker struct {
+	serviceName string
+	cfg         CircuitBreakerConfig
+	now         func() time.Time
+
+	mu                  sync.Mutex
+	state               circuitState
+	consecutiveFailures int
+	windowStart         time.Time
+	windowCalls         int
+	windowFailures      int
+	openedAt            time.Time
+	probesInFlight      int
+	probesSucceeded     int
+	// Incremented on each transition, so that the outcome of the calls let through in a previous state is ignored
+	generation uint64
+}
+
+func newCircuitBreaker(serviceName string, cfg CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{serviceName: serviceName, cfg: cfg.withDefaults(), now: time.Now}
+}
+
+// getCircuitBreaker returns the circuit breaker of the service, creating it with the config if none exists yet
+func getCircuitBreaker(serviceName string, cfg CircuitBreakerConfig) *circuitBreaker {
+	b, _ := circuitBreakers.LoadOrStore(serviceName, newCircuitBreaker(serviceName, cfg))
+	return b.(*circuitBreaker)
+}
+
+// allow reports whether the call can be sent, the returned generation has to be passed to record
+func (b *circuitBreaker) allow(ctx context.Context) (uint64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitOpen {
+		if b.now().Sub(b.openedAt) < b.cfg.OpenDuration {
+			return 0, false
+		}
+		b.transition(ctx, circuitHalfOpen)
+	}
+
+	if b.state == circuitHalfOpen {
+		if b.probesInFlight+b.probesSucceeded >= b.cfg.HalfOpenProbes {
+			return 0, false // Enough probes are in flight
+		}
+		b.probesInFlight++
+	}
+
+	return b.generation, true
+}
+
+// record updates the state with the outcome of a call let through by allow
+func (b *circuitBreaker) record(ctx context.Context, generation uint64, outcome callOutcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return // The state changed while the call was in flight
+	}
+
+	switch b.state {
+	case circuitHalfOpen:
+		b.probesInFlight--
+		switch outcome {
+		case callFailed:
+			b.transition(ctx, circuitOpen)
+		case callSucceeded:
+			b.probesSucceeded++
+			if b.probesSucceeded >= b.cfg.HalfOpenProbes {
+				b.transition(ctx, circuitClosed)
+			}
+		}
+	case circuitClosed:
+		if outcome == callIgnored {
+			return
+		}
+
+		now := b.now()
+		if now.Sub(b.windowStart) >= b.cfg.Window {
+			b.windowStart, b.windowCalls, b.windowFailures = now, 0, 0
+		}
+		b.windowCalls++
+
+		if outcome == callSucceeded {
+			b.consecutiveFailures = 0
+			return
+		}
+		b.consecutiveFailures++
+		b.windowFailures++
+
+		if b.cfg.ConsecutiveFailures > 0 && b.consecutiveFailures >= b.cfg.ConsecutiveFailures {
+			b.transition(ctx, circuitOpen)
+			return
+		}
+		if b.cfg.ErrorRateThreshold > 0 && b.windowCalls >= b.cfg.MinRequests &&
+			float64(b.windowFailures)/float64(b.windowCalls) >= b.cfg.ErrorRateThreshold {
+			b.transition(ctx, circuitOpen)
+		}
+	}
+}
+
+// transition moves the circuit to the state, must be called with the lock held
+func (b *circuitBreaker) transition(ctx context.Context, state circuitState) {
+	prev := b.state
+	b.state = state
+	b.generation++
+	b.consecutiveFailures, b.probesInFlight, b.probesSucceeded = 0, 0, 0
+
+	switch state {
+	case circuitOpen:
+		b.openedAt = b.now()
+		monitoring.FromContext(ctx).Warnf("[ext_http_req] circuit breaker of %s opened, calls are cut off for %s", b.serviceName, b.cfg.OpenDuration)
+	case circuitHalfOpen:
+		monitoring.FromContext(ctx).Infof("[ext_http_req] circuit breaker of %s half-opened, probing with %d call(s)", b.serviceName, b.cfg.HalfOpenProbes)
+	case circuitClosed:
+		b.windowStart, b.windowCalls, b.windowFailures = b.now(), 0, 0
+		monitoring.FromContext(ctx).Infof("[ext_http_req] circuit breaker of %s closed", b.serviceName)
+	}
+
+	instrumenthttp.RecordCircuitBreakerStateChange(ctx, b.serviceName, prev.String(), state.String())
+}
+
+// callOutcomeOf classifies the result of Client.execute for the circuit breaker
+func callOutcomeOf(resp Response, err error) callOutcome {
+	switch {
+	case errors.Is(err, ErrOperationContextCanceled):
+		return callIgnored
+	case err != nil, resp.Status >= http.StatusInternalServerError:
+		return callFailed
+	default:
+		return callSucceeded
+	}
+}
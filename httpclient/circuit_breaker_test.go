@@ -0,0 +1,308 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/viebiz/lit/monitoring/tracing/mocktracer"
+)
+
+func TestCircuitBreakerConfig_IsValid(t *testing.T) {
+	tcs := map[string]struct {
+		given  CircuitBreakerConfig
+		expErr string
+	}{
+		"consecutive failures": {
+			given: CircuitBreakerConfig{ConsecutiveFailures: 5},
+		},
+		"error rate": {
+			given: CircuitBreakerConfig{ErrorRateThreshold: 0.5, MinRequests: 20},
+		},
+		"error - no threshold": {
+			given:  CircuitBreakerConfig{OpenDuration: time.Second},
+			expErr: "either ConsecutiveFailures or ErrorRateThreshold is required: circuit breaker config invalid",
+		},
+		"error - error rate out of range": {
+			given:  CircuitBreakerConfig{ErrorRateThreshold: 1.5},
+			expErr: "ErrorRateThreshold should be between 0 and 1: circuit breaker config invalid",
+		},
+		"error - negative duration": {
+			given:  CircuitBreakerConfig{ConsecutiveFailures: 1, OpenDuration: -time.Second},
+			expErr: "MinRequests, Window, OpenDuration and HalfOpenProbes should not be less than zero: circuit breaker config invalid",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given & When
+			err := tc.given.IsValid()
+
+			// Then
+			if tc.expErr != "" {
+				require.EqualError(t, err, tc.expErr)
+				require.ErrorIs(t, err, ErrCircuitBreakerConfigInvalid)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	type step struct {
+		elapsed    time.Duration // Moves the clock before the call
+		outcome    callOutcome
+		expAllowed bool
+		expState   circuitState // State after the call
+	}
+
+	tcs := map[string]struct {
+		givenCfg CircuitBreakerConfig
+		steps    []step
+	}{
+		"open on consecutive failures": {
+			givenCfg: CircuitBreakerConfig{ConsecutiveFailures: 2, OpenDuration: time.Second},
+			steps: []step{
+				{outcome: callFailed, expAllowed: true, expState: circuitClosed},
+				{outcome: callSucceeded, expAllowed: true, expState: circuitClosed},
+				{outcome: callFailed, expAllowed: true, expState: circuitClosed},
+				{outcome: callFailed, expAllowed: true, expState: circuitOpen},
+				{elapsed: 500 * time.Millisecond, expAllowed: false, expState: circuitOpen},
+			},
+		},
+		"open on error rate": {
+			givenCfg: CircuitBreakerConfig{ErrorRateThreshold: 0.5, MinRequests: 4, Window: time.Minute},
+			steps: []step{
+				{outcome: callFailed, expAllowed: true, expState: circuitClosed},
+				{outcome: callSucceeded, expAllowed: true, expState: circuitClosed},
+				{outcome: callSucceeded, expAllowed: true, expState: circuitClosed},
+				{outcome: callFailed, expAllowed: true, expState: circuitOpen},
+			},
+		},
+		"error rate window resets": {
+			givenCfg: CircuitBreakerConfig{ErrorRateThreshold: 0.5, MinRequests: 2, Window: time.Minute},
+			steps: []step{
+				{outcome: callFailed, expAllowed: true, expState: circuitClosed},
+				{elapsed: time.Minute, outcome: callSucceeded, expAllowed: true, expState: circuitClosed},
+				{outcome: callSucceeded, expAllowed: true, expState: circuitClosed},
+			},
+		},
+		"ignored outcome is not counted": {
+			givenCfg: CircuitBreakerConfig{ConsecutiveFailures: 2},
+			steps: []step{
+				{outcome: callFailed, expAllowed: true, expState: circuitClosed},
+				{outcome: callIgnored, expAllowed: true, expState: circuitClosed},
+				{outcome: callFailed, expAllowed: true, expState: circuitOpen},
+			},
+		},
+		"half-open probe closes the circuit": {
+			givenCfg: CircuitBreakerConfig{ConsecutiveFailures: 1, OpenDuration: time.Second, HalfOpenProbes: 2},
+			steps: []step{
+				{outcome: callFailed, expAllowed: true, expState: circuitOpen},
+				{elapsed: time.Second, outcome: callSucceeded, expAllowed: true, expState: circuitHalfOpen},
+				{outcome: callSucceeded, expAllowed: true, expState: circuitClosed},
+			},
+		},
+		"half-open probe failure reopens the circuit": {
+			givenCfg: CircuitBreakerConfig{ConsecutiveFailures: 1, OpenDuration: time.Second},
+			steps: []step{
+				{outcome: callFailed, expAllowed: true, expState: circuitOpen},
+				{elapsed: time.Second, outcome: callFailed, expAllowed: true, expState: circuitOpen},
+				{elapsed: 500 * time.Millisecond, expAllowed: false, expState: circuitOpen},
+			},
+		},
+		"canceled half-open probe frees the slot": {
+			givenCfg: CircuitBreakerConfig{ConsecutiveFailures: 1, OpenDuration: time.Second},
+			steps: []step{
+				{outcome: callFailed, expAllowed: true, expState: circuitOpen},
+				{elapsed: time.Second, outcome: callIgnored, expAllowed: true, expState: circuitHalfOpen},
+				{outcome: callSucceeded, expAllowed: true, expState: circuitClosed},
+			},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			now := time.Now()
+			b := newCircuitBreaker("svc", tc.givenCfg)
+			b.now = func() time.Time { return now }
+
+			for i, s := range tc.steps {
+				now = now.Add(s.elapsed)
+
+				// When
+				gen, allowed := b.allow(context.Background())
+				if allowed {
+					b.record(context.Background(), gen, s.outcome)
+				}
+
+				// Then
+				require.Equal(t, s.expAllowed, allowed, "step %d", i)
+				require.Equal(t, s.expState, b.state, "step %d", i)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_StaleOutcome(t *testing.T) {
+	// Given
+	b := newCircuitBreaker("svc", CircuitBreakerConfig{ConsecutiveFailures: 1})
+	slowGen, allowed := b.allow(context.Background())
+	require.True(t, allowed)
+	gen, allowed := b.allow(context.Background())
+	require.True(t, allowed)
+	b.record(context.Background(), gen, callFailed)
+	require.Equal(t, circuitOpen, b.state)
+
+	// When
+	b.record(context.Background(), slowGen, callSucceeded)
+
+	// Then
+	require.Equal(t, circuitOpen, b.state)
+}
+
+func TestClient_Send_CircuitBreaker(t *testing.T) {
+	tp := mocktracer.Start()
+	defer tp.Stop()
+
+	fallbackResp := Response{Status: http.StatusOK, Body: []byte(`{"cached":true}`)}
+
+	tcs := map[string]struct {
+		givenFallback CircuitBreakerFallback
+		expResp       Response
+		expErr        error
+	}{
+		"fast-fail": {
+			expErr: ErrCircuitOpen,
+		},
+		"fallback": {
+			givenFallback: func(ctx context.Context, p Payload, err error) (Response, error) {
+				if !errors.Is(err, ErrCircuitOpen) {
+					return Response{}, err
+				}
+				return fallbackResp, nil
+			},
+			expResp: fallbackResp,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			tp.Reset()
+
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(http.StatusBadGateway)
+			}))
+			defer srv.Close()
+
+			c, err := NewUnauthenticated(
+				Config{URL: srv.URL, Method: http.MethodGet, ServiceName: "cb-" + scenario},
+				NewSharedCustomPool(),
+				WithCircuitBreaker(CircuitBreakerConfig{ConsecutiveFailures: 2, OpenDuration: time.Minute, Fallback: tc.givenFallback}),
+			)
+			require.NoError(t, err)
+
+			for range 2 {
+				resp, err := c.Send(context.Background(), Payload{})
+				require.NoError(t, err)
+				require.Equal(t, http.StatusBadGateway, resp.Status)
+			}
+
+			// When
+			resp, err := c.Send(context.Background(), Payload{})
+
+			// Then
+			require.Equal(t, tc.expErr, err)
+			require.Equal(t, tc.expResp, resp)
+			require.Equal(t, int32(2), calls.Load())
+
+			var stateEvents []string
+			for _, span := range tp.GetSpans() {
+				for _, event := range span.Events {
+					if event.Name != "circuit_breaker.state_change" {
+						continue
+					}
+					require.Contains(t, event.Attributes, attribute.String("service.name", "cb-"+scenario))
+					for _, attr := range event.Attributes {
+						if attr.Key == "circuit_breaker.state" {
+							stateEvents = append(stateEvents, attr.Value.AsString())
+						}
+					}
+				}
+			}
+			require.Equal(t, []string{"open"}, stateEvents)
+		})
+	}
+}
+
+func TestClient_SendStream_CircuitBreakerFallback(t *testing.T) {
+	// Given
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c, err := NewUnauthenticated(
+		Config{URL: srv.URL, Method: http.MethodGet, ServiceName: "cb-stream-fallback"},
+		NewSharedCustomPool(),
+		WithCircuitBreaker(CircuitBreakerConfig{
+			ConsecutiveFailures: 1,
+			OpenDuration:        time.Minute,
+			Fallback: func(ctx context.Context, p Payload, err error) (Response, error) {
+				return Response{Status: http.StatusOK, Body: []byte(`{"cached":true}`)}, nil
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	resp, err := c.SendStream(context.Background(), Payload{})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadGateway, resp.Status)
+	require.NoError(t, resp.Body.Close())
+
+	// When
+	resp, err = c.SendStream(context.Background(), Payload{})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.Status)
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, `{"cached":true}`, string(b))
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestNewClient_CircuitBreakerSharedByService(t *testing.T) {
+	// Given
+	cfg := Config{URL: "https://localhost:1604/api/v1/users", Method: http.MethodGet, ServiceName: "cb-shared"}
+	pool := NewSharedCustomPool()
+
+	// When
+	c1, err := NewUnauthenticated(cfg, pool, WithCircuitBreaker(CircuitBreakerConfig{ConsecutiveFailures: 1}))
+	require.NoError(t, err)
+	cfg.URL = "https://localhost:1604/api/v1/orders"
+	c2, err := NewUnauthenticated(cfg, pool, WithCircuitBreaker(CircuitBreakerConfig{ConsecutiveFailures: 5}))
+	require.NoError(t, err)
+	_, err = NewUnauthenticated(cfg, pool, WithCircuitBreaker(CircuitBreakerConfig{}))
+
+	// Then
+	require.Same(t, c1.circuitBreaker, c2.circuitBreaker)
+	require.Equal(t, 1, c2.circuitBreaker.cfg.ConsecutiveFailures)
+	require.ErrorIs(t, err, ErrCircuitBreakerConfigInvalid)
+}
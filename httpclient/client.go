@@ -5,7 +5,9 @@ import (
 	"strings"
 
 	pkgerrors "github.com/pkg/errors"
+	"github.com/viebiz/lit/httpsig"
 	"github.com/viebiz/lit/monitoring"
+	"golang.org/x/sync/singleflight"
 )
 
 // A Client describes an HTTP endpoint's client. This client is
@@ -37,8 +39,38 @@ type Client struct {
 	// Default request header configuration
 	header header
 
+	// OAuth2 access token of the requests
+	// Default: nil
+	tokenSource *tokenSource
+
 	timeoutAndRetryOption timeoutAndRetryOption
 
+	// Retry policy replacing the retries of timeoutAndRetryOption
+	// Default: nil
+	retryPolicy *RetryPolicy
+
+	// Circuit breaker shared by the clients of the service
+	// Default: nil
+	circuitBreakerCfg *CircuitBreakerConfig
+	circuitBreaker    *circuitBreaker
+
+	// Statuses decoded as success by DoJSON
+	// Default: 2xx
+	successStatuses map[int]bool
+
+	// HTTP Message Signature signer of the requests
+	// Default: nil
+	signer *httpsig.Signer
+
+	// Response cache of the GET requests
+	// Default: nil
+	responseCache *httpCache
+
+	// Coalesce the concurrent identical GET requests
+	// Default: false
+	coalescing bool
+	inflight   singleflight.Group
+
 	// Disable request body logging
 	// Default: false,
 	disableReqBodyLogging bool
@@ -101,6 +133,13 @@ func newClient(
 		return nil, err
 	}
 
+	if c.circuitBreakerCfg != nil {
+		if err := c.circuitBreakerCfg.IsValid(); err != nil {
+			return nil, err
+		}
+		c.circuitBreaker = getCircuitBreaker(c.serviceName, *c.circuitBreakerCfg)
+	}
+
 	// TODO: Setup user agent based on configs, so use default go client user-agent
 	//c.userAgent = fmt.Sprintf(
 	//	"%s/%s (%s)",
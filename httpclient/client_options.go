@@ -46,3 +46,13 @@ func DisableResponseBodyLogging() ClientOption {
 		c.disableRespBodyLogging = true
 	}
 }
+
+// WithSuccessStatuses method overrides the statuses counted as success by DoJSON, the others are returned as *StatusError
+func WithSuccessStatuses(statuses ...int) ClientOption {
+	return func(c *Client) {
+		c.successStatuses = make(map[int]bool, len(statuses))
+		for _, s := range statuses {
+			c.successStatuses[s] = true
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/viebiz/lit/monitoring"
+	"github.com/viebiz/lit/monitoring/instrumenthttp"
+)
+
+const requestKeyPrefix = "lit:httpclient:cache:"
+
+// WithRequestCoalescing method coalesces the concurrent identical GET requests (i.e. same URL & headers) into
+// one request in flight, whose response is shared by the callers. The shared request is bounded by the max wait
+// including retries and is not canceled when the caller that started it gives up
+func WithRequestCoalescing() ClientOption {
+	return func(c *Client) {
+		c.coalescing = true
+	}
+}
+
+// getResult is the result of the GET request shared by the coalesced callers
+type getResult struct {
+	resp        Response
+	cacheStatus string
+}
+
+// sendGET executes the GET request through the response cache, coalescing the identical requests in flight
+func (c *Client) sendGET(ctx, ctxTimeout context.Context, endpointURL string, p Payload) (Response, error) {
+	reqHeader := c.requestHeader(p)
+	key := c.requestKey(endpointURL, reqHeader)
+
+	hc := c.responseCache
+	if hc != nil {
+		if _, noStore := parseCacheControl(reqHeader.Values("Cache-Control"))["no-store"]; noStore {
+			instrumenthttp.RecordCacheStatus(ctx, cacheStatusBypass, false)
+			hc = nil
+		}
+	}
+
+	var cached *CachedResponse
+	if hc != nil {
+		if cached = hc.lookup(ctx, key, reqHeader); cached != nil && hc.isFresh(cached, reqHeader) {
+			monitoring.FromContext(ctx).Infof("[ext_http_req] serving cached response")
+			instrumenthttp.RecordCacheStatus(ctx, cacheStatusHit, true)
+			return cached.response(), nil
+		}
+	}
+
+	fetch := func(ctx context.Context) (getResult, error) {
+		if cached != nil { // Revalidate the stale response
+			p.Header = withValidators(p.Header, cached.Header)
+		}
+
+		resp, _, err := c.call(ctx, ctx, endpointURL, p, false)
+		if err != nil || hc == nil {
+			return getResult{resp: resp}, err
+		}
+
+		if resp.Status == http.StatusNotModified && cached != nil {
+			refreshed := hc.revalidate(ctx, key, reqHeader, *cached, resp)
+			return getResult{resp: refreshed.response(), cacheStatus: cacheStatusRevalidated}, nil
+		}
+		hc.store(ctx, key, reqHeader, resp)
+
+		return getResult{resp: resp, cacheStatus: cacheStatusMiss}, nil
+	}
+
+	var rs getResult
+	var err error
+	if c.coalescing {
+		rs, err = c.coalesce(ctx, ctxTimeout, key, fetch)
+	} else {
+		rs, err = fetch(ctxTimeout)
+	}
+	if err != nil {
+		return Response{}, err
+	}
+	if rs.cacheStatus != "" {
+		instrumenthttp.RecordCacheStatus(ctx, rs.cacheStatus, false)
+	}
+
+	return rs.resp, nil
+}
+
+// coalesce executes fetch once for the concurrent callers of the same key, each caller waits until its ctxTimeout
+func (c *Client) coalesce(
+	ctx context.Context,
+	ctxTimeout context.Context,
+	key string,
+	fetch func(context.Context) (getResult, error),
+) (getResult, error) {
+	var leader bool
+	ch := c.inflight.DoChan(key, func() (interface{}, error) {
+		leader = true
+
+		// Detached from the cancellation of the leader, since the followers wait for it too
+		sharedCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.timeoutAndRetryOption.maxWaitInclRetries)
+		defer cancel()
+
+		return fetch(sharedCtx)
+	})
+
+	select {
+	case r := <-ch:
+		if r.Err != nil {
+			return getResult{}, r.Err
+		}
+		if !leader {
+			monitoring.FromContext(ctx).Infof("[ext_http_req] coalesced with identical request in flight")
+			instrumenthttp.RecordCoalesced(ctx)
+		}
+
+		// Each caller, the leader included, gets its own copy of the mutable header & body
+		rs := r.Val.(getResult)
+		rs.resp.Header = rs.resp.Header.Clone()
+		rs.resp.Body = bytes.Clone(rs.resp.Body)
+		return rs, nil
+	case <-ctxTimeout.Done():
+		if errors.Is(ctxTimeout.Err(), context.DeadlineExceeded) {
+			return getResult{}, ErrOverflowMaxWait
+		}
+		return getResult{}, ErrOperationContextCanceled
+	}
+}
+
+// requestKey returns the key of the GET request, identical requests share the same key. The key covers the headers
+// sent, e.g. the API key, and the OAuth2 client, so that the clients sharing a ResponseCache with different
+// credentials do not get the responses of each other
+func (c *Client) requestKey(endpointURL string, reqHeader http.Header) string {
+	names := make([]string, 0, len(reqHeader))
+	for name := range reqHeader {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	h.Write([]byte(c.method + " " + endpointURL))
+	for _, name := range names {
+		h.Write([]byte("\n" + name + ": " + strings.Join(reqHeader.Values(name), ", ")))
+	}
+	if c.tokenSource != nil {
+		h.Write([]byte("\n" + headerAuthorization + ": " + c.tokenSource.cacheKey))
+	}
+
+	return requestKeyPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// requestHeader returns the header sent with the request, excluding the Authorization of the token
+func (c *Client) requestHeader(p Payload) http.Header {
+	r := &http.Request{Header: http.Header{}}
+	c.setHeader(r, p)
+
+	return r.Header
+}
+
+// response returns the cached response as Response
+func (cr CachedResponse) response() Response {
+	return Response{Status: cr.Status, Header: cr.Header.Clone(), Body: bytes.Clone(cr.Body)}
+}
+
+// withValidators returns a copy of the header with the conditional headers to revalidate the cached response
+func withValidators(header map[string]string, cachedHeader http.Header) map[string]string {
+	h := make(map[string]string, len(header)+2)
+	for k, v := range header {
+		h[k] = v
+	}
+	if etag := cachedHeader.Get("ETag"); etag != "" {
+		h["If-None-Match"] = etag
+	} else if lastModified := cachedHeader.Get("Last-Modified"); lastModified != "" {
+		h["If-Modified-Since"] = lastModified
+	}
+
+	return h
+}
@@ -0,0 +1,156 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/viebiz/lit/monitoring/tracing/mocktracer"
+)
+
+func TestWithRequestCoalescing(t *testing.T) {
+	tp := mocktracer.Start()
+	defer tp.Stop()
+
+	tcs := map[string]struct {
+		givenPayloads []Payload
+		expCalls      int32
+		expCoalesced  int
+	}{
+		"identical requests": {
+			givenPayloads: []Payload{
+				{PathVars: map[string]string{"id": "1"}, Header: map[string]string{"X-Tenant": "a"}},
+				{PathVars: map[string]string{"id": "1"}, Header: map[string]string{"x-tenant": "a"}},
+				{PathVars: map[string]string{"id": "1"}, Header: map[string]string{"X-Tenant": "a"}},
+			},
+			expCalls:     1,
+			expCoalesced: 2,
+		},
+		"different urls": {
+			givenPayloads: []Payload{
+				{PathVars: map[string]string{"id": "1"}},
+				{PathVars: map[string]string{"id": "2"}},
+			},
+			expCalls: 2,
+		},
+		"different headers": {
+			givenPayloads: []Payload{
+				{PathVars: map[string]string{"id": "1"}, Header: map[string]string{"X-Tenant": "a"}},
+				{PathVars: map[string]string{"id": "1"}, Header: map[string]string{"X-Tenant": "b"}},
+			},
+			expCalls: 2,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			tp.Reset()
+
+			var calls atomic.Int32
+			release := make(chan struct{})
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				<-release
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(r.URL.Path))
+			}))
+			defer srv.Close()
+
+			c, err := NewUnauthenticated(
+				Config{URL: srv.URL + "/orders/:id", Method: http.MethodGet, ServiceName: "orders"},
+				NewSharedCustomPool(),
+				WithRequestCoalescing(),
+			)
+			require.NoError(t, err)
+
+			// When
+			var wg sync.WaitGroup
+			resps := make([]Response, len(tc.givenPayloads))
+			errs := make([]error, len(tc.givenPayloads))
+			for idx, p := range tc.givenPayloads {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					resps[idx], errs[idx] = c.Send(context.Background(), p)
+				}()
+			}
+			require.Eventually(t, func() bool { return calls.Load() == tc.expCalls }, time.Second, time.Millisecond)
+			time.Sleep(50 * time.Millisecond) // Let the identical requests join the request in flight
+			close(release)
+			wg.Wait()
+
+			// Then
+			require.Equal(t, tc.expCalls, calls.Load())
+			bodies := map[*byte]bool{}
+			for idx, p := range tc.givenPayloads {
+				require.NoError(t, errs[idx])
+				require.Equal(t, http.StatusOK, resps[idx].Status)
+				require.Equal(t, "/orders/"+p.PathVars["id"], string(resps[idx].Body))
+				bodies[&resps[idx].Body[0]] = true
+			}
+			require.Len(t, bodies, len(tc.givenPayloads)) // Each caller has its own copy
+
+			var coalesced int
+			for _, span := range tp.GetSpans() {
+				for _, attr := range span.Attributes {
+					if attr == attribute.Bool("http.client.coalesced", true) {
+						coalesced++
+					}
+				}
+			}
+			require.Equal(t, tc.expCoalesced, coalesced)
+		})
+	}
+}
+
+func TestWithRequestCoalescing_CallerGivesUp(t *testing.T) {
+	// Given
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c, err := NewUnauthenticated(
+		Config{URL: srv.URL, Method: http.MethodGet, ServiceName: "orders"},
+		NewSharedCustomPool(),
+		WithRequestCoalescing(),
+	)
+	require.NoError(t, err)
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.Send(leaderCtx, Payload{})
+		leaderErr <- err
+	}()
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	followerResp := make(chan Response, 1)
+	go func() {
+		resp, err := c.Send(context.Background(), Payload{})
+		require.NoError(t, err)
+		followerResp <- resp
+	}()
+	time.Sleep(50 * time.Millisecond) // Let the follower join the request in flight
+
+	// When
+	cancel()
+	require.ErrorIs(t, <-leaderErr, ErrOperationContextCanceled)
+	close(release)
+
+	// Then
+	require.Equal(t, http.StatusOK, (<-followerResp).Status)
+	require.Equal(t, int32(1), calls.Load())
+}
@@ -12,6 +12,10 @@ const (
 	// Reference: https://www.loginradius.com/blog/async/tune-the-go-http-client-for-high-performance/
 	defaultMaxIdleConnsPerHost = 100
 
+	// Same as http.DefaultTransport
+	defaultDialTimeout   = 30 * time.Second
+	defaultDialKeepAlive = 30 * time.Second
+
 	defaultRetryOnTimeout           = false
 	defaultMaxRetriesOnErrOrTimeout = 0
 	defaultMaxWaitInclRetries       = 15 * time.Second
@@ -2,6 +2,8 @@ package httpclient
 
 import (
 	"errors"
+
+	"github.com/viebiz/lit/internal/oauth2"
 )
 
 var (
@@ -12,4 +14,11 @@ var (
 	ErrMissingURL                   = errors.New("url is missing")
 	ErrMissingMethod                = errors.New("method is missing")
 	ErrMissingServiceName           = errors.New("missing service name")
+	ErrCircuitBreakerConfigInvalid  = errors.New("circuit breaker config invalid")
+	ErrCircuitOpen                  = errors.New("circuit breaker is open")
+	ErrMissingTokenURL              = errors.New("missing token url")
+	ErrMissingClientID              = errors.New("missing client id")
+	ErrMissingSigningKey            = errors.New("missing signing key")
+	ErrInvalidTokenResponse         = oauth2.ErrInvalidTokenResponse
+	ErrStreamedBodyNotSignable      = errors.New("streamed body cannot be signed without content digest")
 )
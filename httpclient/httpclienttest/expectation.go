@@ -0,0 +1,247 @@
+package httpclienttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Response is a canned response of the Server
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	// Delay before responding, e.g. to exceed the client timeout
+	Delay time.Duration
+	// CloseConnection closes the connection without responding, e.g. to simulate a network failure
+	CloseConnection bool
+}
+
+// Expectation is an outbound call expected by the Server
+type Expectation struct {
+	method  string
+	path    string
+	query   url.Values
+	header  http.Header
+	vars    map[string]string
+	body    func([]byte) bool
+	bodyDoc string // Describes the body matcher in the failures
+	times   int    // -1 means any times
+
+	mu        sync.Mutex
+	responses []Response
+	calls     int
+}
+
+// WithQuery expects the query param of the request to have the value
+func (e *Expectation) WithQuery(key, value string) *Expectation {
+	e.query.Add(key, value)
+	return e
+}
+
+// WithHeader expects the request header to have the value
+func (e *Expectation) WithHeader(key, value string) *Expectation {
+	e.header.Add(key, value)
+	return e
+}
+
+// WithPathVar expects the path variable (i.e. :name in the path of the expectation) to have the value
+func (e *Expectation) WithPathVar(name, value string) *Expectation {
+	e.vars[name] = value
+	return e
+}
+
+// WithJSONBody expects the request body to be JSON equal to expected, regardless of whitespaces and keys order
+func (e *Expectation) WithJSONBody(expected string) *Expectation {
+	var exp any
+	if err := json.Unmarshal([]byte(expected), &exp); err != nil {
+		panic(fmt.Sprintf("httpclienttest: invalid expected JSON body %s: %v", expected, err))
+	}
+
+	e.bodyDoc = "JSON body " + expected
+	e.body = func(b []byte) bool {
+		var got any
+		return json.Unmarshal(b, &got) == nil && reflect.DeepEqual(exp, got)
+	}
+	return e
+}
+
+// WithBody expects the request body to be equal to expected
+func (e *Expectation) WithBody(expected string) *Expectation {
+	e.bodyDoc = "body " + expected
+	e.body = func(b []byte) bool {
+		return bytes.Equal(b, []byte(expected))
+	}
+	return e
+}
+
+// WithBodyMatcher expects the request body to satisfy the matcher
+func (e *Expectation) WithBodyMatcher(matcher func(body []byte) bool) *Expectation {
+	e.bodyDoc = "body matching the matcher"
+	e.body = matcher
+	return e
+}
+
+// Times expects the call to be made exactly n times
+// Default: 1
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// AnyTimes expects the call to be made any number of times, including none
+func (e *Expectation) AnyTimes() *Expectation {
+	e.times = -1
+	return e
+}
+
+// Reply adds the response with the status and body. The responses are replied in order, the last one is repeated
+// Default: 200 without body
+func (e *Expectation) Reply(status int, body string) *Expectation {
+	return e.ReplyWith(Response{Status: status, Body: []byte(body)})
+}
+
+// ReplyJSON adds the response with the status and the JSON encoded body
+func (e *Expectation) ReplyJSON(status int, v any) *Expectation {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("httpclienttest: failed to encode the response body: %v", err))
+	}
+
+	return e.ReplyWith(Response{
+		Status: status,
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   b,
+	})
+}
+
+// ReplyWith adds the response
+func (e *Expectation) ReplyWith(resp Response) *Expectation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.responses = append(e.responses, resp)
+	return e
+}
+
+// Calls returns the number of calls matched so far
+func (e *Expectation) Calls() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.calls
+}
+
+func (e *Expectation) String() string {
+	var b strings.Builder
+	b.WriteString(e.method + " " + e.path)
+	if len(e.query) > 0 {
+		b.WriteString("?" + e.query.Encode())
+	}
+	for k, v := range e.header {
+		b.WriteString(fmt.Sprintf(" header %s=%s", k, strings.Join(v, ",")))
+	}
+	for k, v := range e.vars {
+		b.WriteString(fmt.Sprintf(" var %s=%s", k, v))
+	}
+	if e.bodyDoc != "" {
+		b.WriteString(" with " + e.bodyDoc)
+	}
+
+	return b.String()
+}
+
+// matches reports whether the request matches the expectation
+func (e *Expectation) matches(r *http.Request, body []byte) bool {
+	if r.Method != e.method {
+		return false
+	}
+
+	vars, ok := matchPath(e.path, r.URL.EscapedPath())
+	if !ok {
+		return false
+	}
+	for k, v := range e.vars {
+		if vars[k] != v {
+			return false
+		}
+	}
+
+	q := r.URL.Query()
+	for k, values := range e.query {
+		for _, v := range values {
+			if !contains(q[k], v) {
+				return false
+			}
+		}
+	}
+
+	for k, values := range e.header {
+		for _, v := range values {
+			if !contains(r.Header.Values(k), v) {
+				return false
+			}
+		}
+	}
+
+	return e.body == nil || e.body(body)
+}
+
+// take counts the call if the expectation is not exhausted, returning the response to reply
+func (e *Expectation) take() (Response, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.times >= 0 && e.calls >= e.times {
+		return Response{}, false
+	}
+	e.calls++
+
+	if len(e.responses) == 0 {
+		return Response{Status: http.StatusOK}, true
+	}
+
+	return e.responses[min(e.calls, len(e.responses))-1], true
+}
+
+// matchPath matches the escaped path with the pattern with :vars like httpclient.Client URL, returning the unescaped
+// vars values. The path is split before unescaping, so that an escaped / stays in its segment
+func matchPath(pattern, escapedPath string) (map[string]string, bool) {
+	ps, segs := strings.Split(strings.Trim(pattern, "/"), "/"), strings.Split(strings.Trim(escapedPath, "/"), "/")
+	if len(ps) != len(segs) {
+		return nil, false
+	}
+
+	vars := map[string]string{}
+	for i, p := range ps {
+		seg, err := url.PathUnescape(segs[i])
+		if err != nil {
+			return nil, false
+		}
+		if strings.HasPrefix(p, ":") && seg != "" {
+			vars[p[1:]] = seg
+			continue
+		}
+		if p != seg {
+			return nil, false
+		}
+	}
+
+	return vars, true
+}
+
+func contains(values []string, v string) bool {
+	for _, got := range values {
+		if got == v {
+			return true
+		}
+	}
+
+	return false
+}
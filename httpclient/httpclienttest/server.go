@@ -0,0 +1,144 @@
+package httpclienttest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/viebiz/lit/httpclient"
+)
+
+// Server is a stub server of the outbound calls, failing the test when a call is not expected or when an
+// expectation is not met at the end of the test
+//
+// Example:
+//
+//	srv := httpclienttest.NewServer(t)
+//	srv.Expect(http.MethodPost, "/orders/:id/items").
+//		WithPathVar("id", "42").
+//		WithJSONBody(`{"sku":"BOLTER-01"}`).
+//		Reply(http.StatusServiceUnavailable, "").
+//		ReplyJSON(http.StatusCreated, Item{SKU: "BOLTER-01"}).
+//		Times(2)
+//
+//	client, err := httpclient.NewUnauthenticated(srv.Config("orders", http.MethodPost, "/orders/:id/items"), pool)
+type Server struct {
+	*httptest.Server
+
+	t testing.TB
+
+	mu           sync.Mutex
+	expectations []*Expectation
+}
+
+// NewServer starts the stub server, which is closed and verified when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{t: t}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(func() {
+		s.Close()
+		s.verify()
+	})
+
+	return s
+}
+
+// Expect adds the expectation of the call with the method and the path, which can contain :vars
+func (s *Server) Expect(method, path string) *Expectation {
+	e := &Expectation{
+		method: method,
+		path:   path,
+		query:  url.Values{},
+		header: http.Header{},
+		vars:   map[string]string{},
+		times:  1,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expectations = append(s.expectations, e)
+	return e
+}
+
+// Config returns the httpclient.Config calling the path of the server
+func (s *Server) Config(serviceName, method, path string) httpclient.Config {
+	return httpclient.Config{
+		ServiceName: serviceName,
+		URL:         s.URL + path,
+		Method:      method,
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("httpclienttest: failed to read the body of %s %s: %v", r.Method, r.URL, err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resp, ok := s.take(r, body)
+	if !ok {
+		s.t.Errorf("httpclienttest: unexpected call %s %s with body %s", r.Method, r.URL, body)
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-r.Context().Done(): // The client gave up
+			return
+		}
+	}
+
+	if resp.CloseConnection {
+		if conn, _, err := http.NewResponseController(w).Hijack(); err == nil {
+			conn.Close()
+			return
+		}
+		s.t.Errorf("httpclienttest: failed to close the connection of %s %s", r.Method, r.URL)
+		return
+	}
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
+
+// take returns the response of the first expectation matching the request and not exhausted yet
+func (s *Server) take(r *http.Request, body []byte) (Response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.expectations {
+		if !e.matches(r, body) {
+			continue
+		}
+		if resp, ok := e.take(); ok {
+			return resp, true
+		}
+	}
+
+	return Response{}, false
+}
+
+func (s *Server) verify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.expectations {
+		if calls := e.Calls(); e.times >= 0 && calls != e.times {
+			s.t.Errorf("httpclienttest: expected %d call(s) of %s, got %d", e.times, e, calls)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package httpclienttest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/viebiz/lit/httpclient"
+)
+
+// fakeT records the failures of the server instead of failing the test
+type fakeT struct {
+	testing.TB
+
+	mu     sync.Mutex
+	errors []string
+}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) failures() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.errors
+}
+
+func TestServer_Expect(t *testing.T) {
+	type order struct {
+		ID     int    `json:"id"`
+		Status string `json:"status"`
+	}
+
+	tcs := map[string]struct {
+		givenOrderID string
+		givenTenant  string
+		givenBody    string
+		expStatus    int
+		expBody      string
+		expFailures  []string
+	}{
+		"matched": {
+			givenOrderID: "42",
+			givenTenant:  "ultramar",
+			givenBody:    `{ "status": "shipped", "notify": true }`,
+			expStatus:    http.StatusOK,
+			expBody:      `{"id":42,"status":"shipped"}`,
+		},
+		"unexpected path var": {
+			givenOrderID: "7",
+			givenTenant:  "ultramar",
+			givenBody:    `{"notify":true,"status":"shipped"}`,
+			expStatus:    http.StatusNotImplemented,
+			expFailures: []string{
+				`httpclienttest: unexpected call PUT /orders/7?expand=items with body {"notify":true,"status":"shipped"}`,
+			},
+		},
+		"unexpected header": {
+			givenOrderID: "42",
+			givenTenant:  "cadia",
+			givenBody:    `{"notify":true,"status":"shipped"}`,
+			expStatus:    http.StatusNotImplemented,
+			expFailures: []string{
+				`httpclienttest: unexpected call PUT /orders/42?expand=items with body {"notify":true,"status":"shipped"}`,
+			},
+		},
+		"unexpected body": {
+			givenOrderID: "42",
+			givenTenant:  "ultramar",
+			givenBody:    `{"status":"cancelled"}`,
+			expStatus:    http.StatusNotImplemented,
+			expFailures: []string{
+				`httpclienttest: unexpected call PUT /orders/42?expand=items with body {"status":"cancelled"}`,
+			},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			ft := &fakeT{TB: t}
+			srv := NewServer(ft)
+			srv.Expect(http.MethodPut, "/orders/:id").
+				WithPathVar("id", "42").
+				WithQuery("expand", "items").
+				WithHeader("X-Tenant", "ultramar").
+				WithJSONBody(`{"status":"shipped","notify":true}`).
+				ReplyJSON(http.StatusOK, order{ID: 42, Status: "shipped"}).
+				AnyTimes()
+
+			c, err := httpclient.NewUnauthenticated(
+				srv.Config("orders", http.MethodPut, "/orders/:id"),
+				httpclient.NewSharedCustomPool(),
+				httpclient.OverrideBaseRequestHeaders(map[string]string{"X-Tenant": tc.givenTenant}),
+			)
+			require.NoError(t, err)
+
+			// When
+			resp, err := c.Send(context.Background(), httpclient.Payload{
+				PathVars:    map[string]string{"id": tc.givenOrderID},
+				QueryParams: url.Values{"expand": {"items"}},
+				Body:        []byte(tc.givenBody),
+			})
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, tc.expFailures, ft.failures())
+			require.Equal(t, tc.expStatus, resp.Status)
+			require.Equal(t, tc.expBody, string(resp.Body))
+		})
+	}
+}
+
+func TestServer_Reply(t *testing.T) {
+	tcs := map[string]struct {
+		givenReplies []Response
+		givenTimes   int
+		expStatus    int
+		expErr       error
+	}{
+		"retried on status code & connection failure": {
+			givenReplies: []Response{
+				{Status: http.StatusServiceUnavailable},
+				{CloseConnection: true},
+				{Status: http.StatusOK, Body: []byte("ok")},
+			},
+			givenTimes: 3,
+			expStatus:  http.StatusOK,
+		},
+		"last reply repeated": {
+			givenReplies: []Response{
+				{Status: http.StatusBadGateway},
+			},
+			givenTimes: 3,
+			expStatus:  http.StatusBadGateway,
+		},
+		"error - delay exceeds the timeout": {
+			givenReplies: []Response{
+				{Status: http.StatusOK, Delay: time.Second},
+			},
+			givenTimes: 1,
+			expErr:     httpclient.ErrTimeout,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			srv := NewServer(t)
+			e := srv.Expect(http.MethodGet, "/orders").Times(tc.givenTimes)
+			for _, r := range tc.givenReplies {
+				e.ReplyWith(r)
+			}
+
+			c, err := httpclient.NewUnauthenticated(
+				srv.Config("orders", http.MethodGet, "/orders"),
+				httpclient.NewSharedCustomPool(),
+				httpclient.OverrideTimeoutAndRetryOption(0, 100*time.Millisecond, 5*time.Second, false, nil),
+				httpclient.WithRetryPolicy(httpclient.RetryPolicy{MaxRetries: 2, Backoff: httpclient.NewConstantBackoff(0)}),
+			)
+			require.NoError(t, err)
+
+			// When
+			resp, err := c.Send(context.Background(), httpclient.Payload{})
+
+			// Then
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expStatus, resp.Status)
+			require.Equal(t, tc.givenTimes, e.Calls())
+		})
+	}
+}
+
+func TestServer_Verify(t *testing.T) {
+	tcs := map[string]struct {
+		givenCalls  int
+		expFailures []string
+	}{
+		"met": {
+			givenCalls: 2,
+		},
+		"not called enough": {
+			givenCalls: 1,
+			expFailures: []string{
+				"httpclienttest: expected 2 call(s) of GET /orders/:id?expand=items with JSON body {}, got 1",
+			},
+		},
+		"called too many times": {
+			givenCalls: 3,
+			expFailures: []string{
+				"httpclienttest: unexpected call GET /orders/1?expand=items with body {}",
+			},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			ft := &fakeT{TB: t}
+			srv := NewServer(ft)
+			srv.Expect(http.MethodGet, "/orders/:id").
+				WithQuery("expand", "items").
+				WithJSONBody(`{}`).
+				Times(2)
+
+			for range tc.givenCalls {
+				req, err := http.NewRequest(http.MethodGet, srv.URL+"/orders/1?expand=items", strings.NewReader("{}"))
+				require.NoError(t, err)
+				resp, err := http.DefaultClient.Do(req)
+				require.NoError(t, err)
+				resp.Body.Close()
+			}
+
+			// When
+			srv.verify()
+
+			// Then
+			require.Equal(t, tc.expFailures, ft.failures())
+		})
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tcs := map[string]struct {
+		givenPattern string
+		givenPath    string
+		expVars      map[string]string
+		expOK        bool
+	}{
+		"static": {
+			givenPattern: "/orders",
+			givenPath:    "/orders/",
+			expVars:      map[string]string{},
+			expOK:        true,
+		},
+		"vars": {
+			givenPattern: "/customers/:customerID/orders/:id",
+			givenPath:    "/customers/c-1/orders/42",
+			expVars:      map[string]string{"customerID": "c-1", "id": "42"},
+			expOK:        true,
+		},
+		"escaped var": {
+			givenPattern: "/files/:name/versions",
+			givenPath:    "/files/reports%2F2024%20Q1.pdf/versions",
+			expVars:      map[string]string{"name": "reports/2024 Q1.pdf"},
+			expOK:        true,
+		},
+		"invalid escape": {
+			givenPattern: "/files/:name",
+			givenPath:    "/files/%zz",
+		},
+		"different segment": {
+			givenPattern: "/customers/:customerID/orders",
+			givenPath:    "/customers/c-1/invoices",
+		},
+		"empty var": {
+			givenPattern: "/orders/:id/items",
+			givenPath:    "/orders//items",
+		},
+		"different length": {
+			givenPattern: "/orders/:id",
+			givenPath:    "/orders/42/items",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given & When
+			vars, ok := matchPath(tc.givenPattern, tc.givenPath)
+
+			// Then
+			require.Equal(t, tc.expOK, ok)
+			require.Equal(t, tc.expVars, vars)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	acceptJSON = "application/json, " + contentTypeProblemJSON
+)
+
+// JSONPayload is the request payload of DoJSON
+type JSONPayload[T any] struct {
+	// Request body, encoded to JSON unless the method of the client is GET or HEAD
+	Body T
+	// QueryParams contains the request/query parameters
+	QueryParams url.Values
+	// PathVars contains the path variables used to replace the :name placeholders
+	// in Client.URL, e.g. /orders/:id
+	PathVars map[string]string
+	// Header contains custom request headers that will be added to the request
+	// on http call.
+	// The values on this field will override Client.Headers.Values
+	Header map[string]string
+}
+
+// DoJSON sends the JSON encoded body with the client, and decodes the response body into Resp when the status counts
+// as success (2xx unless overridden by WithSuccessStatuses). An empty response body, e.g. 204, gives the zero Resp.
+// The other statuses are returned as *StatusError
+//
+// Example:
+//
+//	user, err := httpclient.DoJSON[CreateUserRequest, User](ctx, client, httpclient.JSONPayload[CreateUserRequest]{
+//		Body: CreateUserRequest{Name: "titus"},
+//	})
+//	var statusErr *httpclient.StatusError
+//	if errors.As(err, &statusErr) && statusErr.Status == http.StatusConflict {
+//		// Handle the conflict
+//	}
+func DoJSON[Req, Resp any](ctx context.Context, c *Client, p JSONPayload[Req]) (Resp, error) {
+	var rs Resp
+
+	payload := Payload{
+		QueryParams: p.QueryParams,
+		PathVars:    p.PathVars,
+		Header:      map[string]string{"Accept": acceptJSON},
+	}
+	for k, v := range p.Header {
+		payload.Header[k] = v
+	}
+	if c.method != http.MethodGet && c.method != http.MethodHead {
+		b, err := json.Marshal(p.Body)
+		if err != nil {
+			return rs, pkgerrors.WithStack(err)
+		}
+		payload.Body = b
+	}
+
+	resp, err := c.Send(ctx, payload)
+	if err != nil {
+		return rs, err
+	}
+
+	if !c.isSuccessStatus(resp.Status) {
+		return rs, newStatusError(resp)
+	}
+
+	if len(resp.Body) == 0 {
+		return rs, nil
+	}
+	if err := json.Unmarshal(resp.Body, &rs); err != nil {
+		return rs, pkgerrors.WithStack(err)
+	}
+
+	return rs, nil
+}
+
+// isSuccessStatus reports whether the status counts as success for DoJSON
+func (c *Client) isSuccessStatus(status int) bool {
+	if len(c.successStatuses) > 0 {
+		return c.successStatuses[status]
+	}
+
+	return status >= http.StatusOK && status < http.StatusMultipleChoices
+}
@@ -0,0 +1,163 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type createUserRequest struct {
+	Name string `json:"name"`
+}
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDoJSON(t *testing.T) {
+	tcs := map[string]struct {
+		givenMethod      string
+		givenOpts        []ClientOption
+		givenStatus      int
+		givenContentType string
+		givenRespBody    string
+		expReqBody       string
+		expResp          user
+		expErr           error
+	}{
+		"created": {
+			givenMethod:   http.MethodPost,
+			givenStatus:   http.StatusCreated,
+			givenRespBody: `{"id":1,"name":"titus"}`,
+			expReqBody:    `{"name":"titus"}`,
+			expResp:       user{ID: 1, Name: "titus"},
+		},
+		"no content": {
+			givenMethod: http.MethodPut,
+			givenStatus: http.StatusNoContent,
+			expReqBody:  `{"name":"titus"}`,
+		},
+		"get without body": {
+			givenMethod:   http.MethodGet,
+			givenStatus:   http.StatusOK,
+			givenRespBody: `{"id":1,"name":"titus"}`,
+			expResp:       user{ID: 1, Name: "titus"},
+		},
+		"overridden success statuses": {
+			givenMethod:   http.MethodGet,
+			givenOpts:     []ClientOption{WithSuccessStatuses(http.StatusOK, http.StatusNotFound)},
+			givenStatus:   http.StatusNotFound,
+			givenRespBody: `{}`,
+		},
+		"error - status not in success statuses": {
+			givenMethod:   http.MethodGet,
+			givenOpts:     []ClientOption{WithSuccessStatuses(http.StatusOK)},
+			givenStatus:   http.StatusAccepted,
+			givenRespBody: `{}`,
+			expErr: &StatusError{
+				Status: http.StatusAccepted,
+				Body:   []byte(`{}`),
+			},
+		},
+		"error - problem json": {
+			givenMethod:      http.MethodPost,
+			givenStatus:      http.StatusConflict,
+			givenContentType: "application/problem+json; charset=utf-8",
+			givenRespBody:    `{"type":"https://example.com/probs/duplicated","title":"User exists","status":409,"detail":"titus is taken","chapter":"Ultramarines"}`,
+			expReqBody:       `{"name":"titus"}`,
+			expErr: &StatusError{
+				Status: http.StatusConflict,
+				Body:   []byte(`{"type":"https://example.com/probs/duplicated","title":"User exists","status":409,"detail":"titus is taken","chapter":"Ultramarines"}`),
+				Problem: &Problem{
+					Type:       "https://example.com/probs/duplicated",
+					Title:      "User exists",
+					Status:     http.StatusConflict,
+					Detail:     "titus is taken",
+					Extensions: map[string]any{"chapter": "Ultramarines"},
+				},
+			},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, tc.expReqBody, string(b))
+				require.Equal(t, "application/json, application/problem+json", r.Header.Get("Accept"))
+
+				if tc.givenContentType != "" {
+					w.Header().Set("Content-Type", tc.givenContentType)
+				}
+				w.WriteHeader(tc.givenStatus)
+				w.Write([]byte(tc.givenRespBody))
+			}))
+			defer srv.Close()
+
+			c, err := NewUnauthenticated(Config{URL: srv.URL, Method: tc.givenMethod, ServiceName: "svc"}, NewSharedCustomPool(), tc.givenOpts...)
+			require.NoError(t, err)
+
+			// When
+			rs, err := DoJSON[createUserRequest, user](context.Background(), c, JSONPayload[createUserRequest]{
+				Body: createUserRequest{Name: "titus"},
+			})
+
+			// Then
+			if tc.expErr != nil {
+				var statusErr *StatusError
+				require.True(t, errors.As(err, &statusErr))
+				statusErr.Header = nil // Set by the server
+				require.Equal(t, tc.expErr, statusErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expResp, rs)
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tcs := map[string]struct {
+		given  *StatusError
+		expMsg string
+	}{
+		"plain": {
+			given:  &StatusError{Status: http.StatusBadGateway, Body: []byte(`{"code":"upstream_down"}`)},
+			expMsg: "unexpected status 502",
+		},
+		"problem": {
+			given:  &StatusError{Status: http.StatusForbidden, Problem: &Problem{Title: "Forbidden", Detail: "not a space marine"}},
+			expMsg: "unexpected status 403: Forbidden: not a space marine",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given & When & Then
+			require.EqualError(t, tc.given, tc.expMsg)
+		})
+	}
+}
+
+func TestStatusError_DecodeBody(t *testing.T) {
+	// Given
+	err := &StatusError{Status: http.StatusBadRequest, Body: []byte(`{"code":"invalid_name"}`)}
+
+	// When
+	var body struct {
+		Code string `json:"code"`
+	}
+	decodeErr := err.DecodeBody(&body)
+
+	// Then
+	require.NoError(t, decodeErr)
+	require.Equal(t, "invalid_name", body.Code)
+}
@@ -0,0 +1,31 @@
+package httpclient
+
+import (
+	"net/http"
+
+	"github.com/viebiz/lit/httpsig"
+)
+
+// WithMessageSignature method signs the requests with the HTTP Message Signature (RFC 9421) signer. Each attempt is
+// signed again so that the retries have a fresh created parameter and nonce
+//
+// The streamed request bodies (i.e. Payload.BodyReader) are not read for the Content-Digest, which must then be set
+// in Payload.Header if covered by the signature
+func WithMessageSignature(signer httpsig.Signer) ClientOption {
+	return func(c *Client) {
+		c.signer = &signer
+	}
+}
+
+// sign signs the request if the client has a signer
+func (c *Client) sign(r *http.Request, p Payload) error {
+	if c.signer == nil {
+		return nil
+	}
+
+	if p.BodyReader != nil && c.signer.CoversContentDigest() && r.Header.Get(httpsig.HeaderContentDigest) == "" {
+		return ErrStreamedBodyNotSignable
+	}
+
+	return c.signer.Sign(r, p.Body)
+}
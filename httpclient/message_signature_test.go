@@ -0,0 +1,114 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/viebiz/lit/httpsig"
+	"github.com/viebiz/lit/jwt"
+)
+
+func TestWithMessageSignature(t *testing.T) {
+	secret := jwt.HMACPrivateKey("orders-secret")
+
+	tcs := map[string]struct {
+		givenPayload   func() Payload
+		givenFailFirst bool
+		expAttempts    int
+		expErr         error
+	}{
+		"signed body": {
+			givenPayload: func() Payload {
+				return Payload{Body: []byte(`{"id":1}`)}
+			},
+			expAttempts: 1,
+		},
+		"signed again on retry": {
+			givenPayload: func() Payload {
+				return Payload{Body: []byte(`{"id":1}`), Header: map[string]string{"Idempotency-Key": "order-1"}}
+			},
+			givenFailFirst: true,
+			expAttempts:    2,
+		},
+		"streamed body with content digest": {
+			givenPayload: func() Payload {
+				return Payload{
+					BodyReader: strings.NewReader(`{"id":1}`),
+					Header:     map[string]string{httpsig.HeaderContentDigest: httpsig.ContentDigest([]byte(`{"id":1}`))},
+				}
+			},
+			expAttempts: 1,
+		},
+		"error - streamed body without content digest": {
+			givenPayload: func() Payload {
+				return Payload{BodyReader: strings.NewReader(`{"id":1}`)}
+			},
+			expErr: ErrStreamedBodyNotSignable,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			verifier, err := httpsig.NewVerifier(httpsig.VerifierConfig{
+				Resolver: func(ctx context.Context, keyID string) (httpsig.VerificationKey, error) {
+					return httpsig.VerificationKey{Algorithm: httpsig.AlgorithmHMACSHA256, Key: secret}, nil
+				},
+			})
+			require.NoError(t, err)
+
+			var mu sync.Mutex
+			var inputs []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				_, err = verifier.Verify(r, body)
+				require.NoError(t, err)
+
+				inputs = append(inputs, r.Header.Get(httpsig.HeaderSignatureInput))
+				if tc.givenFailFirst && len(inputs) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			signer, err := httpsig.NewSigner(httpsig.SignerConfig{KeyID: "orders", Key: secret, Algorithm: httpsig.AlgorithmHMACSHA256})
+			require.NoError(t, err)
+			c, err := NewUnauthenticated(
+				Config{URL: srv.URL + "/orders", Method: http.MethodPost, ServiceName: "orders"},
+				NewSharedCustomPool(),
+				WithMessageSignature(signer),
+				WithRetryPolicy(RetryPolicy{MaxRetries: 1, Backoff: NewConstantBackoff(0)}),
+			)
+			require.NoError(t, err)
+
+			// When
+			resp, err := c.Send(context.Background(), tc.givenPayload())
+
+			// Then
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				require.Empty(t, inputs)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.Status)
+			require.Len(t, inputs, tc.expAttempts)
+			if tc.expAttempts > 1 {
+				require.NotEqual(t, inputs[0], inputs[1]) // Fresh nonce
+			}
+		})
+	}
+}
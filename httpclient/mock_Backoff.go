@@ -0,0 +1,83 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package httpclient
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockBackoff is an autogenerated mock type for the Backoff type
+type MockBackoff struct {
+	mock.Mock
+}
+
+type MockBackoff_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockBackoff) EXPECT() *MockBackoff_Expecter {
+	return &MockBackoff_Expecter{mock: &_m.Mock}
+}
+
+// Next provides a mock function with given fields: retry, prev
+func (_m *MockBackoff) Next(retry int, prev time.Duration) time.Duration {
+	ret := _m.Called(retry, prev)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Next")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func(int, time.Duration) time.Duration); ok {
+		r0 = rf(retry, prev)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// MockBackoff_Next_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Next'
+type MockBackoff_Next_Call struct {
+	*mock.Call
+}
+
+// Next is a helper method to define mock.On call
+//   - retry int
+//   - prev time.Duration
+func (_e *MockBackoff_Expecter) Next(retry interface{}, prev interface{}) *MockBackoff_Next_Call {
+	return &MockBackoff_Next_Call{Call: _e.mock.On("Next", retry, prev)}
+}
+
+func (_c *MockBackoff_Next_Call) Run(run func(retry int, prev time.Duration)) *MockBackoff_Next_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockBackoff_Next_Call) Return(_a0 time.Duration) *MockBackoff_Next_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockBackoff_Next_Call) RunAndReturn(run func(int, time.Duration) time.Duration) *MockBackoff_Next_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockBackoff creates a new instance of MockBackoff. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockBackoff(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockBackoff {
+	mock := &MockBackoff{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
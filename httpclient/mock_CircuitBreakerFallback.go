@@ -0,0 +1,94 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package httpclient
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockCircuitBreakerFallback is an autogenerated mock type for the CircuitBreakerFallback type
+type MockCircuitBreakerFallback struct {
+	mock.Mock
+}
+
+type MockCircuitBreakerFallback_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCircuitBreakerFallback) EXPECT() *MockCircuitBreakerFallback_Expecter {
+	return &MockCircuitBreakerFallback_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: ctx, p, err
+func (_m *MockCircuitBreakerFallback) Execute(ctx context.Context, p Payload, err error) (Response, error) {
+	ret := _m.Called(ctx, p, err)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 Response
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, Payload, error) (Response, error)); ok {
+		return rf(ctx, p, err)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, Payload, error) Response); ok {
+		r0 = rf(ctx, p, err)
+	} else {
+		r0 = ret.Get(0).(Response)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, Payload, error) error); ok {
+		r1 = rf(ctx, p, err)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCircuitBreakerFallback_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type MockCircuitBreakerFallback_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - p Payload
+//   - err error
+func (_e *MockCircuitBreakerFallback_Expecter) Execute(ctx interface{}, p interface{}, err interface{}) *MockCircuitBreakerFallback_Execute_Call {
+	return &MockCircuitBreakerFallback_Execute_Call{Call: _e.mock.On("Execute", ctx, p, err)}
+}
+
+func (_c *MockCircuitBreakerFallback_Execute_Call) Run(run func(ctx context.Context, p Payload, err error)) *MockCircuitBreakerFallback_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(Payload), args[2].(error))
+	})
+	return _c
+}
+
+func (_c *MockCircuitBreakerFallback_Execute_Call) Return(_a0 Response, _a1 error) *MockCircuitBreakerFallback_Execute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCircuitBreakerFallback_Execute_Call) RunAndReturn(run func(context.Context, Payload, error) (Response, error)) *MockCircuitBreakerFallback_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCircuitBreakerFallback creates a new instance of MockCircuitBreakerFallback. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCircuitBreakerFallback(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCircuitBreakerFallback {
+	mock := &MockCircuitBreakerFallback{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
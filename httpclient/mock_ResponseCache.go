@@ -0,0 +1,145 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package httpclient
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockResponseCache is an autogenerated mock type for the ResponseCache type
+type MockResponseCache struct {
+	mock.Mock
+}
+
+type MockResponseCache_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockResponseCache) EXPECT() *MockResponseCache_Expecter {
+	return &MockResponseCache_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: ctx, key
+func (_m *MockResponseCache) Get(ctx context.Context, key string) (*CachedResponse, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *CachedResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*CachedResponse, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *CachedResponse); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*CachedResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockResponseCache_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockResponseCache_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockResponseCache_Expecter) Get(ctx interface{}, key interface{}) *MockResponseCache_Get_Call {
+	return &MockResponseCache_Get_Call{Call: _e.mock.On("Get", ctx, key)}
+}
+
+func (_c *MockResponseCache_Get_Call) Run(run func(ctx context.Context, key string)) *MockResponseCache_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockResponseCache_Get_Call) Return(_a0 *CachedResponse, _a1 error) *MockResponseCache_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockResponseCache_Get_Call) RunAndReturn(run func(context.Context, string) (*CachedResponse, error)) *MockResponseCache_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Set provides a mock function with given fields: ctx, key, resp, ttl
+func (_m *MockResponseCache) Set(ctx context.Context, key string, resp CachedResponse, ttl time.Duration) error {
+	ret := _m.Called(ctx, key, resp, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, CachedResponse, time.Duration) error); ok {
+		r0 = rf(ctx, key, resp, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockResponseCache_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
+type MockResponseCache_Set_Call struct {
+	*mock.Call
+}
+
+// Set is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - resp CachedResponse
+//   - ttl time.Duration
+func (_e *MockResponseCache_Expecter) Set(ctx interface{}, key interface{}, resp interface{}, ttl interface{}) *MockResponseCache_Set_Call {
+	return &MockResponseCache_Set_Call{Call: _e.mock.On("Set", ctx, key, resp, ttl)}
+}
+
+func (_c *MockResponseCache_Set_Call) Run(run func(ctx context.Context, key string, resp CachedResponse, ttl time.Duration)) *MockResponseCache_Set_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(CachedResponse), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockResponseCache_Set_Call) Return(_a0 error) *MockResponseCache_Set_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockResponseCache_Set_Call) RunAndReturn(run func(context.Context, string, CachedResponse, time.Duration) error) *MockResponseCache_Set_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockResponseCache creates a new instance of MockResponseCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockResponseCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockResponseCache {
+	mock := &MockResponseCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
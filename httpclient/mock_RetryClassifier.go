@@ -0,0 +1,83 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package httpclient
+
+import (
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRetryClassifier is an autogenerated mock type for the RetryClassifier type
+type MockRetryClassifier struct {
+	mock.Mock
+}
+
+type MockRetryClassifier_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRetryClassifier) EXPECT() *MockRetryClassifier_Expecter {
+	return &MockRetryClassifier_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: resp, err
+func (_m *MockRetryClassifier) Execute(resp *http.Response, err error) RetryDecision {
+	ret := _m.Called(resp, err)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 RetryDecision
+	if rf, ok := ret.Get(0).(func(*http.Response, error) RetryDecision); ok {
+		r0 = rf(resp, err)
+	} else {
+		r0 = ret.Get(0).(RetryDecision)
+	}
+
+	return r0
+}
+
+// MockRetryClassifier_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type MockRetryClassifier_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - resp *http.Response
+//   - err error
+func (_e *MockRetryClassifier_Expecter) Execute(resp interface{}, err interface{}) *MockRetryClassifier_Execute_Call {
+	return &MockRetryClassifier_Execute_Call{Call: _e.mock.On("Execute", resp, err)}
+}
+
+func (_c *MockRetryClassifier_Execute_Call) Run(run func(resp *http.Response, err error)) *MockRetryClassifier_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*http.Response), args[1].(error))
+	})
+	return _c
+}
+
+func (_c *MockRetryClassifier_Execute_Call) Return(_a0 RetryDecision) *MockRetryClassifier_Execute_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRetryClassifier_Execute_Call) RunAndReturn(run func(*http.Response, error) RetryDecision) *MockRetryClassifier_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRetryClassifier creates a new instance of MockRetryClassifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRetryClassifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRetryClassifier {
+	mock := &MockRetryClassifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,190 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package httpclient
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockTokenCache is an autogenerated mock type for the TokenCache type
+type MockTokenCache struct {
+	mock.Mock
+}
+
+type MockTokenCache_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTokenCache) EXPECT() *MockTokenCache_Expecter {
+	return &MockTokenCache_Expecter{mock: &_m.Mock}
+}
+
+// Delete provides a mock function with given fields: ctx, key
+func (_m *MockTokenCache) Delete(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTokenCache_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockTokenCache_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockTokenCache_Expecter) Delete(ctx interface{}, key interface{}) *MockTokenCache_Delete_Call {
+	return &MockTokenCache_Delete_Call{Call: _e.mock.On("Delete", ctx, key)}
+}
+
+func (_c *MockTokenCache_Delete_Call) Run(run func(ctx context.Context, key string)) *MockTokenCache_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockTokenCache_Delete_Call) Return(_a0 error) *MockTokenCache_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTokenCache_Delete_Call) RunAndReturn(run func(context.Context, string) error) *MockTokenCache_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, key
+func (_m *MockTokenCache) Get(ctx context.Context, key string) (string, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTokenCache_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockTokenCache_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockTokenCache_Expecter) Get(ctx interface{}, key interface{}) *MockTokenCache_Get_Call {
+	return &MockTokenCache_Get_Call{Call: _e.mock.On("Get", ctx, key)}
+}
+
+func (_c *MockTokenCache_Get_Call) Run(run func(ctx context.Context, key string)) *MockTokenCache_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockTokenCache_Get_Call) Return(_a0 string, _a1 error) *MockTokenCache_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTokenCache_Get_Call) RunAndReturn(run func(context.Context, string) (string, error)) *MockTokenCache_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Set provides a mock function with given fields: ctx, key, token, ttl
+func (_m *MockTokenCache) Set(ctx context.Context, key string, token string, ttl time.Duration) error {
+	ret := _m.Called(ctx, key, token, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
+		r0 = rf(ctx, key, token, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTokenCache_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
+type MockTokenCache_Set_Call struct {
+	*mock.Call
+}
+
+// Set is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - token string
+//   - ttl time.Duration
+func (_e *MockTokenCache_Expecter) Set(ctx interface{}, key interface{}, token interface{}, ttl interface{}) *MockTokenCache_Set_Call {
+	return &MockTokenCache_Set_Call{Call: _e.mock.On("Set", ctx, key, token, ttl)}
+}
+
+func (_c *MockTokenCache_Set_Call) Run(run func(ctx context.Context, key string, token string, ttl time.Duration)) *MockTokenCache_Set_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockTokenCache_Set_Call) Return(_a0 error) *MockTokenCache_Set_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTokenCache_Set_Call) RunAndReturn(run func(context.Context, string, string, time.Duration) error) *MockTokenCache_Set_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTokenCache creates a new instance of MockTokenCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTokenCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTokenCache {
+	mock := &MockTokenCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,264 @@
+package httpclient
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/viebiz/lit/internal/oauth2"
+	"github.com/viebiz/lit/jwt"
+	"github.com/viebiz/lit/monitoring"
+)
+
+const (
+	headerAuthorization = "Authorization"
+	bearerPrefix        = "Bearer"
+
+	tokenCacheKeyPrefix = "lit:httpclient:oauth2:"
+
+	defaultTokenRefreshBefore = time.Minute
+	defaultAssertionTTL       = 5 * time.Minute
+
+	// Refer https://datatracker.ietf.org/doc/html/rfc7523#section-2.2
+	clientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+// OAuth2ClientCredentialsConfig holds the config of the OAuth2 client credentials grant
+// Refer https://datatracker.ietf.org/doc/html/rfc6749#section-4.4
+type OAuth2ClientCredentialsConfig struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// Audience is sent as audience parameter when it is not empty, as required by some providers e.g. Auth0
+	Audience string
+	// How long before the expiry the token is refreshed, at most half of the token lifetime. The tokens without
+	// expires_in are refreshed as if they lived 5m
+	// Default: 1m
+	RefreshBefore time.Duration
+	// Where the tokens are cached, use NewRedisTokenCache to share them between the instances of the service
+	// Default: NewMemoryTokenCache()
+	Cache TokenCache
+}
+
+// OAuth2PrivateKeyJWTConfig holds the config of the OAuth2 client credentials grant authenticated
+// with a signed client assertion instead of the client secret
+// Refer https://datatracker.ietf.org/doc/html/rfc7523#section-2.2
+type OAuth2PrivateKeyJWTConfig struct {
+	// ClientSecret is not used
+	OAuth2ClientCredentialsConfig
+	// Key signs the client assertion, its public key has to be registered to the authorization server
+	Key crypto.Signer
+	// SigningMethod of the client assertion, e.g. jwt.NewRS256()
+	SigningMethod jwt.SigningMethod
+	// KeyID is sent as kid header of the client assertion when it is not empty
+	KeyID string
+	// Lifetime of the client assertion
+	// Default: 5m
+	AssertionTTL time.Duration
+}
+
+// clientAssertionClaims are the claims of the client assertion
+type clientAssertionClaims struct {
+	jwt.RegisteredClaims
+}
+
+// tokenSource fetches the access token from the token endpoint and caches it until shortly before it expires
+type tokenSource struct {
+	cfg      OAuth2ClientCredentialsConfig
+	cacheKey string
+	client   *http.Client
+	// authenticate sets the client authentication to the token request form or request
+	authenticate func(form url.Values, r *http.Request) error
+
+	// mu ensures a single token request is in flight, the others wait for its token
+	mu sync.Mutex
+}
+
+func newTokenSource(cfg OAuth2ClientCredentialsConfig, client *http.Client) (*tokenSource, error) {
+	if strings.TrimSpace(cfg.TokenURL) == "" {
+		return nil, pkgerrors.WithStack(ErrMissingTokenURL)
+	}
+	if strings.TrimSpace(cfg.ClientID) == "" {
+		return nil, pkgerrors.WithStack(ErrMissingClientID)
+	}
+	if cfg.RefreshBefore <= 0 {
+		cfg.RefreshBefore = defaultTokenRefreshBefore
+	}
+	if cfg.Cache == nil {
+		cfg.Cache = NewMemoryTokenCache()
+	}
+
+	h := sha256.Sum256([]byte(strings.Join([]string{cfg.TokenURL, cfg.ClientID, strings.Join(cfg.Scopes, " "), cfg.Audience}, "\n")))
+
+	return &tokenSource{
+		cfg:          cfg,
+		cacheKey:     tokenCacheKeyPrefix + hex.EncodeToString(h[:]),
+		client:       client,
+		authenticate: oauth2.BasicAuth(cfg.ClientID, cfg.ClientSecret),
+	}, nil
+}
+
+func newPrivateKeyJWTTokenSource(cfg OAuth2PrivateKeyJWTConfig, client *http.Client) (*tokenSource, error) {
+	if cfg.Key == nil || cfg.SigningMethod == nil {
+		return nil, pkgerrors.WithStack(ErrMissingSigningKey)
+	}
+	if cfg.AssertionTTL <= 0 {
+		cfg.AssertionTTL = defaultAssertionTTL
+	}
+
+	s, err := newTokenSource(cfg.OAuth2ClientCredentialsConfig, client)
+	if err != nil {
+		return nil, err
+	}
+
+	s.authenticate = func(form url.Values, _ *http.Request) error {
+		now := time.Now()
+		iat, exp := now.Unix(), now.Add(cfg.AssertionTTL).Unix()
+
+		tk := jwt.NewToken(cfg.SigningMethod, clientAssertionClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    cfg.ClientID,
+				Subject:   cfg.ClientID,
+				Audience:  jwt.ClaimStrings{cfg.TokenURL},
+				IssuedAt:  &iat,
+				ExpiresAt: &exp,
+				JTI:       uuid.NewString(), // Single use, refer https://datatracker.ietf.org/doc/html/rfc7523#section-3
+			},
+		})
+		if cfg.KeyID != "" {
+			tk.Header["kid"] = cfg.KeyID
+		}
+
+		assertion, err := tk.SignedString(cfg.Key)
+		if err != nil {
+			return pkgerrors.WithStack(err)
+		}
+
+		form.Set("client_id", cfg.ClientID)
+		form.Set("client_assertion_type", clientAssertionTypeJWTBearer)
+		form.Set("client_assertion", assertion)
+		return nil
+	}
+
+	return s, nil
+}
+
+// token returns the cached token, or requests a new one when it is missing or about to expire
+func (s *tokenSource) token(ctx context.Context) (string, error) {
+	if tk := s.cachedToken(ctx); tk != "" {
+		return tk, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The token may have been fetched while waiting for the lock
+	if tk := s.cachedToken(ctx); tk != "" {
+		return tk, nil
+	}
+
+	tk, expiresIn, err := s.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.cfg.Cache.Set(ctx, s.cacheKey, tk, oauth2.ReuseDuration(expiresIn, s.cfg.RefreshBefore)); err != nil {
+		monitoring.FromContext(ctx).Warnf("[ext_http_req] failed to cache access token: (%+v)", err)
+	}
+
+	return tk, nil
+}
+
+// cachedToken returns the cached token, a cache failure is logged and treated as a miss so that a fresh token
+// is requested instead of failing the call
+func (s *tokenSource) cachedToken(ctx context.Context) string {
+	tk, err := s.cfg.Cache.Get(ctx, s.cacheKey)
+	if err != nil {
+		monitoring.FromContext(ctx).Warnf("[ext_http_req] failed to get cached access token: (%+v)", err)
+		return ""
+	}
+
+	return tk
+}
+
+// invalidate removes the token rejected by the service from the cache, unless it was already replaced
+func (s *tokenSource) invalidate(ctx context.Context, rejected string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cachedToken(ctx) != rejected {
+		return nil
+	}
+
+	return s.cfg.Cache.Delete(ctx, s.cacheKey)
+}
+
+func (s *tokenSource) requestToken(ctx context.Context) (string, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeoutPerTry)
+	defer cancel()
+
+	return oauth2.RequestToken(ctx, s.client, oauth2.TokenRequest{
+		TokenURL:     s.cfg.TokenURL,
+		Scopes:       s.cfg.Scopes,
+		Audience:     s.cfg.Audience,
+		Authenticate: s.authenticate,
+	})
+}
+
+// executeWithToken executes the call with the access token, and retries once with a new token when the service
+// rejects it, e.g. revoked before its expiry
+func (c *Client) executeWithToken(ctx context.Context, endpointURL string, p Payload, stream bool) (Response, io.ReadCloser, error) {
+	tk, err := c.tokenSource.token(ctx)
+	if err != nil {
+		return Response{}, nil, err
+	}
+
+	resp, body, err := c.execute(ctx, endpointURL, withBearerToken(p, tk), stream)
+	if err != nil || resp.Status != http.StatusUnauthorized {
+		return resp, body, err
+	}
+	if p.BodyReader != nil && p.Rewind == nil {
+		return resp, body, nil // The body cannot be sent again
+	}
+	if body != nil {
+		body.Close()
+	}
+
+	monitoring.FromContext(ctx).Warnf("[ext_http_req] access token rejected, retrying with a new token")
+	if err := c.tokenSource.invalidate(ctx, tk); err != nil {
+		return Response{}, nil, err
+	}
+	if tk, err = c.tokenSource.token(ctx); err != nil {
+		return Response{}, nil, err
+	}
+	if p.BodyReader != nil {
+		if p.BodyReader, err = p.Rewind(); err != nil {
+			return Response{}, nil, pkgerrors.WithStack(err)
+		}
+	}
+
+	return c.execute(ctx, endpointURL, withBearerToken(p, tk), stream)
+}
+
+// withBearerToken returns the payload with the authorization header, the header of the payload is not modified
+func withBearerToken(p Payload, token string) Payload {
+	h := make(map[string]string, len(p.Header)+1)
+	h[headerAuthorization] = bearerPrefix + " " + token
+	for k, v := range p.Header {
+		h[k] = v
+	}
+	p.Header = h
+
+	return p
+}
@@ -0,0 +1,369 @@
+package httpclient
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/viebiz/lit/jwt"
+)
+
+// startTokenServer starts a token endpoint issuing the tokens in order, the last one is repeated
+func startTokenServer(t *testing.T, assertReq func(r *http.Request), tokens ...string) (string, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, "orders:read orders:write", r.PostForm.Get("scope"))
+		assertReq(r)
+
+		i := int(calls.Add(1)) - 1
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":%q,"token_type":"bearer","expires_in":3600}`, tokens[min(i, len(tokens)-1)])
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv.URL, &calls
+}
+
+// startAPIServer starts a service accepting the bearer tokens given
+func startAPIServer(t *testing.T, validTokens ...string) (string, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		for _, tk := range validTokens {
+			if r.Header.Get("Authorization") == "Bearer "+tk {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv.URL, &calls
+}
+
+func TestNewWithOAuth2ClientCredentials(t *testing.T) {
+	assertBasicAuth := func(r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "order-svc", id)
+		require.Equal(t, "s3cr3t", secret)
+	}
+
+	tcs := map[string]struct {
+		givenTokens      []string
+		givenValidTokens []string
+		givenCalls       int
+		expStatus        int
+		expTokenCalls    int32
+		expAPICalls      int32
+	}{
+		"token is cached": {
+			givenTokens:      []string{"tk-1"},
+			givenValidTokens: []string{"tk-1"},
+			givenCalls:       3,
+			expStatus:        http.StatusOK,
+			expTokenCalls:    1,
+			expAPICalls:      3,
+		},
+		"rejected token is refreshed once": {
+			givenTokens:      []string{"tk-revoked", "tk-2"},
+			givenValidTokens: []string{"tk-2"},
+			givenCalls:       2,
+			expStatus:        http.StatusOK,
+			expTokenCalls:    2,
+			expAPICalls:      3,
+		},
+		"unauthorized after refresh": {
+			givenTokens:   []string{"tk-1", "tk-2"},
+			givenCalls:    1,
+			expStatus:     http.StatusUnauthorized,
+			expTokenCalls: 2,
+			expAPICalls:   2,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			tokenURL, tokenCalls := startTokenServer(t, assertBasicAuth, tc.givenTokens...)
+			apiURL, apiCalls := startAPIServer(t, tc.givenValidTokens...)
+
+			c, err := NewWithOAuth2ClientCredentials(
+				Config{URL: apiURL, Method: http.MethodGet, ServiceName: "svc"},
+				NewSharedCustomPool(),
+				OAuth2ClientCredentialsConfig{
+					TokenURL:     tokenURL,
+					ClientID:     "order-svc",
+					ClientSecret: "s3cr3t",
+					Scopes:       []string{"orders:read", "orders:write"},
+				},
+			)
+			require.NoError(t, err)
+
+			for range tc.givenCalls {
+				// When
+				resp, err := c.Send(context.Background(), Payload{})
+
+				// Then
+				require.NoError(t, err)
+				require.Equal(t, tc.expStatus, resp.Status)
+			}
+			require.Equal(t, tc.expTokenCalls, tokenCalls.Load())
+			require.Equal(t, tc.expAPICalls, apiCalls.Load())
+		})
+	}
+}
+
+func TestNewWithOAuth2ClientCredentials_ConcurrentRefresh(t *testing.T) {
+	// Given
+	tokenURL, tokenCalls := startTokenServer(t, func(r *http.Request) {
+		time.Sleep(50 * time.Millisecond) // Keep the request in flight while the others wait
+	}, "tk-1")
+	apiURL, _ := startAPIServer(t, "tk-1")
+
+	c, err := NewWithOAuth2ClientCredentials(
+		Config{URL: apiURL, Method: http.MethodGet, ServiceName: "svc"},
+		NewSharedCustomPool(),
+		OAuth2ClientCredentialsConfig{TokenURL: tokenURL, ClientID: "order-svc", Scopes: []string{"orders:read", "orders:write"}},
+	)
+	require.NoError(t, err)
+
+	// When
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Send(context.Background(), Payload{})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.Status)
+		}()
+	}
+	wg.Wait()
+
+	// Then
+	require.Equal(t, int32(1), tokenCalls.Load())
+}
+
+func TestNewWithOAuth2ClientCredentials_ShortLivedToken(t *testing.T) {
+	tcs := map[string]struct {
+		givenTokenBody string
+	}{
+		"no expires_in": {
+			givenTokenBody: `{"access_token":"tk-1","token_type":"bearer"}`,
+		},
+		"expires_in shorter than the refresh margin": {
+			givenTokenBody: `{"access_token":"tk-1","token_type":"bearer","expires_in":30}`,
+		},
+		"expires_in equal to the refresh margin": {
+			givenTokenBody: `{"access_token":"tk-1","token_type":"bearer","expires_in":60}`,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			var tokenCalls atomic.Int32
+			tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tokenCalls.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tc.givenTokenBody))
+			}))
+			defer tokenSrv.Close()
+			apiURL, apiCalls := startAPIServer(t, "tk-1")
+
+			c, err := NewWithOAuth2ClientCredentials(
+				Config{URL: apiURL, Method: http.MethodGet, ServiceName: "svc"},
+				NewSharedCustomPool(),
+				OAuth2ClientCredentialsConfig{TokenURL: tokenSrv.URL, ClientID: "order-svc"},
+			)
+			require.NoError(t, err)
+
+			for range 3 {
+				// When
+				resp, err := c.Send(context.Background(), Payload{})
+
+				// Then
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, resp.Status)
+			}
+			require.Equal(t, int32(1), tokenCalls.Load())
+			require.Equal(t, int32(3), apiCalls.Load())
+		})
+	}
+}
+
+func TestNewWithOAuth2ClientCredentials_CacheFailure(t *testing.T) {
+	// Given
+	tokenURL, tokenCalls := startTokenServer(t, func(r *http.Request) {}, "tk-1")
+	apiURL, apiCalls := startAPIServer(t, "tk-1")
+
+	cache := NewMockTokenCache(t)
+	cache.EXPECT().Get(mock.Anything, mock.Anything).Return("", errors.New("simulated error"))
+	cache.EXPECT().Set(mock.Anything, mock.Anything, "tk-1", 59*time.Minute).Return(nil)
+
+	c, err := NewWithOAuth2ClientCredentials(
+		Config{URL: apiURL, Method: http.MethodGet, ServiceName: "svc"},
+		NewSharedCustomPool(),
+		OAuth2ClientCredentialsConfig{
+			TokenURL: tokenURL,
+			ClientID: "order-svc",
+			Scopes:   []string{"orders:read", "orders:write"},
+			Cache:    cache,
+		},
+	)
+	require.NoError(t, err)
+
+	// When
+	resp, err := c.Send(context.Background(), Payload{})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.Status)
+	require.Equal(t, int32(1), tokenCalls.Load())
+	require.Equal(t, int32(1), apiCalls.Load())
+}
+
+func TestNewWithOAuth2ClientCredentials_Error(t *testing.T) {
+	tcs := map[string]struct {
+		givenCfg OAuth2ClientCredentialsConfig
+		expErr   error
+	}{
+		"missing token url": {
+			givenCfg: OAuth2ClientCredentialsConfig{ClientID: "order-svc"},
+			expErr:   ErrMissingTokenURL,
+		},
+		"missing client id": {
+			givenCfg: OAuth2ClientCredentialsConfig{TokenURL: "https://auth.example.com/oauth/token"},
+			expErr:   ErrMissingClientID,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given & When
+			_, err := NewWithOAuth2ClientCredentials(
+				Config{URL: "https://localhost:1604/api/v1/orders", Method: http.MethodGet, ServiceName: "svc"},
+				NewSharedCustomPool(),
+				tc.givenCfg,
+			)
+
+			// Then
+			require.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestClient_Send_InvalidTokenResponse(t *testing.T) {
+	// Given
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_client"}`))
+	}))
+	defer tokenSrv.Close()
+	apiURL, apiCalls := startAPIServer(t)
+
+	c, err := NewWithOAuth2ClientCredentials(
+		Config{URL: apiURL, Method: http.MethodGet, ServiceName: "svc"},
+		NewSharedCustomPool(),
+		OAuth2ClientCredentialsConfig{TokenURL: tokenSrv.URL, ClientID: "order-svc"},
+	)
+	require.NoError(t, err)
+
+	// When
+	_, err = c.Send(context.Background(), Payload{})
+
+	// Then
+	require.ErrorIs(t, err, ErrInvalidTokenResponse)
+	require.EqualError(t, err, `invalid token response: status 400, body {"error":"invalid_client"}`)
+	require.Equal(t, int32(0), apiCalls.Load())
+}
+
+func TestNewWithOAuth2PrivateKeyJWT(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		givenCfg OAuth2PrivateKeyJWTConfig
+		expErr   error
+	}{
+		"success": {
+			givenCfg: OAuth2PrivateKeyJWTConfig{Key: key, SigningMethod: jwt.NewRS256(), KeyID: "key-1"},
+		},
+		"error - missing key": {
+			givenCfg: OAuth2PrivateKeyJWTConfig{SigningMethod: jwt.NewRS256()},
+			expErr:   ErrMissingSigningKey,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			var tokenURL string
+			var assertions []string
+			tokenURL, tokenCalls := startTokenServer(t, func(r *http.Request) {
+				_, _, ok := r.BasicAuth()
+				require.False(t, ok)
+				require.Equal(t, "order-svc", r.PostForm.Get("client_id"))
+				require.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", r.PostForm.Get("client_assertion_type"))
+
+				assertion := r.PostForm.Get("client_assertion")
+				tk, err := jwt.NewDefaultParser[clientAssertionClaims]().Parse(assertion, func(kid string) (crypto.PublicKey, error) {
+					if kid != "key-1" {
+						return nil, errors.New("unknown key")
+					}
+					return &key.PublicKey, nil
+				})
+				require.NoError(t, err)
+				require.Equal(t, "order-svc", tk.Claims.Issuer)
+				require.Equal(t, "order-svc", tk.Claims.Subject)
+				require.Equal(t, jwt.ClaimStrings{tokenURL}, tk.Claims.Audience)
+				require.NotEmpty(t, tk.Claims.JTI)
+				assertions = append(assertions, assertion)
+			}, "tk-1", "tk-2")
+			apiURL, _ := startAPIServer(t, "tk-2")
+
+			tc.givenCfg.OAuth2ClientCredentialsConfig = OAuth2ClientCredentialsConfig{
+				TokenURL: tokenURL,
+				ClientID: "order-svc",
+				Scopes:   []string{"orders:read", "orders:write"},
+			}
+
+			// When
+			c, err := NewWithOAuth2PrivateKeyJWT(
+				Config{URL: apiURL, Method: http.MethodGet, ServiceName: "svc"},
+				NewSharedCustomPool(),
+				tc.givenCfg,
+			)
+
+			// Then
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+
+			resp, err := c.Send(context.Background(), Payload{})
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.Status)
+			require.Equal(t, int32(2), tokenCalls.Load())
+			require.Len(t, assertions, 2)
+			require.NotEqual(t, assertions[0], assertions[1]) // Assertions are single use
+		})
+	}
+}
@@ -23,7 +23,9 @@ func newUnderlyingClient(t *http.Transport, opts ...PoolOption) *http.Client {
 	for _, opt := range opts {
 		opt(c, t)
 	}
-	c.Transport = t
+	if c.Transport == nil { // Otherwise set by an option wrapping the transport
+		c.Transport = t
+	}
 	return c
 }
 
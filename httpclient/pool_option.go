@@ -1,11 +1,20 @@
 package httpclient
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
+
+	"golang.org/x/net/http/httpproxy"
 )
 
-// PoolOption alters behaviour of the http.Client
+// PoolOption alters behaviour of the http.Client. The option can set http.Client.Transport to a http.RoundTripper
+// wrapping the http.Transport, otherwise the http.Transport is used
 type PoolOption func(c *http.Client, t *http.Transport)
 
 // OverridePoolTimeoutDuration overrides the timeout for each try
@@ -35,3 +44,99 @@ func OverridePoolMaxIdleConnsPerHost(n int) PoolOption {
 		t.MaxIdleConnsPerHost = n
 	}
 }
+
+// WithPoolRootCAs sets the root certificate authorities verifying the server certificates, instead of the system ones
+func WithPoolRootCAs(pool *x509.CertPool) PoolOption {
+	return func(_ *http.Client, t *http.Transport) {
+		tlsConfig(t).RootCAs = pool
+	}
+}
+
+// WithPoolClientCertificates sets the client certificates presented to the servers requesting mTLS
+func WithPoolClientCertificates(certs ...tls.Certificate) PoolOption {
+	return func(_ *http.Client, t *http.Transport) {
+		tlsConfig(t).Certificates = certs
+	}
+}
+
+// WithPoolClientCertificateFiles sets the client certificate presented to the servers requesting mTLS, loaded from
+// the PEM encoded files. The files are checked for changes at most once per reloadInterval, so that the rotated
+// certificate is used by the new connections without restart. The calls fail when the certificate cannot be loaded,
+// while a certificate failing to reload is ignored in favor of the previous one
+func WithPoolClientCertificateFiles(certFile, keyFile string, reloadInterval time.Duration) PoolOption {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, interval: reloadInterval, now: time.Now}
+	return func(_ *http.Client, t *http.Transport) {
+		tlsConfig(t).GetClientCertificate = r.getClientCertificate
+	}
+}
+
+// WithPoolMinTLSVersion overrides the minimum TLS version, e.g. tls.VersionTLS13
+func WithPoolMinTLSVersion(version uint16) PoolOption {
+	return func(_ *http.Client, t *http.Transport) {
+		tlsConfig(t).MinVersion = version
+	}
+}
+
+// WithPoolProxy sends the requests through the HTTP(S) proxy, instead of the proxy of the environment variables.
+// The requests to the bypass hosts are sent directly, using the NO_PROXY syntax: host names matching their
+// subdomains (e.g. "bank.example"), domain suffixes (e.g. ".internal"), IP addresses & CIDR ranges, with optional
+// port. The requests to localhost are always sent directly, and so are all the requests when proxyURL is nil
+func WithPoolProxy(proxyURL *url.URL, bypass ...string) PoolOption {
+	if proxyURL == nil {
+		return func(_ *http.Client, t *http.Transport) {
+			t.Proxy = nil
+		}
+	}
+
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    strings.Join(bypass, ","),
+	}).ProxyFunc()
+	return func(_ *http.Client, t *http.Transport) {
+		t.Proxy = func(r *http.Request) (*url.URL, error) {
+			return proxyFunc(r.URL)
+		}
+	}
+}
+
+// WithPoolHTTP2 enables or disables HTTP/2 over TLS, it is enabled by default
+func WithPoolHTTP2(enabled bool) PoolOption {
+	return func(_ *http.Client, t *http.Transport) {
+		t.ForceAttemptHTTP2 = enabled
+		if enabled {
+			return
+		}
+
+		// A non-nil empty map disables HTTP/2, the negotiation must not offer it either
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		tlsConfig(t).NextProtos = []string{"http/1.1"}
+	}
+}
+
+// WithPoolDialContext overrides the dialer of the connections, e.g. to resolve the host names with a service
+// discovery instead of DNS
+func WithPoolDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) PoolOption {
+	return func(_ *http.Client, t *http.Transport) {
+		t.DialContext = dial
+	}
+}
+
+// WithPoolResolver overrides the DNS resolver of the connections, e.g. to query a private DNS server
+func WithPoolResolver(resolver *net.Resolver) PoolOption {
+	return func(_ *http.Client, t *http.Transport) {
+		t.DialContext = (&net.Dialer{
+			Timeout:   defaultDialTimeout,
+			KeepAlive: defaultDialKeepAlive,
+			Resolver:  resolver,
+		}).DialContext
+	}
+}
+
+// tlsConfig returns the TLS config of the transport, creating it if missing
+func tlsConfig(t *http.Transport) *tls.Config {
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	return t.TLSClientConfig
+}
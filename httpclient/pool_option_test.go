@@ -0,0 +1,303 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPoolClientCertificates(t *testing.T) {
+	certPEM, keyPEM := newTestCertificate(t, "orders")
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		givenOpts func(t *testing.T) []PoolOption
+		expCN     string
+		expErr    bool
+	}{
+		"client certificate": {
+			givenOpts: func(t *testing.T) []PoolOption {
+				return []PoolOption{WithPoolClientCertificates(cert)}
+			},
+			expCN: "orders",
+		},
+		"client certificate files": {
+			givenOpts: func(t *testing.T) []PoolOption {
+				dir := t.TempDir()
+				certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+				writeTestCertificate(t, certFile, keyFile, "orders-file", time.Now())
+				return []PoolOption{WithPoolClientCertificateFiles(certFile, keyFile, time.Minute)}
+			},
+			expCN: "orders-file",
+		},
+		"error - missing client certificate": {
+			givenOpts: func(t *testing.T) []PoolOption { return nil },
+			expErr:    true,
+		},
+		"error - missing client certificate files": {
+			givenOpts: func(t *testing.T) []PoolOption {
+				return []PoolOption{WithPoolClientCertificateFiles("client.crt", "client.key", time.Minute)}
+			},
+			expErr: true,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+			}))
+			srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+			srv.StartTLS()
+			defer srv.Close()
+
+			roots := x509.NewCertPool()
+			roots.AddCert(srv.Certificate())
+			c, err := NewUnauthenticated(
+				Config{URL: srv.URL, Method: http.MethodGet, ServiceName: "bank"},
+				NewSharedCustomPool(append(tc.givenOpts(t), WithPoolRootCAs(roots))...),
+			)
+			require.NoError(t, err)
+
+			// When
+			resp, err := c.Send(context.Background(), Payload{})
+
+			// Then
+			if tc.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expCN, string(resp.Body))
+		})
+	}
+}
+
+func TestWithPoolRootCAs(t *testing.T) {
+	tcs := map[string]struct {
+		givenTrusted bool
+		expErr       bool
+	}{
+		"trusted server": {
+			givenTrusted: true,
+		},
+		"error - untrusted server": {
+			expErr: true,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			defer srv.Close()
+
+			roots := x509.NewCertPool()
+			if tc.givenTrusted {
+				roots.AddCert(srv.Certificate())
+			}
+			pool := NewSharedPool(WithPoolRootCAs(roots))
+
+			// When
+			resp, err := pool.Get(srv.URL)
+
+			// Then
+			if tc.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestWithPoolMinTLSVersion(t *testing.T) {
+	tcs := map[string]struct {
+		givenServerMaxVersion uint16
+		expErr                bool
+	}{
+		"supported version": {
+			givenServerMaxVersion: tls.VersionTLS13,
+		},
+		"error - version too old": {
+			givenServerMaxVersion: tls.VersionTLS12,
+			expErr:                true,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			srv.TLS = &tls.Config{MaxVersion: tc.givenServerMaxVersion}
+			srv.StartTLS()
+			defer srv.Close()
+
+			roots := x509.NewCertPool()
+			roots.AddCert(srv.Certificate())
+			pool := NewSharedPool(WithPoolRootCAs(roots), WithPoolMinTLSVersion(tls.VersionTLS13))
+
+			// When
+			resp, err := pool.Get(srv.URL)
+
+			// Then
+			if tc.expErr {
+				require.ErrorContains(t, err, "protocol version")
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, tls.VersionTLS13, int(resp.TLS.Version))
+		})
+	}
+}
+
+func TestWithPoolProxy(t *testing.T) {
+	proxyURL, err := url.Parse("http://proxy.corp:3128")
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		givenURL string
+		expProxy *url.URL
+	}{
+		"http": {
+			givenURL: "http://api.bank.example/payments",
+			expProxy: proxyURL,
+		},
+		"https": {
+			givenURL: "https://api.bank.example/payments",
+			expProxy: proxyURL,
+		},
+		"bypass host": {
+			givenURL: "https://orders.svc/orders",
+		},
+		"bypass subdomain": {
+			givenURL: "https://api.internal/orders",
+		},
+		"bypass cidr": {
+			givenURL: "http://10.1.2.3:8080/orders",
+		},
+		"localhost": {
+			givenURL: "http://localhost:8080/orders",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			pool := NewSharedPool(WithPoolProxy(proxyURL, "orders.svc", ".internal", "10.0.0.0/8"))
+			req, err := http.NewRequest(http.MethodGet, tc.givenURL, nil)
+			require.NoError(t, err)
+
+			// When
+			proxy, err := pool.Transport.(*http.Transport).Proxy(req)
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, tc.expProxy, proxy)
+		})
+	}
+}
+
+func TestWithPoolProxy_Nil(t *testing.T) {
+	// Given & When
+	pool := NewSharedPool(WithPoolProxy(nil))
+
+	// Then
+	require.Nil(t, pool.Transport.(*http.Transport).Proxy)
+}
+
+func TestWithPoolHTTP2(t *testing.T) {
+	tcs := map[string]struct {
+		givenEnabled bool
+		expProto     int
+	}{
+		"enabled": {
+			givenEnabled: true,
+			expProto:     2,
+		},
+		"disabled": {
+			expProto: 1,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			srv.EnableHTTP2 = true
+			srv.StartTLS()
+			defer srv.Close()
+
+			roots := x509.NewCertPool()
+			roots.AddCert(srv.Certificate())
+			pool := NewSharedPool(WithPoolRootCAs(roots), WithPoolHTTP2(tc.givenEnabled))
+
+			// When
+			resp, err := pool.Get(srv.URL)
+
+			// Then
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, tc.expProto, resp.ProtoMajor)
+		})
+	}
+}
+
+func TestWithPoolDialContext(t *testing.T) {
+	// Given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host))
+	}))
+	defer srv.Close()
+
+	var dialed string
+	pool := NewSharedPool(WithPoolDialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = addr
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}))
+
+	// When
+	resp, err := pool.Get("http://orders.svc:8080/orders")
+
+	// Then
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, "orders.svc:8080", dialed)
+}
+
+func TestWithPoolResolver(t *testing.T) {
+	// Given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var queried bool
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			queried = true
+			return nil, &net.DNSError{Err: "no such host", IsNotFound: true}
+		},
+	}
+	pool := NewSharedPool(WithPoolResolver(resolver))
+
+	// When
+	_, err := pool.Get("http://orders.svc.invalid/orders")
+
+	// Then
+	require.Error(t, err)
+	require.True(t, queried)
+}
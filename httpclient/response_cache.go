@@ -0,0 +1,327 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/viebiz/lit/caching/redis"
+	"github.com/viebiz/lit/monitoring"
+)
+
+const (
+	// Stale responses with a validator are kept for this long so that they can be revalidated
+	staleRetention = 24 * time.Hour
+
+	defaultMemoryResponseCacheSize = 1000
+
+	cacheStatusHit         = "hit"
+	cacheStatusMiss        = "miss"
+	cacheStatusRevalidated = "revalidated"
+	cacheStatusBypass      = "bypass"
+)
+
+// heuristicallyCacheableStatuses are the statuses cacheable by default, as of RFC 9110 section 15.1
+var heuristicallyCacheableStatuses = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusPermanentRedirect:    true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+}
+
+// CachedResponse is the response stored in the ResponseCache
+type CachedResponse struct {
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	FreshUntil time.Time   `json:"fresh_until"`
+	// Vary holds the values of the request headers listed in the Vary header of the response
+	Vary map[string]string `json:"vary,omitempty"`
+}
+
+// ResponseCache stores the responses of the GET requests
+type ResponseCache interface {
+	// Get returns the response of the key, or nil when it is missing
+	Get(ctx context.Context, key string) (*CachedResponse, error)
+
+	// Set stores the response of the key for the ttl
+	Set(ctx context.Context, key string, resp CachedResponse, ttl time.Duration) error
+}
+
+// WithResponseCache method caches the responses of the GET requests per RFC 9111 (i.e. Cache-Control, Expires, Age
+// and Vary), the stale responses are revalidated with If-None-Match or If-Modified-Since. The request Cache-Control
+// no-store bypasses the cache and no-cache revalidates the cached response
+//
+// The cache is private to the service: only the responses with explicit freshness or a validator are stored, as if
+// the request headers (including the API key) and the OAuth2 client were part of the URL, so that a ResponseCache can
+// be shared by the clients with different credentials
+func WithResponseCache(cache ResponseCache) ClientOption {
+	return func(c *Client) {
+		c.responseCache = &httpCache{cache: cache, now: time.Now}
+	}
+}
+
+// NewMemoryResponseCache returns a ResponseCache storing the responses in the memory of the process
+func NewMemoryResponseCache() ResponseCache {
+	return &memoryResponseCache{
+		entries: map[string]memoryResponse{},
+		maxSize: defaultMemoryResponseCacheSize,
+		now:     time.Now,
+	}
+}
+
+// NewRedisResponseCache returns a ResponseCache storing the responses in redis, so that they are shared by all the
+// instances of the service
+func NewRedisResponseCache(client redis.Client) ResponseCache {
+	return redisResponseCache{client: client}
+}
+
+type memoryResponse struct {
+	resp      CachedResponse
+	expiresAt time.Time
+}
+
+type memoryResponseCache struct {
+	maxSize int
+	now     func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]memoryResponse
+}
+
+func (c *memoryResponseCache) Get(_ context.Context, key string) (*CachedResponse, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expiresAt) {
+		return nil, nil
+	}
+
+	return &e.resp, nil
+}
+
+func (c *memoryResponseCache) Set(_ context.Context, key string, resp CachedResponse, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxSize {
+		for k, e := range c.entries { // Evict the expired entries, or any entry if none is expired
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < c.maxSize {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = memoryResponse{resp: resp, expiresAt: now.Add(ttl)}
+
+	return nil
+}
+
+type redisResponseCache struct {
+	client redis.Client
+}
+
+func (c redisResponseCache) Get(ctx context.Context, key string) (*CachedResponse, error) {
+	v, err := c.client.GetString(ctx, key)
+	if err != nil || v == "" {
+		return nil, err
+	}
+
+	var resp CachedResponse
+	if err := json.Unmarshal([]byte(v), &resp); err != nil {
+		return nil, pkgerrors.WithStack(err)
+	}
+
+	return &resp, nil
+}
+
+func (c redisResponseCache) Set(ctx context.Context, key string, resp CachedResponse, ttl time.Duration) error {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return pkgerrors.WithStack(err)
+	}
+
+	return c.client.SetString(ctx, key, string(b), ttl)
+}
+
+// httpCache applies the caching rules of RFC 9111 on top of the ResponseCache
+type httpCache struct {
+	cache ResponseCache
+	now   func() time.Time
+}
+
+// lookup returns the cached response of the request, or nil if missing or varying
+func (hc *httpCache) lookup(ctx context.Context, key string, reqHeader http.Header) *CachedResponse {
+	cached, err := hc.cache.Get(ctx, key)
+	if err != nil {
+		monitoring.FromContext(ctx).Warnf("[ext_http_req] failed to get cached response: (%+v)", err)
+		return nil
+	}
+	if cached == nil {
+		return nil
+	}
+
+	for name, v := range cached.Vary {
+		if reqHeader.Get(name) != v {
+			return nil
+		}
+	}
+
+	return cached
+}
+
+// store stores the response if cacheable, returning whether it is stored
+func (hc *httpCache) store(ctx context.Context, key string, reqHeader http.Header, resp Response) bool {
+	if !heuristicallyCacheableStatuses[resp.Status] {
+		return false
+	}
+
+	// Copied, as the caller owns the header & body of the response
+	cached := CachedResponse{Status: resp.Status, Header: resp.Header.Clone(), Body: bytes.Clone(resp.Body)}
+	return hc.save(ctx, key, reqHeader, cached)
+}
+
+// revalidate refreshes the cached response with the headers of the 304 response, returning the refreshed response
+func (hc *httpCache) revalidate(ctx context.Context, key string, reqHeader http.Header, cached CachedResponse, notModified Response) CachedResponse {
+	header := cached.Header.Clone()
+	for k, v := range notModified.Header {
+		if k == "Content-Length" {
+			continue
+		}
+		header[k] = v
+	}
+	cached.Header = header
+
+	hc.save(ctx, key, reqHeader, cached)
+
+	return cached
+}
+
+func (hc *httpCache) save(ctx context.Context, key string, reqHeader http.Header, cached CachedResponse) bool {
+	now := hc.now()
+	lifetime, ok := freshnessLifetime(cached.Header, now)
+	if !ok {
+		return false
+	}
+
+	hasValidator := cached.Header.Get("ETag") != "" || cached.Header.Get("Last-Modified") != ""
+	ttl := lifetime
+	if hasValidator {
+		ttl = max(lifetime, 0) + staleRetention
+	}
+	if ttl <= 0 {
+		return false
+	}
+
+	cached.FreshUntil = now.Add(lifetime)
+	cached.Vary = nil
+	for _, name := range headerTokens(cached.Header.Values("Vary")) {
+		if cached.Vary == nil {
+			cached.Vary = map[string]string{}
+		}
+		cached.Vary[http.CanonicalHeaderKey(name)] = reqHeader.Get(name)
+	}
+
+	if err := hc.cache.Set(ctx, key, cached, ttl); err != nil {
+		monitoring.FromContext(ctx).Warnf("[ext_http_req] failed to cache response: (%+v)", err)
+		return false
+	}
+
+	return true
+}
+
+// isFresh reports whether the cached response can be served without revalidation for the request
+func (hc *httpCache) isFresh(cached *CachedResponse, reqHeader http.Header) bool {
+	cc := parseCacheControl(reqHeader.Values("Cache-Control"))
+	if _, ok := cc["no-cache"]; ok {
+		return false
+	}
+	if v, ok := cc["max-age"]; ok && v == "0" {
+		return false
+	}
+
+	return hc.now().Before(cached.FreshUntil)
+}
+
+// freshnessLifetime returns the freshness lifetime of the response, or false if it must not be stored
+func freshnessLifetime(h http.Header, now time.Time) (time.Duration, bool) {
+	cc := parseCacheControl(h.Values("Cache-Control"))
+	if _, ok := cc["no-store"]; ok {
+		return 0, false
+	}
+	for _, name := range headerTokens(h.Values("Vary")) {
+		if name == "*" {
+			return 0, false
+		}
+	}
+
+	var lifetime time.Duration
+	if v, ok := cc["max-age"]; ok {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs > 0 {
+			lifetime = time.Duration(secs) * time.Second
+		}
+	} else if v := h.Get("Expires"); v != "" {
+		if expires, err := http.ParseTime(v); err == nil {
+			date, err := http.ParseTime(h.Get("Date"))
+			if err != nil {
+				date = now
+			}
+			lifetime = expires.Sub(date)
+		}
+	}
+
+	if _, ok := cc["no-cache"]; ok {
+		lifetime = 0
+	}
+	if age, err := strconv.ParseInt(h.Get("Age"), 10, 64); err == nil && age > 0 {
+		lifetime -= time.Duration(age) * time.Second
+	}
+
+	return lifetime, true
+}
+
+// parseCacheControl parses the Cache-Control directives, the directive names are lowercased
+func parseCacheControl(values []string) map[string]string {
+	cc := map[string]string{}
+	for _, d := range headerTokens(values) {
+		name, value, _ := strings.Cut(d, "=")
+		cc[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+
+	return cc
+}
+
+// headerTokens splits the comma separated values of the header
+func headerTokens(values []string) []string {
+	var tokens []string
+	for _, v := range values {
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				tokens = append(tokens, t)
+			}
+		}
+	}
+
+	return tokens
+}
@@ -0,0 +1,424 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/viebiz/lit/caching/redis"
+	"github.com/viebiz/lit/monitoring/tracing/mocktracer"
+)
+
+func TestFreshnessLifetime(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tcs := map[string]struct {
+		givenHeader http.Header
+		expLifetime time.Duration
+		expOK       bool
+	}{
+		"max-age": {
+			givenHeader: http.Header{"Cache-Control": {"public, max-age=60"}},
+			expLifetime: time.Minute,
+			expOK:       true,
+		},
+		"max-age minus age": {
+			givenHeader: http.Header{"Cache-Control": {"max-age=60"}, "Age": {"15"}},
+			expLifetime: 45 * time.Second,
+			expOK:       true,
+		},
+		"max-age overrides expires": {
+			givenHeader: http.Header{"Cache-Control": {"max-age=10"}, "Expires": {"Wed, 01 May 2024 11:00:00 GMT"}},
+			expLifetime: 10 * time.Second,
+			expOK:       true,
+		},
+		"expires minus date": {
+			givenHeader: http.Header{"Date": {"Wed, 01 May 2024 09:59:00 GMT"}, "Expires": {"Wed, 01 May 2024 10:01:00 GMT"}},
+			expLifetime: 2 * time.Minute,
+			expOK:       true,
+		},
+		"expires without date": {
+			givenHeader: http.Header{"Expires": {"Wed, 01 May 2024 10:00:30 GMT"}},
+			expLifetime: 30 * time.Second,
+			expOK:       true,
+		},
+		"invalid expires": {
+			givenHeader: http.Header{"Expires": {"0"}},
+			expOK:       true,
+		},
+		"no-cache": {
+			givenHeader: http.Header{"Cache-Control": {"max-age=60, no-cache"}, "Etag": {`"v1"`}},
+			expOK:       true,
+		},
+		"no-store": {
+			givenHeader: http.Header{"Cache-Control": {"max-age=60", "no-store"}},
+		},
+		"vary all": {
+			givenHeader: http.Header{"Cache-Control": {"max-age=60"}, "Vary": {"*"}},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given & When
+			lifetime, ok := freshnessLifetime(tc.givenHeader, now)
+
+			// Then
+			require.Equal(t, tc.expOK, ok)
+			require.Equal(t, tc.expLifetime, lifetime)
+		})
+	}
+}
+
+func TestHTTPCache_lookup(t *testing.T) {
+	tcs := map[string]struct {
+		givenVary   map[string]string
+		givenHeader http.Header
+		expFound    bool
+	}{
+		"no vary": {
+			givenHeader: http.Header{"Accept-Language": {"vi"}},
+			expFound:    true,
+		},
+		"vary matched": {
+			givenVary:   map[string]string{"Accept-Language": "vi"},
+			givenHeader: http.Header{"Accept-Language": {"vi"}},
+			expFound:    true,
+		},
+		"vary mismatched": {
+			givenVary:   map[string]string{"Accept-Language": "vi"},
+			givenHeader: http.Header{"Accept-Language": {"en"}},
+		},
+		"vary missing": {
+			givenVary:   map[string]string{"Accept-Language": "vi"},
+			givenHeader: http.Header{},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			hc := &httpCache{cache: NewMemoryResponseCache(), now: time.Now}
+			require.NoError(t, hc.cache.Set(ctx, "key", CachedResponse{Status: http.StatusOK, Vary: tc.givenVary}, time.Minute))
+
+			// When
+			cached := hc.lookup(ctx, "key", tc.givenHeader)
+
+			// Then
+			require.Equal(t, tc.expFound, cached != nil)
+		})
+	}
+}
+
+func TestMemoryResponseCache(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	cache := NewMemoryResponseCache().(*memoryResponseCache)
+	cache.maxSize = 2
+	cache.now = func() time.Time { return now }
+
+	// When
+	require.NoError(t, cache.Set(ctx, "a", CachedResponse{Status: http.StatusOK}, time.Second))
+	require.NoError(t, cache.Set(ctx, "b", CachedResponse{Status: http.StatusOK}, time.Minute))
+	now = now.Add(2 * time.Second)
+	expired, err := cache.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, cache.Set(ctx, "c", CachedResponse{Status: http.StatusNotFound}, time.Minute))
+
+	// Then
+	require.Nil(t, expired)
+	require.Len(t, cache.entries, 2)
+	rs, err := cache.Get(ctx, "c")
+	require.NoError(t, err)
+	require.Equal(t, &CachedResponse{Status: http.StatusNotFound}, rs)
+	rs, err = cache.Get(ctx, "b")
+	require.NoError(t, err)
+	require.Equal(t, &CachedResponse{Status: http.StatusOK}, rs)
+}
+
+func TestRedisResponseCache(t *testing.T) {
+	given := CachedResponse{
+		Status:     http.StatusOK,
+		Header:     http.Header{"Etag": {`"v1"`}},
+		Body:       []byte(`{"id":1}`),
+		FreshUntil: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	givenJSON := `{"status":200,"header":{"Etag":["\"v1\""]},"body":"eyJpZCI6MX0=","fresh_until":"2024-05-01T10:00:00Z"}`
+
+	tcs := map[string]struct {
+		mockValue string
+		mockErr   error
+		exp       *CachedResponse
+		expErr    error
+	}{
+		"found": {
+			mockValue: givenJSON,
+			exp:       &given,
+		},
+		"missing": {},
+		"error": {
+			mockErr: errors.New("connection refused"),
+			expErr:  errors.New("connection refused"),
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			client := redis.NewMockClient(t)
+			client.EXPECT().SetString(ctx, "key", givenJSON, time.Minute).Return(nil)
+			client.EXPECT().GetString(ctx, "key").Return(tc.mockValue, tc.mockErr)
+			cache := NewRedisResponseCache(client)
+
+			// When
+			require.NoError(t, cache.Set(ctx, "key", given, time.Minute))
+			rs, err := cache.Get(ctx, "key")
+
+			// Then
+			if tc.expErr != nil {
+				require.EqualError(t, err, tc.expErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, rs)
+		})
+	}
+}
+
+func TestWithResponseCache(t *testing.T) {
+	tp := mocktracer.Start()
+	defer tp.Stop()
+
+	tcs := map[string]struct {
+		givenReqHeader  map[string]string
+		givenRespHeader http.Header
+		givenStatus     int
+		expCalls        int32
+		expStatus       int
+		expCacheStatus  string
+		expCacheHit     bool
+	}{
+		"fresh response is served from cache": {
+			givenRespHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			givenStatus:     http.StatusOK,
+			expCalls:        1,
+			expStatus:       http.StatusOK,
+			expCacheStatus:  "hit",
+			expCacheHit:     true,
+		},
+		"stale response is revalidated": {
+			givenRespHeader: http.Header{"Cache-Control": {"no-cache"}, "Etag": {`"v1"`}},
+			givenStatus:     http.StatusOK,
+			expCalls:        2,
+			expStatus:       http.StatusOK,
+			expCacheStatus:  "revalidated",
+		},
+		"stale response is revalidated with last modified": {
+			givenRespHeader: http.Header{"Cache-Control": {"max-age=0"}, "Last-Modified": {"Wed, 01 May 2024 10:00:00 GMT"}},
+			givenStatus:     http.StatusOK,
+			expCalls:        2,
+			expStatus:       http.StatusOK,
+			expCacheStatus:  "revalidated",
+		},
+		"request no-cache revalidates": {
+			givenReqHeader:  map[string]string{"Cache-Control": "no-cache"},
+			givenRespHeader: http.Header{"Cache-Control": {"max-age=60"}, "Etag": {`"v1"`}},
+			givenStatus:     http.StatusOK,
+			expCalls:        2,
+			expStatus:       http.StatusOK,
+			expCacheStatus:  "revalidated",
+		},
+		"request no-store bypasses cache": {
+			givenReqHeader:  map[string]string{"Cache-Control": "no-store"},
+			givenRespHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			givenStatus:     http.StatusOK,
+			expCalls:        2,
+			expStatus:       http.StatusOK,
+			expCacheStatus:  "bypass",
+		},
+		"no-store response is not cached": {
+			givenRespHeader: http.Header{"Cache-Control": {"no-store"}},
+			givenStatus:     http.StatusOK,
+			expCalls:        2,
+			expStatus:       http.StatusOK,
+			expCacheStatus:  "miss",
+		},
+		"response without freshness is not cached": {
+			givenStatus:    http.StatusOK,
+			expCalls:       2,
+			expStatus:      http.StatusOK,
+			expCacheStatus: "miss",
+		},
+		"uncacheable status is not cached": {
+			givenRespHeader: http.Header{"Cache-Control": {"max-age=60"}},
+			givenStatus:     http.StatusInternalServerError,
+			expCalls:        2,
+			expStatus:       http.StatusInternalServerError,
+			expCacheStatus:  "miss",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			tp.Reset()
+
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				for k, v := range tc.givenRespHeader {
+					w.Header()[k] = v
+				}
+				if etag := r.Header.Get("If-None-Match"); etag != "" && etag == tc.givenRespHeader.Get("Etag") ||
+					r.Header.Get("If-Modified-Since") != "" {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.WriteHeader(tc.givenStatus)
+				_, _ = w.Write([]byte(`{"id":1}`))
+			}))
+			defer srv.Close()
+
+			c, err := NewUnauthenticated(
+				Config{URL: srv.URL + "/orders/:id", Method: http.MethodGet, ServiceName: "orders"},
+				NewSharedCustomPool(),
+				WithResponseCache(NewMemoryResponseCache()),
+			)
+			require.NoError(t, err)
+			p := Payload{PathVars: map[string]string{"id": "1"}, Header: tc.givenReqHeader}
+			_, err = c.Send(context.Background(), p)
+			require.NoError(t, err)
+
+			// When
+			resp, err := c.Send(context.Background(), p)
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, tc.expStatus, resp.Status)
+			require.Equal(t, `{"id":1}`, string(resp.Body))
+			require.Equal(t, tc.expCalls, calls.Load())
+
+			var groupSpans []mocktracer.SpanStub
+			for _, span := range tp.GetSpans() {
+				if span.Name == "http.outgoing_request" {
+					groupSpans = append(groupSpans, span)
+				}
+			}
+			require.Len(t, groupSpans, 2)
+			attrs := groupSpans[1].Attributes
+			require.Contains(t, attrs, attribute.String("http.client.cache.status", tc.expCacheStatus))
+			if tc.expCacheStatus != "bypass" {
+				require.Contains(t, attrs, attribute.Bool("http.client.cache.hit", tc.expCacheHit))
+			}
+		})
+	}
+}
+
+func TestWithResponseCache_CacheFailure(t *testing.T) {
+	// Given
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cache := NewMockResponseCache(t)
+	cache.EXPECT().Get(mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+	cache.EXPECT().Set(mock.Anything, mock.Anything, mock.Anything, time.Minute).Return(errors.New("connection refused"))
+	c, err := NewUnauthenticated(
+		Config{URL: srv.URL, Method: http.MethodGet, ServiceName: "orders"},
+		NewSharedCustomPool(),
+		WithResponseCache(cache),
+	)
+	require.NoError(t, err)
+
+	// When
+	resp, err := c.Send(context.Background(), Payload{})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.Status)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestWithResponseCache_SharedByClients(t *testing.T) {
+	// Given
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(r.Header.Get("X-Api-Key")))
+	}))
+	defer srv.Close()
+
+	cache := NewMemoryResponseCache()
+	newClient := func(apiKey string) *Client {
+		c, err := NewWithAPIKey(
+			Config{URL: srv.URL + "/orders", Method: http.MethodGet, ServiceName: "orders"},
+			NewSharedCustomPool(),
+			APIKeyConfig{Key: "X-Api-Key", Value: apiKey},
+			WithResponseCache(cache),
+		)
+		require.NoError(t, err)
+		return c
+	}
+	tenantA, tenantB := newClient("key-a"), newClient("key-b")
+
+	// When
+	respA, err := tenantA.Send(context.Background(), Payload{})
+	require.NoError(t, err)
+	respB, err := tenantB.Send(context.Background(), Payload{})
+	require.NoError(t, err)
+	cachedA, err := tenantA.Send(context.Background(), Payload{})
+	require.NoError(t, err)
+
+	// Then
+	require.Equal(t, "key-a", string(respA.Body))
+	require.Equal(t, "key-b", string(respB.Body))
+	require.Equal(t, "key-a", string(cachedA.Body))
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestWithResponseCache_ResponseNotShared(t *testing.T) {
+	// Given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewUnauthenticated(
+		Config{URL: srv.URL, Method: http.MethodGet, ServiceName: "orders"},
+		NewSharedCustomPool(),
+		WithResponseCache(NewMemoryResponseCache()),
+	)
+	require.NoError(t, err)
+
+	resp, err := c.Send(context.Background(), Payload{})
+	require.NoError(t, err)
+	resp.Body[0] = '['
+	resp.Header.Set("Cache-Control", "no-store")
+
+	// When
+	cached, err := c.Send(context.Background(), Payload{})
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, `{"id":1}`, string(cached.Body))
+	require.Equal(t, "max-age=60", cached.Header.Get("Cache-Control"))
+}
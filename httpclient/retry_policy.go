@@ -0,0 +1,230 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	defaultRetryBaseBackoff     = 100 * time.Millisecond
+	defaultRetryMaxBackoff      = 10 * time.Second
+	defaultRetryMaxRetryAfter   = time.Minute
+	defaultIdempotencyKeyHeader = "Idempotency-Key"
+
+	headerRetryAfter     = "Retry-After"
+	headerRateLimitReset = "RateLimit-Reset"
+)
+
+// defaultRetryStatusCodes are the statuses retried when RetryPolicy.RetryOnStatusCodes is empty
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryDecision is the decision of a RetryClassifier
+type RetryDecision int
+
+const (
+	// RetryDefault leaves the decision to the next classifier, or to the RetryPolicy
+	RetryDefault RetryDecision = iota
+	// RetryAllow retries the attempt
+	RetryAllow
+	// RetryDeny stops retrying, the response or the error of the attempt is returned
+	RetryDeny
+)
+
+// RetryClassifier decides whether the attempt is retried, resp is nil when the request failed with err
+type RetryClassifier func(resp *http.Response, err error) RetryDecision
+
+// RetryPolicy holds the retry config of the client. It replaces the retries of OverrideTimeoutAndRetryOption,
+// whose timeouts still apply
+type RetryPolicy struct {
+	// Max num of retries. Setting to 0 means no retry
+	MaxRetries uint64
+	// Wait before each retry, the Retry-After or RateLimit-Reset header of the response is used instead if any
+	// Default: NewExponentialJitterBackoff(100ms, 10s)
+	Backoff Backoff
+	// Max wait asked by the service with the Retry-After or RateLimit-Reset header, the response is returned
+	// without retrying when the service asks to wait longer
+	// Default: 1m
+	MaxRetryAfter time.Duration
+	// Retry on these status codes
+	// Default: 429, 502, 503, 504
+	RetryOnStatusCodes []int
+	// Retry on timeout errors
+	// Default: false
+	RetryOnTimeout bool
+	// Non-idempotent requests (i.e. POST & PATCH) are only retried when they have this header
+	// Default: Idempotency-Key
+	IdempotencyKeyHeader string
+	// Classifiers are run in order on each failed attempt, the first decision other than RetryDefault wins
+	// over RetryOnStatusCodes and RetryOnTimeout
+	Classifiers []RetryClassifier
+}
+
+// WithRetryPolicy method sets the retry policy of the client
+//
+// Example:
+//
+//	httpclient.WithRetryPolicy(httpclient.RetryPolicy{
+//		MaxRetries: 3,
+//		Backoff:    httpclient.NewDecorrelatedJitterBackoff(200*time.Millisecond, 5*time.Second),
+//		Classifiers: []httpclient.RetryClassifier{
+//			func(resp *http.Response, err error) httpclient.RetryDecision {
+//				if resp != nil && resp.Header.Get("X-Error-Code") == "insufficient_funds" {
+//					return httpclient.RetryDeny
+//				}
+//				return httpclient.RetryDefault
+//			},
+//		},
+//	})
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		if policy.Backoff == nil {
+			policy.Backoff = NewExponentialJitterBackoff(defaultRetryBaseBackoff, defaultRetryMaxBackoff)
+		}
+		if policy.MaxRetryAfter <= 0 {
+			policy.MaxRetryAfter = defaultRetryMaxRetryAfter
+		}
+		if len(policy.RetryOnStatusCodes) == 0 {
+			policy.RetryOnStatusCodes = defaultRetryStatusCodes
+		}
+		if policy.IdempotencyKeyHeader == "" {
+			policy.IdempotencyKeyHeader = defaultIdempotencyKeyHeader
+		}
+		c.retryPolicy = &policy
+	}
+}
+
+// canRetry reports whether the request with the headers can be sent more than once
+func (rp *RetryPolicy) canRetry(method string, headers ...map[string]string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	key := http.CanonicalHeaderKey(rp.IdempotencyKeyHeader)
+	for _, h := range headers {
+		for k, v := range h {
+			if http.CanonicalHeaderKey(k) == key && v != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (rp *RetryPolicy) classify(resp *http.Response, err error) RetryDecision {
+	for _, classify := range rp.Classifiers {
+		if d := classify(resp, err); d != RetryDefault {
+			return d
+		}
+	}
+
+	return RetryDefault
+}
+
+// retryError returns the error of the failed request, as backoff.Permanent if it should not be retried
+func (rp *RetryPolicy) retryError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return backoff.Permanent(ErrOperationContextCanceled)
+	}
+
+	mapped := pkgerrors.WithStack(err)
+	if uerr, ok := err.(*url.Error); ok && uerr.Timeout() {
+		mapped = ErrTimeout
+	}
+
+	switch rp.classify(nil, err) {
+	case RetryAllow:
+		return mapped
+	case RetryDeny:
+		return backoff.Permanent(mapped)
+	}
+
+	if mapped == ErrTimeout && !rp.RetryOnTimeout {
+		return backoff.Permanent(mapped)
+	}
+
+	return mapped
+}
+
+// retryResponse reports whether the response should be retried
+func (rp *RetryPolicy) retryResponse(resp *http.Response) bool {
+	switch rp.classify(resp, nil) {
+	case RetryAllow:
+		return true
+	case RetryDeny:
+		return false
+	}
+
+	for _, sc := range rp.RetryOnStatusCodes {
+		if sc == resp.StatusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// retryAfter returns the wait asked by the service with the Retry-After or RateLimit-Reset header, 0 if none
+// Refer https://datatracker.ietf.org/doc/html/rfc9110#section-10.2.3
+// Refer https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	if v := h.Get(headerRetryAfter); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil && t.After(now) {
+			return t.Sub(now)
+		}
+	}
+
+	if v := h.Get(headerRateLimitReset); v != "" {
+		if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+
+	return 0
+}
+
+// policyBackOff is the backoff.BackOff of the RetryPolicy, the wait asked by the service overrides the next one
+type policyBackOff struct {
+	policy *RetryPolicy
+
+	retry      int
+	prev       time.Duration
+	retryAfter time.Duration
+}
+
+func (b *policyBackOff) NextBackOff() time.Duration {
+	b.retry++
+
+	d := b.retryAfter
+	b.retryAfter = 0
+	if d <= 0 {
+		d = b.policy.Backoff.Next(b.retry, b.prev)
+	}
+	b.prev = d
+
+	return d
+}
+
+func (b *policyBackOff) Reset() {
+	b.retry, b.prev, b.retryAfter = 0, 0, 0
+}
+
+func execWithPolicy(ctx context.Context, maxRetries uint64, b *policyBackOff, f func() error) error {
+	return backoff.Retry(f, backoff.WithContext(backoff.WithMaxRetries(b, maxRetries), ctx))
+}
@@ -0,0 +1,314 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/viebiz/lit/monitoring/tracing/mocktracer"
+)
+
+func TestBackoff(t *testing.T) {
+	tcs := map[string]struct {
+		given     Backoff
+		givenPrev time.Duration
+		givenTry  int
+		expMin    time.Duration
+		expMax    time.Duration
+	}{
+		"exponential jitter": {
+			given:    NewExponentialJitterBackoff(100*time.Millisecond, time.Second),
+			givenTry: 3,
+			expMax:   400 * time.Millisecond,
+		},
+		"exponential jitter capped": {
+			given:    NewExponentialJitterBackoff(100*time.Millisecond, time.Second),
+			givenTry: 100,
+			expMax:   time.Second,
+		},
+		"constant": {
+			given:    NewConstantBackoff(time.Second),
+			givenTry: 5,
+			expMin:   time.Second,
+			expMax:   time.Second,
+		},
+		"decorrelated jitter": {
+			given:     NewDecorrelatedJitterBackoff(100*time.Millisecond, 5*time.Second),
+			givenPrev: time.Second,
+			givenTry:  2,
+			expMin:    100 * time.Millisecond,
+			expMax:    3 * time.Second,
+		},
+		"decorrelated jitter capped": {
+			given:     NewDecorrelatedJitterBackoff(100*time.Millisecond, 2*time.Second),
+			givenPrev: time.Second,
+			givenTry:  2,
+			expMin:    100 * time.Millisecond,
+			expMax:    2 * time.Second,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			for range 100 {
+				// Given & When
+				d := tc.given.Next(tc.givenTry, tc.givenPrev)
+
+				// Then
+				require.GreaterOrEqual(t, d, tc.expMin)
+				require.LessOrEqual(t, d, tc.expMax)
+			}
+		})
+	}
+}
+
+func Test_retryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tcs := map[string]struct {
+		given http.Header
+		exp   time.Duration
+	}{
+		"seconds": {
+			given: http.Header{"Retry-After": {"120"}},
+			exp:   2 * time.Minute,
+		},
+		"http date": {
+			given: http.Header{"Retry-After": {"Sat, 01 Mar 2025 10:00:30 GMT"}},
+			exp:   30 * time.Second,
+		},
+		"http date in the past": {
+			given: http.Header{"Retry-After": {"Sat, 01 Mar 2025 09:00:00 GMT"}},
+		},
+		"rate limit reset": {
+			given: http.Header{"Ratelimit-Reset": {"5"}},
+			exp:   5 * time.Second,
+		},
+		"invalid": {
+			given: http.Header{"Retry-After": {"soon"}},
+		},
+		"none": {
+			given: http.Header{},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given & When & Then
+			require.Equal(t, tc.exp, retryAfter(tc.given, now))
+		})
+	}
+}
+
+func TestRetryPolicy_retryError(t *testing.T) {
+	timeoutErr := &url.Error{Op: "Get", URL: "http://localhost", Err: &net.DNSError{IsTimeout: true}}
+	connErr := &url.Error{Op: "Get", URL: "http://localhost", Err: errors.New("connection refused")}
+
+	tcs := map[string]struct {
+		givenPolicy  RetryPolicy
+		givenErr     error
+		expErr       error
+		expPermanent bool
+	}{
+		"connection error is retried": {
+			givenErr: connErr,
+			expErr:   connErr,
+		},
+		"timeout is not retried": {
+			givenErr:     timeoutErr,
+			expErr:       ErrTimeout,
+			expPermanent: true,
+		},
+		"timeout is retried": {
+			givenPolicy: RetryPolicy{RetryOnTimeout: true},
+			givenErr:    timeoutErr,
+			expErr:      ErrTimeout,
+		},
+		"canceled": {
+			givenErr:     context.Canceled,
+			expErr:       ErrOperationContextCanceled,
+			expPermanent: true,
+		},
+		"classifier denies": {
+			givenPolicy: RetryPolicy{Classifiers: []RetryClassifier{
+				func(resp *http.Response, err error) RetryDecision { return RetryDefault },
+				func(resp *http.Response, err error) RetryDecision { return RetryDeny },
+			}},
+			givenErr:     connErr,
+			expErr:       connErr,
+			expPermanent: true,
+		},
+		"classifier allows timeout": {
+			givenPolicy: RetryPolicy{Classifiers: []RetryClassifier{
+				func(resp *http.Response, err error) RetryDecision { return RetryAllow },
+			}},
+			givenErr: timeoutErr,
+			expErr:   ErrTimeout,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given & When
+			err := tc.givenPolicy.retryError(tc.givenErr)
+
+			// Then
+			var permanent *backoff.PermanentError
+			require.Equal(t, tc.expPermanent, errors.As(err, &permanent))
+			require.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	tp := mocktracer.Start()
+	defer tp.Stop()
+
+	tcs := map[string]struct {
+		givenMethod      string
+		givenHeader      map[string]string
+		givenPolicy      RetryPolicy
+		givenRespHeader  http.Header
+		givenFirstStatus int
+		expStatus        int
+		expCalls         int32
+		expMinElapsed    time.Duration
+	}{
+		"get is retried": {
+			givenMethod:      http.MethodGet,
+			givenPolicy:      RetryPolicy{MaxRetries: 2},
+			givenFirstStatus: http.StatusServiceUnavailable,
+			expStatus:        http.StatusOK,
+			expCalls:         2,
+		},
+		"retry after is honored": {
+			givenMethod:      http.MethodGet,
+			givenPolicy:      RetryPolicy{MaxRetries: 2},
+			givenRespHeader:  http.Header{"Retry-After": {"1"}},
+			givenFirstStatus: http.StatusTooManyRequests,
+			expStatus:        http.StatusOK,
+			expCalls:         2,
+			expMinElapsed:    time.Second,
+		},
+		"retry after exceeding max wait is not retried": {
+			givenMethod:      http.MethodGet,
+			givenPolicy:      RetryPolicy{MaxRetries: 2},
+			givenRespHeader:  http.Header{"Ratelimit-Reset": {"60"}},
+			givenFirstStatus: http.StatusTooManyRequests,
+			expStatus:        http.StatusTooManyRequests,
+			expCalls:         1,
+		},
+		"retry after exceeding max retry after is not retried": {
+			givenMethod:      http.MethodGet,
+			givenPolicy:      RetryPolicy{MaxRetries: 2, MaxRetryAfter: 500 * time.Millisecond},
+			givenRespHeader:  http.Header{"Retry-After": {"1"}},
+			givenFirstStatus: http.StatusTooManyRequests,
+			expStatus:        http.StatusTooManyRequests,
+			expCalls:         1,
+		},
+		"post is not retried": {
+			givenMethod:      http.MethodPost,
+			givenPolicy:      RetryPolicy{MaxRetries: 2},
+			givenFirstStatus: http.StatusServiceUnavailable,
+			expStatus:        http.StatusServiceUnavailable,
+			expCalls:         1,
+		},
+		"post with idempotency key is retried": {
+			givenMethod:      http.MethodPost,
+			givenHeader:      map[string]string{"idempotency-key": "order-1"},
+			givenPolicy:      RetryPolicy{MaxRetries: 2},
+			givenFirstStatus: http.StatusServiceUnavailable,
+			expStatus:        http.StatusOK,
+			expCalls:         2,
+		},
+		"status not retried": {
+			givenMethod:      http.MethodGet,
+			givenPolicy:      RetryPolicy{MaxRetries: 2},
+			givenFirstStatus: http.StatusInternalServerError,
+			expStatus:        http.StatusInternalServerError,
+			expCalls:         1,
+		},
+		"classifier allows status": {
+			givenMethod: http.MethodGet,
+			givenPolicy: RetryPolicy{MaxRetries: 2, Classifiers: []RetryClassifier{
+				func(resp *http.Response, err error) RetryDecision {
+					if resp != nil && resp.StatusCode == http.StatusInternalServerError {
+						return RetryAllow
+					}
+					return RetryDefault
+				},
+			}},
+			givenFirstStatus: http.StatusInternalServerError,
+			expStatus:        http.StatusOK,
+			expCalls:         2,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			tp.Reset()
+
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) == 1 {
+					for k, v := range tc.givenRespHeader {
+						w.Header()[k] = v
+					}
+					w.WriteHeader(tc.givenFirstStatus)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			tc.givenPolicy.Backoff = NewConstantBackoff(0)
+			c, err := NewUnauthenticated(
+				Config{URL: srv.URL, Method: tc.givenMethod, ServiceName: "svc"},
+				NewSharedCustomPool(),
+				OverrideTimeoutAndRetryOption(0, 5*time.Second, 5*time.Second, false, nil),
+				WithRetryPolicy(tc.givenPolicy),
+			)
+			require.NoError(t, err)
+
+			// When
+			start := time.Now()
+			resp, err := c.Send(context.Background(), Payload{Header: tc.givenHeader})
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, tc.expStatus, resp.Status)
+			require.Equal(t, tc.expCalls, calls.Load())
+			require.GreaterOrEqual(t, time.Since(start), tc.expMinElapsed)
+
+			var attempts []int64
+			for _, span := range tp.GetSpans() {
+				if span.Name != "http.request" {
+					continue
+				}
+				for _, attr := range span.Attributes {
+					if attr.Key == "http.request.attempt" {
+						attempts = append(attempts, attr.Value.AsInt64())
+					}
+				}
+				if len(attempts) > 1 {
+					require.Contains(t, span.Attributes, attribute.Int("http.request.resend_count", len(attempts)-1))
+				}
+			}
+			require.Len(t, attempts, int(tc.expCalls))
+			for i, attempt := range attempts {
+				require.Equal(t, int64(i+1), attempt)
+			}
+		})
+	}
+}
@@ -26,10 +26,15 @@ var (
 type Payload struct {
 	// Request body
 	Body []byte
+	// BodyReader streams the request body instead of Body, e.g. file upload. It is not logged
+	BodyReader io.Reader
+	// Rewind returns the BodyReader again from the start, so that the call can be retried or redirected.
+	// The call is not retried when BodyReader is set without Rewind
+	Rewind func() (io.Reader, error)
 	// QueryParams contains the request/query parameters
 	QueryParams url.Values
-	// PathVars contains the path variables used to replace placeholders
-	// wrapped with {} in Client.URL
+	// PathVars contains the path variables used to replace the :name placeholders
+	// in Client.URL, e.g. /orders/:id
 	PathVars map[string]string
 	// Header contains custom request headers that will be added to the request
 	// on http call.
@@ -44,31 +49,82 @@ type Response struct {
 	Header http.Header
 }
 
+// StreamResponse is the result of the http call with the streamed response body
+type StreamResponse struct {
+	Status int
+	// Body must be closed by the caller, the tracing span and the timeouts of the call last until then
+	Body   io.ReadCloser
+	Header http.Header
+}
+
 // Send executes an HTTP call based on the information and configuration
 // in the Client
 func (c *Client) Send(ctx context.Context, p Payload) (Response, error) {
+	resp, _, err := c.send(ctx, p, false)
+	return resp, err
+}
+
+// SendStream executes an HTTP call like Send, but returns the response body as it is received instead of reading it
+// into memory, e.g. file download or NDJSON stream. The response body is not logged
+func (c *Client) SendStream(ctx context.Context, p Payload) (StreamResponse, error) {
+	resp, body, err := c.send(ctx, p, true)
+	if err != nil {
+		return StreamResponse{}, err
+	}
+	return StreamResponse{Status: resp.Status, Body: body, Header: resp.Header}, nil
+}
+
+// send executes the HTTP call, the response body is returned as io.ReadCloser instead of Response.Body when stream is set
+func (c *Client) send(ctx context.Context, p Payload, stream bool) (Response, io.ReadCloser, error) {
 	// Endpoint URL
 	endpointURL := c.constructURL(p)
 
 	var err error
+	var body io.ReadCloser
 	ctx, segEnd := instrumenthttp.StartOutgoingGroupSegment(ctx, c.extSvcInfo, c.serviceName, c.method, c.url)
-	defer func() { segEnd(err) }()
 	monitor := monitoring.FromContext(ctx)
 
 	if !c.disableReqBodyLogging && (c.method == http.MethodPost || c.method == http.MethodPut || c.method == http.MethodPatch) {
-		v := p.Body
+		if p.BodyReader != nil {
+			monitor.Infof("[ext_http_req] skipping logging streamed req body")
+		} else {
+			v := p.Body
 
-		monitor.Infof("[ext_http_req] request body:(%s)", string(v))
+			monitor.Infof("[ext_http_req] request body:(%s)", string(v))
+		}
 	}
 
 	// Create context with max timeout
 	ctxTimeout, cancel := context.WithTimeout(ctx, c.timeoutAndRetryOption.maxWaitInclRetries)
-	defer cancel()
+	defer func() {
+		if body == nil { // Otherwise ended when the body is closed
+			cancel()
+			segEnd(err)
+		}
+	}()
 
 	// HTTP operation
-	resp, err := c.execute(ctxTimeout, endpointURL, p)
+	var resp Response
+	var respBody io.ReadCloser
+	if !stream && c.method == http.MethodGet && (c.coalescing || c.responseCache != nil) {
+		resp, err = c.sendGET(ctx, ctxTimeout, endpointURL, p)
+	} else {
+		resp, respBody, err = c.call(ctx, ctxTimeout, endpointURL, p, stream)
+	}
 	if err != nil {
-		return Response{}, err
+		return Response{}, nil, err
+	}
+
+	if stream {
+		monitor.Infof("[ext_http_req] skipping logging streamed resp body")
+		if respBody == nil { // Served by the circuit breaker fallback
+			respBody = io.NopCloser(bytes.NewReader(resp.Body))
+		}
+		body = newNotifyCloser(respBody, func(err error) {
+			cancel()
+			segEnd(err)
+		})
+		return resp, body, nil
 	}
 
 	if !c.disableRespBodyLogging {
@@ -79,38 +135,113 @@ func (c *Client) Send(ctx context.Context, p Payload) (Response, error) {
 		monitor.Infof("[ext_http_req] skipping logging resp body")
 	}
 
-	return resp, nil
+	return resp, nil, nil
+}
+
+// call executes the HTTP call through the circuit breaker of the service, ctxTimeout bounds the call including retries
+func (c *Client) call(
+	ctx context.Context,
+	ctxTimeout context.Context,
+	endpointURL string,
+	p Payload,
+	stream bool,
+) (Response, io.ReadCloser, error) {
+	// Fast-fail while the circuit of the service is open
+	var breakerGen uint64
+	if c.circuitBreaker != nil {
+		var allowed bool
+		if breakerGen, allowed = c.circuitBreaker.allow(ctx); !allowed {
+			monitoring.FromContext(ctx).Warnf("[ext_http_req] circuit breaker is open, skipping call")
+			if fb := c.circuitBreakerCfg.Fallback; fb != nil {
+				resp, err := fb(ctx, p, ErrCircuitOpen)
+				return resp, nil, err
+			}
+			return Response{}, nil, ErrCircuitOpen
+		}
+	}
+
+	var resp Response
+	var respBody io.ReadCloser
+	var err error
+	if c.tokenSource != nil {
+		resp, respBody, err = c.executeWithToken(ctxTimeout, endpointURL, p, stream)
+	} else {
+		resp, respBody, err = c.execute(ctxTimeout, endpointURL, p, stream)
+	}
+	if c.circuitBreaker != nil {
+		c.circuitBreaker.record(ctx, breakerGen, callOutcomeOf(resp, err))
+	}
+
+	return resp, respBody, err
 }
 
+// execute executes the HTTP call with retries, the response body of the last attempt is returned as io.ReadCloser
+// instead of Response.Body when stream is set
 func (c *Client) execute(
 	ctx context.Context,
 	endpointURL string,
 	p Payload,
-) (Response, error) {
+	stream bool,
+) (Response, io.ReadCloser, error) {
 	var resultResp Response
+	var resultBody io.ReadCloser
 	var attempts int
 
-	if err := execWithRetryFunc(ctx, c.timeoutAndRetryOption.maxRetries, c.timeoutAndRetryOption.maxWaitInclRetries,
+	maxRetries := c.timeoutAndRetryOption.maxRetries
+	retry := func(f func() error) error {
+		return execWithRetryFunc(ctx, maxRetries, c.timeoutAndRetryOption.maxWaitInclRetries, f)
+	}
+	var policyBO *policyBackOff
+	if c.retryPolicy != nil {
+		maxRetries = c.retryPolicy.MaxRetries
+		if !c.retryPolicy.canRetry(c.method, c.header.values, p.Header) {
+			maxRetries = 0
+		}
+		policyBO = &policyBackOff{policy: c.retryPolicy}
+		retry = func(f func() error) error {
+			return execWithPolicy(ctx, maxRetries, policyBO, f)
+		}
+	}
+
+	// A streamed body can only be sent again if it can be rewound
+	if p.BodyReader != nil && p.Rewind == nil {
+		maxRetries = 0
+	}
+
+	if err := retry(
 		func() error {
 			// start new attempt for the http request
 			attempts++
 
-			req, err := c.createHTTPRequest(endpointURL, p.Body) // create HTTP request
+			req, err := c.createHTTPRequest(endpointURL, p, attempts) // create HTTP request
 			if err != nil {
 				return err
 			}
 
 			// var err error
 			var status int
+			var streamed bool
 			reqCtx, segEnd := instrumenthttp.StartOutgoingSegment(ctx, c.extSvcInfo, c.serviceName, req)
-			defer func() { segEnd(status, err) }()
+			instrumenthttp.RecordOutgoingAttempt(reqCtx, attempts)
+			defer func() {
+				if !streamed { // Otherwise ended when the body is closed
+					segEnd(status, err)
+				}
+			}()
 			monitor := monitoring.FromContext(ctx)
 
 			reqCtx, cancelReqCtx := context.WithTimeout(reqCtx, c.timeoutAndRetryOption.maxWaitPerTry)
-			defer cancelReqCtx()
+			defer func() {
+				if !streamed {
+					cancelReqCtx()
+				}
+			}()
 			req = req.WithContext(reqCtx) // limit each HTTP request timeout option for per try
 
 			c.setHeader(req, p) // set request headers
+			if err = c.sign(req, p); err != nil {
+				return backoff.Permanent(err) // stop retry by returning backoff.Permanent error
+			}
 
 			// start sending request
 			start := time.Now()
@@ -122,12 +253,16 @@ func (c *Client) execute(
 				WithTag("ext_http_resp_duration", fmt.Sprintf("%dms", time.Since(start).Milliseconds()))
 			if err != nil {
 				// handle request error
-				monitor.Infof("[ext_http_req] end with error: (%+v), attempt (%d)", err, attempts) // intentionally not using Errorf for err as this is just info and the req can be retried.
+				monitor.Warnf("[ext_http_req] end with error: (%+v), attempt (%d)", err, attempts) // intentionally not using Errorf for err as this is just a warning and the req can be retried.
 
 				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 					return backoff.Permanent(ErrOverflowMaxWait) // stop retry by returning backoff.Permanent error
 				}
 
+				if c.retryPolicy != nil {
+					return c.retryPolicy.retryError(err)
+				}
+
 				// evaluate if err is caused by connection timeout
 				uerr, ok := err.(*url.Error)
 				if !ok || !uerr.Timeout() {
@@ -146,17 +281,43 @@ func (c *Client) execute(
 			}
 
 			status = resp.StatusCode
-			if _, ok := c.timeoutAndRetryOption.onStatusCodes[resp.StatusCode]; ok {
+			if c.retryPolicy != nil {
+				if uint64(attempts) <= maxRetries && c.retryPolicy.retryResponse(resp) {
+					wait := retryAfter(resp.Header, time.Now())
+					deadline, hasDeadline := ctx.Deadline()
+					if wait <= c.retryPolicy.MaxRetryAfter && (!hasDeadline || time.Until(deadline) > wait) {
+						monitor.Warnf("[ext_http_req] retry on status code: (%d), attempt (%d)", resp.StatusCode, attempts)
+						policyBO.retryAfter = wait
+						resp.Body.Close()
+						return fmt.Errorf("retry on status code %v", resp.StatusCode)
+					}
+					monitor.Warnf("[ext_http_req] not retrying status code: (%d), retry after (%s) exceeds max wait", resp.StatusCode, wait)
+				}
+			} else if _, ok := c.timeoutAndRetryOption.onStatusCodes[resp.StatusCode]; ok {
 				// Only returns error for backoff retry function retries the call
 				// when attempts count haven't reached max retries
-				if uint64(attempts) <= c.timeoutAndRetryOption.maxRetries {
-					monitor.Infof("[ext_http_req] retry on status code: (%d), attempt (%d)", resp.StatusCode, attempts)
+				if uint64(attempts) <= maxRetries {
+					monitor.Warnf("[ext_http_req] retry on status code: (%d), attempt (%d)", resp.StatusCode, attempts)
+					resp.Body.Close()
 					return fmt.Errorf("retry on status code %v", resp.StatusCode)
 				}
 			}
 
 			monitor.Infof("[ext_http_req] end with status code: (%d), attempt (%d)", resp.StatusCode, attempts)
 
+			resultResp.Status = resp.StatusCode
+			resultResp.Header = resp.Header
+
+			if stream {
+				// hand over the end of the attempt to the caller closing the body
+				streamed = true
+				resultBody = newNotifyCloser(resp.Body, func(err error) {
+					cancelReqCtx()
+					segEnd(status, err)
+				})
+				return nil
+			}
+
 			// attempt to read response body
 			// we need to read the response body in the same function where we cancel the retry context
 			// otherwise, for big payload, the context will be cancelled while reading the body
@@ -180,23 +341,21 @@ func (c *Client) execute(
 				return pkgerrors.WithStack(err)
 			}
 
-			resultResp.Status = resp.StatusCode
 			resultResp.Body = respBody
-			resultResp.Header = resp.Header
 
 			return nil
 		}); err != nil {
 		switch err {
 		case context.Canceled:
-			return Response{}, ErrOperationContextCanceled
+			return Response{}, nil, ErrOperationContextCanceled
 		case context.DeadlineExceeded:
-			return Response{}, ErrOverflowMaxWait
+			return Response{}, nil, ErrOverflowMaxWait
 		default:
-			return Response{}, err
+			return Response{}, nil, err
 		}
 	}
 
-	return resultResp, nil
+	return resultResp, resultBody, nil
 }
 
 // constructURL returns the full URL with query params and path variables substitution
@@ -234,14 +393,39 @@ func (c *Client) setHeader(r *http.Request, p Payload) {
 	}
 }
 
-func (c *Client) createHTTPRequest(endpointURL string, body []byte) (*http.Request, error) {
+func (c *Client) createHTTPRequest(endpointURL string, p Payload, attempt int) (*http.Request, error) {
 	var b io.Reader
-	if len(body) > 0 {
-		b = bytes.NewBuffer(body)
+	switch {
+	case p.BodyReader != nil && attempt > 1:
+		var err error
+		if b, err = p.Rewind(); err != nil {
+			return nil, backoff.Permanent(pkgerrors.WithStack(err))
+		}
+	case p.BodyReader != nil:
+		b = p.BodyReader
+	case len(p.Body) > 0:
+		b = bytes.NewBuffer(p.Body)
 	}
 
 	r, err := http.NewRequest(c.method, endpointURL, b)
-	return r, pkgerrors.WithStack(err)
+	if err != nil {
+		return nil, pkgerrors.WithStack(err)
+	}
+
+	if p.Rewind != nil { // Allow the redirects keeping the body, i.e. 307 & 308
+		r.GetBody = func() (io.ReadCloser, error) {
+			rb, err := p.Rewind()
+			if err != nil {
+				return nil, err
+			}
+			if rc, ok := rb.(io.ReadCloser); ok {
+				return rc, nil
+			}
+			return io.NopCloser(rb), nil
+		}
+	}
+
+	return r, nil
 }
 
 func readRespBody(r io.Reader) ([]byte, error) {
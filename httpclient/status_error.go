@@ -0,0 +1,87 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	contentTypeProblemJSON = "application/problem+json"
+)
+
+// StatusError is returned by DoJSON when the service responds with a status not counting as success
+type StatusError struct {
+	Status int
+	Header http.Header
+	// Raw response body, use DecodeBody to decode it into the error type of the service
+	Body []byte
+	// Problem is decoded from the body when the response is application/problem+json
+	Problem *Problem
+}
+
+// Problem is the problem details of the HTTP API error
+// Refer https://datatracker.ietf.org/doc/html/rfc9457
+type Problem struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Extensions holds the other members of the problem details
+	Extensions map[string]any `json:"-"`
+}
+
+// UnmarshalJSON decodes the problem details, keeping the extension members
+func (p *Problem) UnmarshalJSON(b []byte) error {
+	type problem Problem // Avoid recursion
+	if err := json.Unmarshal(b, (*problem)(p)); err != nil {
+		return err
+	}
+
+	var members map[string]any
+	if err := json.Unmarshal(b, &members); err != nil {
+		return err
+	}
+	for _, k := range []string{"type", "title", "status", "detail", "instance"} {
+		delete(members, k)
+	}
+	if len(members) > 0 {
+		p.Extensions = members
+	}
+
+	return nil
+}
+
+func newStatusError(resp Response) *StatusError {
+	err := &StatusError{Status: resp.Status, Header: resp.Header, Body: resp.Body}
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType == contentTypeProblemJSON {
+		var p Problem
+		if json.Unmarshal(resp.Body, &p) == nil {
+			err.Problem = &p
+		}
+	}
+
+	return err
+}
+
+func (e *StatusError) Error() string {
+	if e.Problem != nil {
+		msg := e.Problem.Title
+		if e.Problem.Detail != "" {
+			msg += ": " + e.Problem.Detail
+		}
+		return fmt.Sprintf("unexpected status %d: %s", e.Status, msg)
+	}
+
+	return fmt.Sprintf("unexpected status %d", e.Status)
+}
+
+// DecodeBody decodes the JSON response body into v
+func (e *StatusError) DecodeBody(v any) error {
+	return pkgerrors.WithStack(json.Unmarshal(e.Body, v))
+}
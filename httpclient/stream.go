@@ -0,0 +1,194 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"sync"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// notifyCloser calls onClose once when the body is closed, with the read error if any
+type notifyCloser struct {
+	io.ReadCloser
+
+	onClose func(error)
+	once    sync.Once
+	readErr error
+}
+
+func newNotifyCloser(rc io.ReadCloser, onClose func(error)) *notifyCloser {
+	return &notifyCloser{ReadCloser: rc, onClose: onClose}
+}
+
+func (c *notifyCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) && c.readErr == nil {
+		c.readErr = err
+	}
+
+	return n, err
+}
+
+func (c *notifyCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.once.Do(func() {
+		c.onClose(c.readErr)
+	})
+
+	return err
+}
+
+// multipartPart is a field or a file of the multipart form
+type multipartPart struct {
+	header textproto.MIMEHeader
+	value  string
+	open   func() (io.Reader, error) // Set for the files
+}
+
+// MultipartForm builds the multipart/form-data request body, the files are streamed when the request is sent
+// instead of being read into memory
+//
+// Example:
+//
+//	form := httpclient.NewMultipartForm().
+//		AddField("title", "Codex Astartes").
+//		AddFile("document", "codex.pdf", "application/pdf", func() (io.Reader, error) {
+//			return os.Open("codex.pdf")
+//		})
+//	resp, err := client.Send(ctx, form.Payload())
+type MultipartForm struct {
+	boundary string
+	parts    []multipartPart
+}
+
+// NewMultipartForm returns a new empty MultipartForm
+func NewMultipartForm() *MultipartForm {
+	return &MultipartForm{boundary: multipart.NewWriter(nil).Boundary()}
+}
+
+// AddField adds the form field
+func (f *MultipartForm) AddField(name, value string) *MultipartForm {
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(name)))
+	f.parts = append(f.parts, multipartPart{header: h, value: value})
+
+	return f
+}
+
+// AddFile adds the file, open is called each time the request is sent and the reader is closed after being sent
+// if it is an io.Closer
+func (f *MultipartForm) AddFile(fieldName, fileName, contentType string, open func() (io.Reader, error)) *MultipartForm {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(fieldName), escapeQuotes(fileName)))
+	h.Set("Content-Type", contentType)
+	f.parts = append(f.parts, multipartPart{header: h, open: open})
+
+	return f
+}
+
+// ContentType returns the content type of the form including the boundary
+func (f *MultipartForm) ContentType() string {
+	return "multipart/form-data; boundary=" + f.boundary
+}
+
+// Payload returns the Payload streaming the form, which can be rewound for the retries
+func (f *MultipartForm) Payload() Payload {
+	return Payload{
+		BodyReader: f.reader(),
+		Rewind: func() (io.Reader, error) {
+			return f.reader(), nil
+		},
+		Header: map[string]string{"Content-Type": f.ContentType()},
+	}
+}
+
+// reader returns the reader of the form, writing it on the first read
+func (f *MultipartForm) reader() io.ReadCloser {
+	return &lazyReader{start: func() io.ReadCloser {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(f.write(pw))
+		}()
+
+		return pr
+	}}
+}
+
+func (f *MultipartForm) write(w io.Writer) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(f.boundary); err != nil {
+		return pkgerrors.WithStack(err)
+	}
+
+	for _, part := range f.parts {
+		pw, err := mw.CreatePart(part.header)
+		if err != nil {
+			return pkgerrors.WithStack(err)
+		}
+
+		if part.open == nil {
+			if _, err := io.WriteString(pw, part.value); err != nil {
+				return pkgerrors.WithStack(err)
+			}
+			continue
+		}
+
+		if err := copyFile(pw, part.open); err != nil {
+			return err
+		}
+	}
+
+	return pkgerrors.WithStack(mw.Close())
+}
+
+func copyFile(w io.Writer, open func() (io.Reader, error)) error {
+	r, err := open()
+	if err != nil {
+		return pkgerrors.WithStack(err)
+	}
+	if c, ok := r.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	_, err = io.Copy(w, r)
+	return pkgerrors.WithStack(err)
+}
+
+// lazyReader starts the reader on the first read, so that nothing is left running if it is never read
+type lazyReader struct {
+	start func() io.ReadCloser
+
+	once sync.Once
+	rc   io.ReadCloser
+}
+
+func (r *lazyReader) Read(p []byte) (int, error) {
+	r.once.Do(func() {
+		r.rc = r.start()
+	})
+
+	return r.rc.Read(p)
+}
+
+func (r *lazyReader) Close() error {
+	r.once.Do(func() {
+		r.rc = io.NopCloser(strings.NewReader("")) // Never started
+	})
+
+	return r.rc.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
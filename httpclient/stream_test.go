@@ -0,0 +1,220 @@
+package httpclient
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/viebiz/lit/monitoring/tracing/mocktracer"
+)
+
+// retryWithoutWait replaces the backoff of the retries for the test
+func retryWithoutWait(t *testing.T) {
+	orig := execWithRetryFunc
+	execWithRetryFunc = func(ctx context.Context, maxRetries uint64, _ time.Duration, f func() error) error {
+		return backoff.Retry(f, backoff.WithContext(backoff.WithMaxRetries(&backoff.ZeroBackOff{}, maxRetries), ctx))
+	}
+	t.Cleanup(func() { execWithRetryFunc = orig })
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestClient_SendStream(t *testing.T) {
+	tp := mocktracer.Start()
+	defer tp.Stop()
+
+	// Given
+	tp.Reset()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, line := range []string{`{"id":1}`, `{"id":2}`, `{"id":3}`} {
+			w.Write([]byte(line + "\n"))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewUnauthenticated(Config{URL: srv.URL, Method: http.MethodGet, ServiceName: "svc"}, NewSharedCustomPool())
+	require.NoError(t, err)
+
+	// When
+	resp, err := c.SendStream(context.Background(), Payload{})
+	require.NoError(t, err)
+
+	// Then
+	require.Equal(t, http.StatusOK, resp.Status)
+	require.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	require.Empty(t, tp.GetSpans()) // Spans are kept open while the body is read
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, []string{`{"id":1}`, `{"id":2}`, `{"id":3}`}, lines)
+
+	require.NoError(t, resp.Body.Close())
+	spans := tp.GetSpans()
+	require.Len(t, spans, 2)
+	require.Equal(t, "http.request", spans[0].Name)
+	require.Equal(t, "http.outgoing_request", spans[1].Name)
+}
+
+func TestClient_Send_StreamedBody(t *testing.T) {
+	retryWithoutWait(t)
+
+	tcs := map[string]struct {
+		givenRewind bool
+		expStatus   int
+		expCalls    int32
+		expBody     string
+	}{
+		"retried with rewind": {
+			givenRewind: true,
+			expStatus:   http.StatusOK,
+			expCalls:    2,
+			expBody:     "chapter approved",
+		},
+		"not retried without rewind": {
+			expStatus: http.StatusServiceUnavailable,
+			expCalls:  1,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, "ultramarines", string(b))
+
+				if calls.Add(1) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Write([]byte("chapter approved"))
+			}))
+			defer srv.Close()
+
+			c, err := NewUnauthenticated(
+				Config{URL: srv.URL, Method: http.MethodPost, ServiceName: "svc"},
+				NewSharedCustomPool(),
+				OverrideTimeoutAndRetryOption(1, time.Second, 5*time.Second, false, []int{http.StatusServiceUnavailable}),
+			)
+			require.NoError(t, err)
+
+			p := Payload{BodyReader: strings.NewReader("ultramarines")}
+			if tc.givenRewind {
+				p.Rewind = func() (io.Reader, error) {
+					return strings.NewReader("ultramarines"), nil
+				}
+			}
+
+			// When
+			resp, err := c.Send(context.Background(), p)
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, tc.expStatus, resp.Status)
+			require.Equal(t, tc.expBody, string(resp.Body))
+			require.Equal(t, tc.expCalls, calls.Load())
+		})
+	}
+}
+
+func TestMultipartForm(t *testing.T) {
+	retryWithoutWait(t)
+
+	// Given
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		require.Equal(t, "Codex Astartes", r.FormValue("title"))
+
+		f, h, err := r.FormFile("document")
+		require.NoError(t, err)
+		defer f.Close()
+		b, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.Equal(t, `codex "2".txt`, h.Filename)
+		require.Equal(t, "text/plain", h.Header.Get("Content-Type"))
+		require.Equal(t, "Chapters shall number a thousand", string(b))
+
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c, err := NewUnauthenticated(
+		Config{URL: srv.URL, Method: http.MethodPost, ServiceName: "svc"},
+		NewSharedCustomPool(),
+		OverrideTimeoutAndRetryOption(1, time.Second, 5*time.Second, false, []int{http.StatusBadGateway}),
+	)
+	require.NoError(t, err)
+
+	var files []*closeRecorder
+	form := NewMultipartForm().
+		AddField("title", "Codex Astartes").
+		AddFile("document", `codex "2".txt`, "text/plain", func() (io.Reader, error) {
+			f := &closeRecorder{Reader: strings.NewReader("Chapters shall number a thousand")}
+			files = append(files, f)
+			return f, nil
+		})
+
+	// When
+	resp, err := c.Send(context.Background(), form.Payload())
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.Status)
+	require.Equal(t, int32(2), calls.Load())
+	require.Len(t, files, 2)
+	for _, f := range files {
+		require.True(t, f.closed)
+	}
+}
+
+func TestMultipartForm_OpenError(t *testing.T) {
+	// Given
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	c, err := NewUnauthenticated(Config{URL: srv.URL, Method: http.MethodPost, ServiceName: "svc"}, NewSharedCustomPool())
+	require.NoError(t, err)
+
+	form := NewMultipartForm().AddFile("document", "codex.pdf", "", func() (io.Reader, error) {
+		return nil, errors.New("file not found")
+	})
+
+	// When
+	_, err = c.Send(context.Background(), form.Payload())
+
+	// Then
+	require.ErrorContains(t, err, "file not found")
+}
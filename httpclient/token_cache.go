@@ -0,0 +1,94 @@
+package httpclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/viebiz/lit/caching/redis"
+)
+
+// TokenCache stores the access tokens fetched from the token endpoint
+type TokenCache interface {
+	// Get returns the token of the key, or empty string when it is missing or expired
+	Get(ctx context.Context, key string) (string, error)
+
+	// Set stores the token of the key for the ttl
+	Set(ctx context.Context, key string, token string, ttl time.Duration) error
+
+	// Delete removes the token of the key
+	Delete(ctx context.Context, key string) error
+}
+
+// NewMemoryTokenCache returns a TokenCache storing the tokens in the memory of the process
+func NewMemoryTokenCache() TokenCache {
+	return &memoryTokenCache{
+		tokens: map[string]memoryToken{},
+		now:    time.Now,
+	}
+}
+
+// NewRedisTokenCache returns a TokenCache storing the tokens in redis, so that they are shared by all the instances
+// of the service
+func NewRedisTokenCache(client redis.Client) TokenCache {
+	return redisTokenCache{client: client}
+}
+
+type memoryToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+type memoryTokenCache struct {
+	now func() time.Time
+
+	mu     sync.RWMutex
+	tokens map[string]memoryToken
+}
+
+func (c *memoryTokenCache) Get(_ context.Context, key string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	tk, ok := c.tokens[key]
+	if !ok || !c.now().Before(tk.expiresAt) {
+		return "", nil
+	}
+
+	return tk.value, nil
+}
+
+func (c *memoryTokenCache) Set(_ context.Context, key string, token string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tokens[key] = memoryToken{value: token, expiresAt: c.now().Add(ttl)}
+
+	return nil
+}
+
+func (c *memoryTokenCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.tokens, key)
+
+	return nil
+}
+
+type redisTokenCache struct {
+	client redis.Client
+}
+
+func (c redisTokenCache) Get(ctx context.Context, key string) (string, error) {
+	return c.client.GetString(ctx, key)
+}
+
+func (c redisTokenCache) Set(ctx context.Context, key string, token string, ttl time.Duration) error {
+	return c.client.SetString(ctx, key, token, ttl)
+}
+
+func (c redisTokenCache) Delete(ctx context.Context, key string) error {
+	_, err := c.client.Delete(ctx, key)
+	return err
+}
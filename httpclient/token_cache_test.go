@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/viebiz/lit/caching/redis"
+)
+
+func TestMemoryTokenCache(t *testing.T) {
+	tcs := map[string]struct {
+		givenTTL     time.Duration
+		givenElapsed time.Duration
+		givenDelete  bool
+		expToken     string
+	}{
+		"cached": {
+			givenTTL:     time.Minute,
+			givenElapsed: 59 * time.Second,
+			expToken:     "tk-1",
+		},
+		"expired": {
+			givenTTL:     time.Minute,
+			givenElapsed: time.Minute,
+		},
+		"deleted": {
+			givenTTL:    time.Minute,
+			givenDelete: true,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			now := time.Now()
+			c := NewMemoryTokenCache().(*memoryTokenCache)
+			c.now = func() time.Time { return now }
+
+			require.NoError(t, c.Set(ctx, "key", "tk-1", tc.givenTTL))
+			if tc.givenDelete {
+				require.NoError(t, c.Delete(ctx, "key"))
+			}
+			now = now.Add(tc.givenElapsed)
+
+			// When
+			tk, err := c.Get(ctx, "key")
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, tc.expToken, tk)
+		})
+	}
+}
+
+func TestRedisTokenCache(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	client := redis.NewMockClient(t)
+	client.On("SetString", mock.Anything, "key", "tk-1", time.Minute).Return(nil)
+	client.On("GetString", mock.Anything, "key").Return("tk-1", nil)
+	client.On("Delete", mock.Anything, "key").Return(int64(1), nil)
+	c := NewRedisTokenCache(client)
+
+	// When
+	require.NoError(t, c.Set(ctx, "key", "tk-1", time.Minute))
+	tk, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	err = c.Delete(ctx, "key")
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, "tk-1", tk)
+}
@@ -0,0 +1,114 @@
+package vcr
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	bodyEncodingBase64 = "base64"
+)
+
+// Cassette holds the recorded interactions
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded request
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Response is the recorded response
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Body is recorded as text, or as base64 when it is not valid UTF-8
+type Body []byte
+
+// MarshalJSON encodes the body as text when possible to keep the cassette readable
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+
+	return json.Marshal(map[string]string{
+		"encoding": bodyEncodingBase64,
+		"data":     base64.StdEncoding.EncodeToString(b),
+	})
+}
+
+// UnmarshalJSON decodes the text or base64 body
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+
+	var encoded struct {
+		Encoding string `json:"encoding"`
+		Data     string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	if encoded.Encoding != bodyEncodingBase64 {
+		return errors.New("unsupported body encoding " + encoded.Encoding)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Data)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+
+	return nil
+}
+
+// loadCassette reads the cassette file, returning os.ErrNotExist if there is none
+func loadCassette(path string) (Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Cassette{}, pkgerrors.WithStack(err)
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return Cassette{}, pkgerrors.WithStack(err)
+	}
+
+	return c, nil
+}
+
+func saveCassette(path string, c Cassette) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return pkgerrors.WithStack(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return pkgerrors.WithStack(err)
+	}
+
+	return pkgerrors.WithStack(os.WriteFile(path, append(b, '\n'), 0o644))
+}
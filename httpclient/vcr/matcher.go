@@ -0,0 +1,55 @@
+package vcr
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+)
+
+// Matcher reports whether the request matches the recorded one, body is the read request body
+type Matcher func(r *http.Request, body []byte, rec Request) bool
+
+// MatchMethod matches the HTTP method
+func MatchMethod(r *http.Request, _ []byte, rec Request) bool {
+	return r.Method == rec.Method
+}
+
+// MatchURL matches the full URL including the query
+func MatchURL(r *http.Request, _ []byte, rec Request) bool {
+	return r.URL.String() == rec.URL
+}
+
+// MatchPath matches the path and the query of the URL regardless of the host, e.g. for httptest servers
+func MatchPath(r *http.Request, _ []byte, rec Request) bool {
+	u, err := url.Parse(rec.URL)
+	if err != nil {
+		return false
+	}
+
+	return r.URL.Path == u.Path && r.URL.Query().Encode() == u.Query().Encode()
+}
+
+// MatchBody matches the request body byte by byte
+func MatchBody(_ *http.Request, body []byte, rec Request) bool {
+	return bytes.Equal(body, rec.Body)
+}
+
+// MatchHeaders matches the values of the headers, the redacted headers only have to be present
+func MatchHeaders(names ...string) Matcher {
+	return func(r *http.Request, _ []byte, rec Request) bool {
+		for _, name := range names {
+			got, recorded := r.Header.Get(name), rec.Header.Get(name)
+			if recorded == redactedValue {
+				if got == "" {
+					return false
+				}
+				continue
+			}
+			if got != recorded {
+				return false
+			}
+		}
+
+		return true
+	}
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package vcr
+
+import (
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockMatcher is an autogenerated mock type for the Matcher type
+type MockMatcher struct {
+	mock.Mock
+}
+
+type MockMatcher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMatcher) EXPECT() *MockMatcher_Expecter {
+	return &MockMatcher_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: r, body, rec
+func (_m *MockMatcher) Execute(r *http.Request, body []byte, rec Request) bool {
+	ret := _m.Called(r, body, rec)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*http.Request, []byte, Request) bool); ok {
+		r0 = rf(r, body, rec)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockMatcher_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type MockMatcher_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - r *http.Request
+//   - body []byte
+//   - rec Request
+func (_e *MockMatcher_Expecter) Execute(r interface{}, body interface{}, rec interface{}) *MockMatcher_Execute_Call {
+	return &MockMatcher_Execute_Call{Call: _e.mock.On("Execute", r, body, rec)}
+}
+
+func (_c *MockMatcher_Execute_Call) Run(run func(r *http.Request, body []byte, rec Request)) *MockMatcher_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*http.Request), args[1].([]byte), args[2].(Request))
+	})
+	return _c
+}
+
+func (_c *MockMatcher_Execute_Call) Return(_a0 bool) *MockMatcher_Execute_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMatcher_Execute_Call) RunAndReturn(run func(*http.Request, []byte, Request) bool) *MockMatcher_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMatcher creates a new instance of MockMatcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMatcher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMatcher {
+	mock := &MockMatcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
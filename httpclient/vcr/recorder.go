@@ -0,0 +1,276 @@
+package vcr
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/viebiz/lit/httpclient"
+)
+
+const (
+	// EnvMode sets the mode of the recorders without WithMode, e.g. VCR_MODE=record go test ./... to record the
+	// cassettes again
+	EnvMode = "VCR_MODE"
+
+	redactedValue      = "[REDACTED]"
+	defaultCassetteDir = "testdata/cassettes"
+)
+
+// ErrInteractionNotFound is returned when no recorded interaction matches the request in replay mode
+var ErrInteractionNotFound = errors.New("vcr: interaction not found")
+
+// defaultRedactedHeaders are redacted from the cassettes unless overridden by WithRedactedHeaders
+var defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// Mode is the mode of the Recorder
+type Mode string
+
+const (
+	// ModeAuto replays the cassette if it exists, otherwise records it
+	ModeAuto Mode = "auto"
+	// ModeReplay replays the cassette, the requests never reach the network
+	ModeReplay Mode = "replay"
+	// ModeRecord sends the requests and records them, overwriting the cassette
+	ModeRecord Mode = "record"
+)
+
+// Option alters behaviour of the Recorder
+type Option func(r *Recorder)
+
+// WithMode sets the mode of the recorder, it takes precedence over EnvMode
+// Default: ModeAuto
+func WithMode(mode Mode) Option {
+	return func(r *Recorder) {
+		r.mode = mode
+	}
+}
+
+// WithMatchers overrides how the requests are matched with the recorded ones
+// Default: MatchMethod, MatchURL
+func WithMatchers(matchers ...Matcher) Option {
+	return func(r *Recorder) {
+		r.matchers = matchers
+	}
+}
+
+// WithRedactedHeaders overrides the headers redacted from the cassette
+// Default: Authorization, Proxy-Authorization, Cookie, Set-Cookie, X-Api-Key
+func WithRedactedHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		r.redactedHeaders = names
+	}
+}
+
+// WithCassetteDir overrides the directory of the cassettes
+// Default: testdata/cassettes
+func WithCassetteDir(dir string) Option {
+	return func(r *Recorder) {
+		r.dir = dir
+	}
+}
+
+// Recorder is a http.RoundTripper recording the interactions to a cassette file, or replaying them from it
+type Recorder struct {
+	t               testing.TB
+	dir             string
+	path            string
+	mode            Mode
+	matchers        []Matcher
+	redactedHeaders []string
+	next            http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New creates the Recorder of the cassette testdata/cassettes/<name>.json, which is saved when the test ends
+// if recording. The requests matching no interaction fail the test
+//
+// Example:
+//
+//	func TestPartnerAPI(t *testing.T) {
+//		rec := vcr.New(t, "partner_api_get_order")
+//		pool := httpclient.NewSharedCustomPool(rec.PoolOption())
+//		client, err := httpclient.NewUnauthenticated(cfg, pool)
+//		...
+//	}
+func New(t testing.TB, name string, opts ...Option) *Recorder {
+	t.Helper()
+
+	r := &Recorder{
+		t:               t,
+		dir:             defaultCassetteDir,
+		mode:            ModeAuto,
+		matchers:        []Matcher{MatchMethod, MatchURL},
+		redactedHeaders: defaultRedactedHeaders,
+		next:            http.DefaultTransport,
+	}
+	if mode := os.Getenv(EnvMode); mode != "" {
+		r.mode = Mode(mode)
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.path = filepath.Join(r.dir, name+".json")
+
+	if r.mode != ModeRecord {
+		c, err := loadCassette(r.path)
+		switch {
+		case err == nil:
+			r.cassette, r.mode = c, ModeReplay
+			r.used = make([]bool, len(c.Interactions))
+		case errors.Is(err, os.ErrNotExist) && r.mode == ModeAuto:
+			r.mode = ModeRecord
+		default:
+			t.Fatalf("vcr: failed to load cassette %s: %v", r.path, err)
+		}
+	}
+
+	if r.mode == ModeRecord {
+		t.Cleanup(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			if err := saveCassette(r.path, r.cassette); err != nil {
+				t.Errorf("vcr: failed to save cassette %s: %v", r.path, err)
+			}
+		})
+	}
+
+	return r
+}
+
+// PoolOption installs the recorder in the pool, the requests are recorded using the transport of the pool
+func (r *Recorder) PoolOption() httpclient.PoolOption {
+	return func(c *http.Client, t *http.Transport) {
+		r.next = t
+		c.Transport = r
+	}
+}
+
+// RoundTrip replays or records the request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The request of the caller must not be modified, the body read is sent with a copy of the request
+	rc := req.Body
+	body, err := readBody(&rc)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req = req.Clone(req.Context())
+		req.Body = rc
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+
+	return r.record(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || !r.matches(req, body, in.Request) {
+			continue
+		}
+		r.used[i] = true
+
+		return &http.Response{
+			Status:        strconv.Itoa(in.Response.Status) + " " + http.StatusText(in.Response.Status),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	r.t.Errorf("vcr: no interaction matching %s %s in cassette %s", req.Method, req.URL, r.path)
+	return nil, pkgerrors.WithStack(ErrInteractionNotFound)
+}
+
+func (r *Recorder) matches(req *http.Request, body []byte, rec Request) bool {
+	for _, match := range r.matchers {
+		if !match(req, body, rec) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: r.redact(req.Header),
+			Body:   body,
+		},
+		Response: Response{
+			Status: resp.StatusCode,
+			Header: r.redact(resp.Header),
+			Body:   respBody,
+		},
+	})
+
+	return resp, nil
+}
+
+func (r *Recorder) redact(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range r.redactedHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, redactedValue)
+		}
+	}
+
+	return h
+}
+
+// readBody reads the body and replaces it with a reader of what was read
+func readBody(rc *io.ReadCloser) ([]byte, error) {
+	if *rc == nil || *rc == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(*rc)
+	if err != nil {
+		return nil, pkgerrors.WithStack(err)
+	}
+	if err := (*rc).Close(); err != nil {
+		return nil, pkgerrors.WithStack(err)
+	}
+	*rc = io.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
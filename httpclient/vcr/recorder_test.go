@@ -0,0 +1,277 @@
+package vcr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/viebiz/lit/httpclient"
+)
+
+// fakeT records the failures of the recorder instead of failing the test
+type fakeT struct {
+	testing.TB
+
+	errors []string
+}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	tcs := map[string]struct {
+		givenOrderID string
+		expStatus    int
+		expBody      string
+		expErr       error
+		expFailures  []string
+	}{
+		"matched": {
+			givenOrderID: "42",
+			expStatus:    http.StatusOK,
+			expBody:      `{"id":42,"status":"shipped","items":[{"sku":"BOLTER-01","quantity":2}]}`,
+		},
+		"matched error response": {
+			givenOrderID: "404",
+			expStatus:    http.StatusNotFound,
+			expBody:      `{"title":"Order not found","status":404}`,
+		},
+		"error - unmatched": {
+			givenOrderID: "7",
+			expErr:       ErrInteractionNotFound,
+			expFailures: []string{
+				"vcr: no interaction matching GET https://partner.example.com/api/v1/orders/7?expand=items in cassette testdata/cassettes/partner_get_order.json",
+			},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			t.Setenv(EnvMode, "")
+			ft := &fakeT{TB: t}
+			rec := New(ft, "partner_get_order", WithMode(ModeReplay))
+
+			c, err := httpclient.NewWithAPIKey(
+				httpclient.Config{URL: "https://partner.example.com/api/v1/orders/:id", Method: http.MethodGet, ServiceName: "partner"},
+				httpclient.NewSharedCustomPool(rec.PoolOption()),
+				httpclient.APIKeyConfig{Key: "Authorization", Value: "Bearer live-token"},
+			)
+			require.NoError(t, err)
+
+			// When
+			resp, err := c.Send(context.Background(), httpclient.Payload{
+				PathVars:    map[string]string{"id": tc.givenOrderID},
+				QueryParams: url.Values{"expand": {"items"}},
+			})
+
+			// Then
+			require.Equal(t, tc.expFailures, ft.errors)
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expStatus, resp.Status)
+			require.Equal(t, tc.expBody, string(resp.Body))
+		})
+	}
+}
+
+func TestRecorder_RecordThenReplay(t *testing.T) {
+	// Given
+	t.Setenv(EnvMode, "")
+	dir := t.TempDir()
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"echo":%s}`, b)
+	}))
+	defer srv.Close()
+
+	send := func(rec *Recorder, body string) httpclient.Response {
+		c, err := httpclient.NewUnauthenticated(
+			httpclient.Config{URL: srv.URL + "/orders", Method: http.MethodPost, ServiceName: "partner"},
+			httpclient.NewSharedCustomPool(rec.PoolOption()),
+			httpclient.OverrideBaseRequestHeaders(map[string]string{"Authorization": "Bearer live-token"}),
+		)
+		require.NoError(t, err)
+
+		resp, err := c.Send(context.Background(), httpclient.Payload{Body: []byte(body)})
+		require.NoError(t, err)
+		return resp
+	}
+
+	// When
+	t.Run("record", func(t *testing.T) {
+		rec := New(t, "orders", WithCassetteDir(dir), WithMatchers(MatchMethod, MatchPath, MatchBody, MatchHeaders("Authorization")))
+		send(rec, `{"id":1}`)
+		send(rec, `{"id":2}`)
+	})
+	srv.Close() // Replay offline
+
+	t.Run("replay", func(t *testing.T) {
+		rec := New(t, "orders", WithCassetteDir(dir), WithMatchers(MatchMethod, MatchPath, MatchBody, MatchHeaders("Authorization")))
+		resp := send(rec, `{"id":2}`)
+
+		// Then
+		require.Equal(t, http.StatusCreated, resp.Status)
+		require.Equal(t, `{"echo":{"id":2}}`, string(resp.Body))
+	})
+
+	require.Equal(t, 2, calls)
+	b, err := os.ReadFile(filepath.Join(dir, "orders.json"))
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "live-token")
+	require.NotContains(t, string(b), "session=abc")
+	require.Equal(t, 4, strings.Count(string(b), redactedValue)) // Authorization & Set-Cookie of both interactions
+}
+
+func TestNew_ModePrecedence(t *testing.T) {
+	tcs := map[string]struct {
+		givenEnv  string
+		givenOpts []Option
+		expMode   Mode
+	}{
+		"explicit mode over env": {
+			givenEnv:  string(ModeRecord),
+			givenOpts: []Option{WithMode(ModeReplay)},
+			expMode:   ModeReplay,
+		},
+		"env without explicit mode": {
+			givenEnv: string(ModeReplay),
+			expMode:  ModeReplay,
+		},
+		"cassette replayed by default": {
+			expMode: ModeReplay,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			t.Setenv(EnvMode, tc.givenEnv)
+
+			// When
+			rec := New(t, "partner_get_order", tc.givenOpts...)
+
+			// Then
+			require.Equal(t, tc.expMode, rec.mode)
+		})
+	}
+}
+
+func TestRecorder_RoundTrip_RequestNotModified(t *testing.T) {
+	// Given
+	t.Setenv(EnvMode, "")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"id":1}`, string(b))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	rec := New(t, "orders", WithCassetteDir(t.TempDir()), WithMode(ModeRecord))
+	rec.next = srv.Client().Transport
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/orders", strings.NewReader(`{"id":1}`))
+	require.NoError(t, err)
+	givenBody := req.Body
+
+	// When
+	resp, err := rec.RoundTrip(req)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.True(t, givenBody == req.Body)
+	require.Len(t, rec.cassette.Interactions, 1)
+	require.Equal(t, []byte(`{"id":1}`), []byte(rec.cassette.Interactions[0].Request.Body))
+}
+
+func TestBody_JSON(t *testing.T) {
+	tcs := map[string]struct {
+		given   Body
+		expJSON string
+	}{
+		"text": {
+			given:   Body(`{"id":1}`),
+			expJSON: `"{\"id\":1}"`,
+		},
+		"binary": {
+			given:   Body{0xff, 0xd8, 0xff},
+			expJSON: `{"data":"/9j/","encoding":"base64"}`,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given & When
+			b, err := tc.given.MarshalJSON()
+			require.NoError(t, err)
+
+			var decoded Body
+			err = decoded.UnmarshalJSON(b)
+
+			// Then
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expJSON, string(b))
+			require.Equal(t, tc.given, decoded)
+		})
+	}
+}
+
+func TestMatchHeaders(t *testing.T) {
+	tcs := map[string]struct {
+		givenHeader http.Header
+		givenRec    http.Header
+		exp         bool
+	}{
+		"equal": {
+			givenHeader: http.Header{"X-Tenant": {"ultramar"}},
+			givenRec:    http.Header{"X-Tenant": {"ultramar"}},
+			exp:         true,
+		},
+		"different": {
+			givenHeader: http.Header{"X-Tenant": {"cadia"}},
+			givenRec:    http.Header{"X-Tenant": {"ultramar"}},
+		},
+		"redacted is present": {
+			givenHeader: http.Header{"X-Tenant": {"ultramar"}},
+			givenRec:    http.Header{"X-Tenant": {redactedValue}},
+			exp:         true,
+		},
+		"redacted is missing": {
+			givenRec: http.Header{"X-Tenant": {redactedValue}},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header = tc.givenHeader
+			if r.Header == nil {
+				r.Header = http.Header{}
+			}
+
+			// When & Then
+			require.Equal(t, tc.exp, MatchHeaders("X-Tenant")(r, nil, Request{Header: tc.givenRec}))
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package httpsig
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/viebiz/lit/jwt"
+)
+
+// Algorithm is the signature algorithm, as registered in the HTTP Signature Algorithms registry of RFC 9421
+type Algorithm string
+
+const (
+	// AlgorithmHMACSHA256 signs with jwt.HMACPrivateKey and verifies with jwt.HMACPrivateKey
+	AlgorithmHMACSHA256 Algorithm = "hmac-sha256"
+	// AlgorithmRSAV15SHA256 signs with *rsa.PrivateKey and verifies with *rsa.PublicKey
+	AlgorithmRSAV15SHA256 Algorithm = "rsa-v1_5-sha256"
+	// AlgorithmRSAPSSSHA512 signs with a RSA jwt.Signer (e.g. *rsa.PrivateKey or a KMS key) and verifies with *rsa.PublicKey
+	AlgorithmRSAPSSSHA512 Algorithm = "rsa-pss-sha512"
+	// AlgorithmECDSAP256SHA256 signs with a P-256 jwt.Signer (e.g. *ecdsa.PrivateKey) and verifies with *ecdsa.PublicKey
+	AlgorithmECDSAP256SHA256 Algorithm = "ecdsa-p256-sha256"
+	// AlgorithmECDSAP384SHA384 signs with a P-384 jwt.Signer (e.g. *ecdsa.PrivateKey) and verifies with *ecdsa.PublicKey
+	AlgorithmECDSAP384SHA384 Algorithm = "ecdsa-p384-sha384"
+	// AlgorithmEd25519 signs with ed25519.PrivateKey and verifies with ed25519.PublicKey
+	AlgorithmEd25519 Algorithm = "ed25519"
+)
+
+// IsValid reports whether the algorithm is supported
+func (alg Algorithm) IsValid() bool {
+	switch alg {
+	case AlgorithmHMACSHA256, AlgorithmRSAV15SHA256, AlgorithmRSAPSSSHA512,
+		AlgorithmECDSAP256SHA256, AlgorithmECDSAP384SHA384, AlgorithmEd25519:
+		return true
+	}
+
+	return false
+}
+
+// sign signs the signature base with the key
+func (alg Algorithm) sign(base []byte, key jwt.Signer) ([]byte, error) {
+	switch alg {
+	case AlgorithmHMACSHA256:
+		return mapJWTErr(jwt.NewHS256().Sign(base, key))
+	case AlgorithmRSAV15SHA256:
+		return mapJWTErr(jwt.NewRS256().Sign(base, key))
+	case AlgorithmRSAPSSSHA512:
+		return mapJWTErr(jwt.NewPS512().Sign(base, key))
+	case AlgorithmECDSAP256SHA256:
+		return mapJWTErr(jwt.NewES256().Sign(base, key))
+	case AlgorithmECDSAP384SHA384:
+		return mapJWTErr(jwt.NewES384().Sign(base, key))
+	case AlgorithmEd25519:
+		if _, ok := key.(ed25519.PrivateKey); !ok {
+			return nil, ErrInvalidKeyType
+		}
+		return key.Sign(rand.Reader, base, crypto.Hash(0))
+	}
+
+	return nil, ErrUnsupportedAlgorithm
+}
+
+// verify verifies the signature of the signature base with the key
+func (alg Algorithm) verify(base, sig []byte, key jwt.VerifyKey) error {
+	switch alg {
+	case AlgorithmHMACSHA256:
+		if _, ok := key.(jwt.HMACPrivateKey); !ok {
+			return ErrInvalidKeyType
+		}
+		_, err := mapJWTErr(nil, jwt.NewHS256().Verify(base, sig, key))
+		return err
+	case AlgorithmRSAV15SHA256:
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrInvalidKeyType
+		}
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest(crypto.SHA256, base), sig); err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+	case AlgorithmRSAPSSSHA512:
+		_, err := mapJWTErr(nil, jwt.NewPS512().Verify(base, sig, key))
+		return err
+	case AlgorithmECDSAP256SHA256:
+		_, err := mapJWTErr(nil, jwt.NewES256().Verify(base, sig, key))
+		return err
+	case AlgorithmECDSAP384SHA384:
+		_, err := mapJWTErr(nil, jwt.NewES384().Verify(base, sig, key))
+		return err
+	case AlgorithmEd25519:
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return ErrInvalidKeyType
+		}
+		if !ed25519.Verify(pub, base, sig) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+
+	return ErrUnsupportedAlgorithm
+}
+
+func digest(hash crypto.Hash, b []byte) []byte {
+	h := hash.New()
+	h.Write(b)
+
+	return h.Sum(nil)
+}
+
+// mapJWTErr maps the errors of the jwt signing methods to the ones of this package
+func mapJWTErr(b []byte, err error) ([]byte, error) {
+	switch {
+	case err == nil:
+		return b, nil
+	case errors.Is(err, jwt.ErrInvalidKeyType), errors.Is(err, jwt.ErrInvalidKeyCurve):
+		return nil, ErrInvalidKeyType
+	case errors.Is(err, jwt.ErrInvalidSignature):
+		return nil, ErrInvalidSignature
+	}
+
+	return nil, pkgerrors.WithStack(err)
+}
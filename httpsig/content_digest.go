@@ -0,0 +1,59 @@
+package httpsig
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// HeaderContentDigest is the Content-Digest field of RFC 9530, covered as content-digest
+	HeaderContentDigest = "Content-Digest"
+
+	componentContentDigest = "content-digest"
+)
+
+// digestAlgorithms are the supported algorithms of the Content-Digest field, by order of preference
+var digestAlgorithms = []struct {
+	name string
+	hash crypto.Hash
+}{
+	{name: "sha-512", hash: crypto.SHA512},
+	{name: "sha-256", hash: crypto.SHA256},
+}
+
+// ContentDigest returns the value of the Content-Digest field of the body using sha-256
+func ContentDigest(body []byte) string {
+	return "sha-256=" + serializeByteSequence(digest(crypto.SHA256, body))
+}
+
+// verifyContentDigest verifies the body against the strongest supported digest of the Content-Digest field
+func verifyContentDigest(h http.Header, body []byte) error {
+	raw := strings.Join(h.Values(HeaderContentDigest), ", ")
+	if raw == "" {
+		return fmt.Errorf("%w: %s", ErrMissingComponent, componentContentDigest)
+	}
+
+	members, err := parseDictionary(raw)
+	if err != nil {
+		return ErrContentDigestMismatch
+	}
+
+	for _, alg := range digestAlgorithms {
+		for _, m := range members {
+			if m.key != alg.name {
+				continue
+			}
+
+			expected, ok := m.value.([]byte)
+			if !ok || subtle.ConstantTimeCompare(expected, digest(alg.hash, body)) != 1 {
+				return ErrContentDigestMismatch
+			}
+			return nil
+		}
+	}
+
+	return ErrUnsupportedDigestAlgorithm
+}
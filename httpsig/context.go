@@ -0,0 +1,25 @@
+package httpsig
+
+import (
+	"context"
+)
+
+type contextkey string
+
+const (
+	contextKeyKeyID = contextkey("httpsig-key-id")
+)
+
+// SetKeyIDInContext keeps the key id of the verified signature of the request
+func SetKeyIDInContext(ctx context.Context, keyID string) context.Context {
+	return context.WithValue(ctx, contextKeyKeyID, keyID)
+}
+
+// GetKeyIDFromContext returns the key id of the verified signature of the request, if any
+func GetKeyIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKeyKeyID).(string); ok {
+		return id
+	}
+
+	return ""
+}
@@ -0,0 +1,28 @@
+// Package httpsig provides HTTP Message Signatures (RFC 9421) for signing the outgoing requests
+// and verifying the incoming ones
+//
+// Supported algorithms: hmac-sha256, rsa-v1_5-sha256, rsa-pss-sha512, ecdsa-p256-sha256, ecdsa-p384-sha384, ed25519
+//
+// Usage example:
+//
+//	// Client side, see httpclient.WithMessageSignature
+//	signer, err := httpsig.NewSigner(httpsig.SignerConfig{
+//		KeyID:     "orders-2024",
+//		Key:       jwt.HMACPrivateKey(secret),
+//		Algorithm: httpsig.AlgorithmHMACSHA256,
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	client, err := httpclient.NewUnauthenticated(cfg, pool, httpclient.WithMessageSignature(signer))
+//
+//	// Server side, see SignatureVerificationMiddleware of middleware/http
+//	verifier, err := httpsig.NewVerifier(httpsig.VerifierConfig{
+//		Resolver: keyResolver,
+//		Nonces:   redis.NewNonceStore(redisClient),
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	router.Use(httpmiddleware.SignatureVerificationMiddleware(verifier))
+package httpsig
@@ -0,0 +1,41 @@
+package httpsig
+
+import (
+	"errors"
+)
+
+// Error represents an error in httpsig package, the verification failures are of this type so that they can be
+// told apart from the unexpected errors, e.g. of the NonceStore
+type Error string
+
+func newError(msg string) Error {
+	return Error(msg)
+}
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+var (
+	ErrSignerConfigInvalid   = errors.New("signer config invalid")
+	ErrVerifierConfigInvalid = errors.New("verifier config invalid")
+	ErrUnsupportedAlgorithm  = errors.New("unsupported signature algorithm")
+	ErrInvalidKeyType        = errors.New("invalid key type")
+)
+
+// The verification failures
+var (
+	ErrMissingSignature           = newError("missing signature")
+	ErrMalformedSignature         = newError("malformed signature")
+	ErrUnsupportedComponent       = newError("unsupported covered component")
+	ErrMissingComponent           = newError("missing covered component")
+	ErrUnknownKey                 = newError("unknown key")
+	ErrAlgorithmMismatch          = newError("signature algorithm mismatch")
+	ErrInvalidSignature           = newError("invalid signature")
+	ErrSignatureExpired           = newError("signature is expired")
+	ErrSignatureNotValidYet       = newError("signature is not valid yet")
+	ErrContentDigestMismatch      = newError("content digest mismatch")
+	ErrMissingNonce               = newError("missing nonce")
+	ErrReplayedRequest            = newError("replayed request")
+	ErrUnsupportedDigestAlgorithm = newError("unsupported content digest algorithm")
+)
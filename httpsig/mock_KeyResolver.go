@@ -0,0 +1,93 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package httpsig
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockKeyResolver is an autogenerated mock type for the KeyResolver type
+type MockKeyResolver struct {
+	mock.Mock
+}
+
+type MockKeyResolver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockKeyResolver) EXPECT() *MockKeyResolver_Expecter {
+	return &MockKeyResolver_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: ctx, keyID
+func (_m *MockKeyResolver) Execute(ctx context.Context, keyID string) (VerificationKey, error) {
+	ret := _m.Called(ctx, keyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 VerificationKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (VerificationKey, error)); ok {
+		return rf(ctx, keyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) VerificationKey); ok {
+		r0 = rf(ctx, keyID)
+	} else {
+		r0 = ret.Get(0).(VerificationKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, keyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockKeyResolver_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type MockKeyResolver_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keyID string
+func (_e *MockKeyResolver_Expecter) Execute(ctx interface{}, keyID interface{}) *MockKeyResolver_Execute_Call {
+	return &MockKeyResolver_Execute_Call{Call: _e.mock.On("Execute", ctx, keyID)}
+}
+
+func (_c *MockKeyResolver_Execute_Call) Run(run func(ctx context.Context, keyID string)) *MockKeyResolver_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockKeyResolver_Execute_Call) Return(_a0 VerificationKey, _a1 error) *MockKeyResolver_Execute_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockKeyResolver_Execute_Call) RunAndReturn(run func(context.Context, string) (VerificationKey, error)) *MockKeyResolver_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockKeyResolver creates a new instance of MockKeyResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockKeyResolver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockKeyResolver {
+	mock := &MockKeyResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
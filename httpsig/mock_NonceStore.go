@@ -0,0 +1,95 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package httpsig
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockNonceStore is an autogenerated mock type for the NonceStore type
+type MockNonceStore struct {
+	mock.Mock
+}
+
+type MockNonceStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockNonceStore) EXPECT() *MockNonceStore_Expecter {
+	return &MockNonceStore_Expecter{mock: &_m.Mock}
+}
+
+// Remember provides a mock function with given fields: ctx, nonce, ttl
+func (_m *MockNonceStore) Remember(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(ctx, nonce, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remember")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (bool, error)); ok {
+		return rf(ctx, nonce, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) bool); ok {
+		r0 = rf(ctx, nonce, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, nonce, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockNonceStore_Remember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Remember'
+type MockNonceStore_Remember_Call struct {
+	*mock.Call
+}
+
+// Remember is a helper method to define mock.On call
+//   - ctx context.Context
+//   - nonce string
+//   - ttl time.Duration
+func (_e *MockNonceStore_Expecter) Remember(ctx interface{}, nonce interface{}, ttl interface{}) *MockNonceStore_Remember_Call {
+	return &MockNonceStore_Remember_Call{Call: _e.mock.On("Remember", ctx, nonce, ttl)}
+}
+
+func (_c *MockNonceStore_Remember_Call) Run(run func(ctx context.Context, nonce string, ttl time.Duration)) *MockNonceStore_Remember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockNonceStore_Remember_Call) Return(_a0 bool, _a1 error) *MockNonceStore_Remember_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockNonceStore_Remember_Call) RunAndReturn(run func(context.Context, string, time.Duration) (bool, error)) *MockNonceStore_Remember_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockNonceStore creates a new instance of MockNonceStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockNonceStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockNonceStore {
+	mock := &MockNonceStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
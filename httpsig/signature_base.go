@@ -0,0 +1,203 @@
+package httpsig
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Derived components supported in the covered components, the other components are HTTP fields
+const (
+	ComponentMethod        = "@method"
+	ComponentTargetURI     = "@target-uri"
+	ComponentAuthority     = "@authority"
+	ComponentScheme        = "@scheme"
+	ComponentRequestTarget = "@request-target"
+	ComponentPath          = "@path"
+	ComponentQuery         = "@query"
+
+	componentSignatureParams = "@signature-params"
+)
+
+// signatureParams is the signature parameters of the Signature-Input field
+type signatureParams struct {
+	components []string
+	created    time.Time
+	expires    time.Time
+	nonce      string
+	alg        Algorithm
+	keyID      string
+	tag        string
+}
+
+// String serializes the parameters as the value of the Signature-Input member, and the @signature-params
+// component of the signature base
+func (sp signatureParams) String() string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for i, c := range sp.components {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(serializeString(c))
+	}
+	b.WriteByte(')')
+
+	if !sp.created.IsZero() {
+		b.WriteString(";created=" + strconv.FormatInt(sp.created.Unix(), 10))
+	}
+	if !sp.expires.IsZero() {
+		b.WriteString(";expires=" + strconv.FormatInt(sp.expires.Unix(), 10))
+	}
+	if sp.nonce != "" {
+		b.WriteString(";nonce=" + serializeString(sp.nonce))
+	}
+	if sp.alg != "" {
+		b.WriteString(";alg=" + serializeString(string(sp.alg)))
+	}
+	if sp.keyID != "" {
+		b.WriteString(";keyid=" + serializeString(sp.keyID))
+	}
+	if sp.tag != "" {
+		b.WriteString(";tag=" + serializeString(sp.tag))
+	}
+
+	return b.String()
+}
+
+// covers reports whether the component is covered by the signature
+func (sp signatureParams) covers(component string) bool {
+	for _, c := range sp.components {
+		if c == component {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseSignatureParams parses the Signature-Input member
+func parseSignatureParams(m sfMember) (signatureParams, error) {
+	if !m.list {
+		return signatureParams{}, fmt.Errorf("%w: %s is not an inner list", ErrMalformedSignature, m.key)
+	}
+
+	sp := signatureParams{components: m.items}
+	for _, p := range m.params {
+		var ok bool
+		switch p.key {
+		case "created", "expires":
+			var n int64
+			if n, ok = p.value.(int64); ok {
+				if p.key == "created" {
+					sp.created = time.Unix(n, 0)
+				} else {
+					sp.expires = time.Unix(n, 0)
+				}
+			}
+		case "nonce":
+			sp.nonce, ok = p.value.(string)
+		case "alg":
+			var alg string
+			alg, ok = p.value.(string)
+			sp.alg = Algorithm(alg)
+		case "keyid":
+			sp.keyID, ok = p.value.(string)
+		case "tag":
+			sp.tag, ok = p.value.(string)
+		default:
+			ok = true // Unknown parameters are covered by the signature but ignored
+		}
+		if !ok {
+			return signatureParams{}, fmt.Errorf("%w: invalid parameter %s", ErrMalformedSignature, p.key)
+		}
+	}
+
+	return sp, nil
+}
+
+// signatureBase creates the signature base of the request, rawParams is the serialized signature parameters
+func signatureBase(r *http.Request, components []string, rawParams string) ([]byte, error) {
+	var b strings.Builder
+	seen := make(map[string]bool, len(components))
+	for _, c := range components {
+		if seen[c] {
+			return nil, fmt.Errorf("%w: duplicated component %s", ErrMalformedSignature, c)
+		}
+		seen[c] = true
+
+		v, err := componentValue(r, c)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(serializeString(c) + ": " + v + "\n")
+	}
+	b.WriteString(serializeString(componentSignatureParams) + ": " + rawParams)
+
+	return []byte(b.String()), nil
+}
+
+// componentValue returns the value of the component of the request
+func componentValue(r *http.Request, component string) (string, error) {
+	switch component {
+	case ComponentMethod:
+		return r.Method, nil
+	case ComponentTargetURI:
+		return scheme(r) + "://" + authority(r) + r.URL.RequestURI(), nil
+	case ComponentAuthority:
+		return authority(r), nil
+	case ComponentScheme:
+		return scheme(r), nil
+	case ComponentRequestTarget:
+		return r.URL.RequestURI(), nil
+	case ComponentPath:
+		if p := r.URL.EscapedPath(); p != "" {
+			return p, nil
+		}
+		return "/", nil
+	case ComponentQuery:
+		return "?" + r.URL.RawQuery, nil
+	}
+
+	if strings.HasPrefix(component, "@") || component != strings.ToLower(component) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedComponent, component)
+	}
+
+	values := r.Header.Values(component)
+	if component == "host" && r.Host != "" { // Moved out of the header by net/http
+		values = []string{r.Host}
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrMissingComponent, component)
+	}
+	trimmed := make([]string, len(values))
+	for i, v := range values {
+		trimmed[i] = strings.TrimSpace(v)
+	}
+
+	return strings.Join(trimmed, ", "), nil
+}
+
+// scheme returns the scheme of the request, whether it is sent by a client or received by a server
+func scheme(r *http.Request) string {
+	if r.URL.Scheme != "" {
+		return strings.ToLower(r.URL.Scheme)
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
+
+// authority returns the authority of the request, whether it is sent by a client or received by a server
+func authority(r *http.Request) string {
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+
+	return strings.ToLower(host)
+}
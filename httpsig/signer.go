@@ -0,0 +1,126 @@
+package httpsig
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/viebiz/lit/jwt"
+)
+
+const (
+	// HeaderSignatureInput is the Signature-Input field holding the signature parameters
+	HeaderSignatureInput = "Signature-Input"
+	// HeaderSignature is the Signature field holding the signatures
+	HeaderSignature = "Signature"
+
+	defaultLabel = "sig1"
+)
+
+// defaultComponents are covered by the signature unless overridden
+var defaultComponents = []string{ComponentMethod, ComponentTargetURI, componentContentDigest}
+
+// SignerConfig holds the config of the Signer
+type SignerConfig struct {
+	// KeyID identifies the key for the verifier, sent as the keyid parameter
+	KeyID string
+	// Key signs the requests, e.g. jwt.HMACPrivateKey, *rsa.PrivateKey, *ecdsa.PrivateKey or a KMS key
+	Key jwt.Signer
+	// Algorithm of the signature, which must match the key
+	Algorithm Algorithm
+	// Components covered by the signature, content-digest adds the Content-Digest field of the body
+	// Default: @method, @target-uri, content-digest
+	Components []string
+	// Label of the signature in the Signature-Input and Signature fields
+	// Default: sig1
+	Label string
+	// TTL of the signature, sent as the expires parameter. Setting to 0 means no expires parameter
+	TTL time.Duration
+	// DisableNonce disables the random nonce parameter, which the verifiers need to detect replayed requests
+	DisableNonce bool
+	// Tag is the application specific tag parameter, if any
+	Tag string
+	// IncludeAlgorithm sends the alg parameter, which RFC 9421 recommends to leave out as the verifier
+	// should get the algorithm from the key
+	IncludeAlgorithm bool
+}
+
+// Signer signs the requests per RFC 9421
+type Signer struct {
+	cfg SignerConfig
+	now func() time.Time
+}
+
+// NewSigner creates the Signer
+//
+// Example:
+//
+//	signer, err := httpsig.NewSigner(httpsig.SignerConfig{
+//		KeyID:     "partner-2024",
+//		Key:       privateKey, // *ecdsa.PrivateKey
+//		Algorithm: httpsig.AlgorithmECDSAP256SHA256,
+//		TTL:       time.Minute,
+//	})
+func NewSigner(cfg SignerConfig) (Signer, error) {
+	if cfg.KeyID == "" || cfg.Key == nil {
+		return Signer{}, ErrSignerConfigInvalid
+	}
+	if !cfg.Algorithm.IsValid() {
+		return Signer{}, ErrUnsupportedAlgorithm
+	}
+	if len(cfg.Components) == 0 {
+		cfg.Components = defaultComponents
+	}
+	if cfg.Label == "" {
+		cfg.Label = defaultLabel
+	}
+
+	return Signer{cfg: cfg, now: time.Now}, nil
+}
+
+// CoversContentDigest reports whether the Content-Digest field is covered by the signature
+func (s Signer) CoversContentDigest() bool {
+	return signatureParams{components: s.cfg.Components}.covers(componentContentDigest)
+}
+
+// Sign signs the request with the body, setting the Signature-Input and Signature fields and the Content-Digest
+// field if covered and not set yet
+func (s Signer) Sign(r *http.Request, body []byte) error {
+	if s.CoversContentDigest() && r.Header.Get(HeaderContentDigest) == "" {
+		r.Header.Set(HeaderContentDigest, ContentDigest(body))
+	}
+
+	now := s.now()
+	sp := signatureParams{
+		components: s.cfg.Components,
+		created:    now,
+		keyID:      s.cfg.KeyID,
+		tag:        s.cfg.Tag,
+	}
+	if s.cfg.TTL > 0 {
+		sp.expires = now.Add(s.cfg.TTL)
+	}
+	if !s.cfg.DisableNonce {
+		sp.nonce = uuid.NewString()
+	}
+	if s.cfg.IncludeAlgorithm {
+		sp.alg = s.cfg.Algorithm
+	}
+
+	rawParams := sp.String()
+	base, err := signatureBase(r, sp.components, rawParams)
+	if err != nil {
+		return err
+	}
+
+	sig, err := s.cfg.Algorithm.sign(base, s.cfg.Key)
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set(HeaderSignatureInput, s.cfg.Label+"="+rawParams)
+	r.Header.Set(HeaderSignature, s.cfg.Label+"="+serializeByteSequence(sig))
+
+	return nil
+}
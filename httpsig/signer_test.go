@@ -0,0 +1,193 @@
+package httpsig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/viebiz/lit/jwt"
+)
+
+// rfcSharedSecret is the key of the HMAC example of RFC 9421 B.2.5
+func rfcSharedSecret(t *testing.T) jwt.HMACPrivateKey {
+	key, err := base64.StdEncoding.DecodeString("uzvJfB4u3N0Jy4T7NZ75MDVcr8zSTInedJtkgcu46YW4XByzNJjxBdtjUkdJPBtbmHhIDi6pcl8jsasjlTMtDQ==")
+	require.NoError(t, err)
+
+	return key
+}
+
+// rfcRequest is the test request of RFC 9421 B.2
+func rfcRequest(t *testing.T) *http.Request {
+	r, err := http.NewRequest(http.MethodPost, "http://example.com/foo?param=Value&Pet=dog", strings.NewReader(`{"hello": "world"}`))
+	require.NoError(t, err)
+	r.Header.Set("Date", "Tue, 20 Apr 2021 02:07:55 GMT")
+	r.Header.Set("Content-Type", "application/json")
+
+	return r
+}
+
+func TestSigner_Sign(t *testing.T) {
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		givenCfg          SignerConfig
+		givenHeader       http.Header
+		givenBody         string
+		expSignatureInput string
+		expSignature      string
+		expContentDigest  string
+		expErr            error
+	}{
+		"RFC 9421 B.2.5 HMAC example": {
+			givenCfg: SignerConfig{
+				KeyID:        "test-shared-secret",
+				Key:          rfcSharedSecret(t),
+				Algorithm:    AlgorithmHMACSHA256,
+				Components:   []string{"date", ComponentAuthority, "content-type"},
+				Label:        "sig-b25",
+				DisableNonce: true,
+			},
+			expSignatureInput: `sig-b25=("date" "@authority" "content-type");created=1618884473;keyid="test-shared-secret"`,
+			expSignature:      "sig-b25=:pxcQw6G3AjtMBQjwo8XzkZf/bws5LelbaMk5rGIGtE8=:",
+		},
+		"content digest, expires, alg & tag": {
+			givenCfg: SignerConfig{
+				KeyID:            "test-shared-secret",
+				Key:              rfcSharedSecret(t),
+				Algorithm:        AlgorithmHMACSHA256,
+				TTL:              time.Minute,
+				DisableNonce:     true,
+				Tag:              "orders",
+				IncludeAlgorithm: true,
+			},
+			givenBody:         `{"hello": "world"}`,
+			expSignatureInput: `sig1=("@method" "@target-uri" "content-digest");created=1618884473;expires=1618884533;alg="hmac-sha256";keyid="test-shared-secret";tag="orders"`,
+			expSignature:      "sig1=:6HQj8P0T6zNGqFvUAG6y48W6kkRgmZuP4g5F/jSnHlM=:",
+			expContentDigest:  "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:",
+		},
+		"content digest already set": {
+			givenCfg: SignerConfig{
+				KeyID:        "test-shared-secret",
+				Key:          rfcSharedSecret(t),
+				Algorithm:    AlgorithmHMACSHA256,
+				DisableNonce: true,
+			},
+			givenHeader:       http.Header{"Content-Digest": {"sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:"}},
+			expSignatureInput: `sig1=("@method" "@target-uri" "content-digest");created=1618884473;keyid="test-shared-secret"`,
+			expSignature:      "sig1=:jF3BjnLonii+/rddVHqb+RG6LDdwJ1LTZnEeep1Ud5E=:",
+			expContentDigest:  "sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:",
+		},
+		"error - missing component": {
+			givenCfg: SignerConfig{
+				KeyID:      "test-shared-secret",
+				Key:        rfcSharedSecret(t),
+				Algorithm:  AlgorithmHMACSHA256,
+				Components: []string{ComponentMethod, "x-tenant"},
+			},
+			expErr: ErrMissingComponent,
+		},
+		"error - unsupported component": {
+			givenCfg: SignerConfig{
+				KeyID:      "test-shared-secret",
+				Key:        rfcSharedSecret(t),
+				Algorithm:  AlgorithmHMACSHA256,
+				Components: []string{"@status"},
+			},
+			expErr: ErrUnsupportedComponent,
+		},
+		"error - key of another algorithm": {
+			givenCfg: SignerConfig{
+				KeyID:     "test-shared-secret",
+				Key:       rfcSharedSecret(t),
+				Algorithm: AlgorithmRSAV15SHA256,
+			},
+			expErr: ErrInvalidKeyType,
+		},
+		"error - key of a larger curve": {
+			givenCfg: SignerConfig{
+				KeyID:     "p384",
+				Key:       p384Key,
+				Algorithm: AlgorithmECDSAP256SHA256,
+			},
+			expErr: ErrInvalidKeyType,
+		},
+		"error - key of a smaller curve": {
+			givenCfg: SignerConfig{
+				KeyID:     "p256",
+				Key:       p256Key,
+				Algorithm: AlgorithmECDSAP384SHA384,
+			},
+			expErr: ErrInvalidKeyType,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			s, err := NewSigner(tc.givenCfg)
+			require.NoError(t, err)
+			s.now = func() time.Time { return time.Unix(1618884473, 0) }
+
+			r := rfcRequest(t)
+			for k, v := range tc.givenHeader {
+				r.Header[k] = v
+			}
+
+			// When
+			err = s.Sign(r, []byte(tc.givenBody))
+
+			// Then
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expSignatureInput, r.Header.Get(HeaderSignatureInput))
+			require.Equal(t, tc.expSignature, r.Header.Get(HeaderSignature))
+			require.Equal(t, tc.expContentDigest, r.Header.Get(HeaderContentDigest))
+		})
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	tcs := map[string]struct {
+		given  SignerConfig
+		expErr error
+	}{
+		"valid": {
+			given: SignerConfig{KeyID: "k", Key: jwt.HMACPrivateKey("secret"), Algorithm: AlgorithmHMACSHA256},
+		},
+		"error - missing key id": {
+			given:  SignerConfig{Key: jwt.HMACPrivateKey("secret"), Algorithm: AlgorithmHMACSHA256},
+			expErr: ErrSignerConfigInvalid,
+		},
+		"error - missing key": {
+			given:  SignerConfig{KeyID: "k", Algorithm: AlgorithmHMACSHA256},
+			expErr: ErrSignerConfigInvalid,
+		},
+		"error - unsupported algorithm": {
+			given:  SignerConfig{KeyID: "k", Key: jwt.HMACPrivateKey("secret"), Algorithm: "hmac-md5"},
+			expErr: ErrUnsupportedAlgorithm,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given & When
+			_, err := NewSigner(tc.given)
+
+			// Then
+			require.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package httpsig
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The subset of the Structured Field Values (RFC 8941) used by the Signature-Input, Signature and
+// Content-Digest fields: dictionaries of inner lists of strings or of byte sequences, with parameters
+
+// sfToken is a structured field token, e.g. sha-256
+type sfToken string
+
+// sfParam is a parameter of a structured field item or inner list
+type sfParam struct {
+	key   string
+	value any // string, sfToken, int64, bool or []byte
+}
+
+// sfMember is a member of a structured field dictionary
+type sfMember struct {
+	key    string
+	items  []string // Strings of the inner list if the value is an inner list
+	list   bool
+	value  any // Value of the item if the value is not an inner list
+	params []sfParam
+	raw    string // Serialization of the value as received, including its parameters
+}
+
+type sfParser struct {
+	s string
+	i int
+}
+
+// parseDictionary parses the dictionary field value, keeping the order of the members
+func parseDictionary(s string) ([]sfMember, error) {
+	p := &sfParser{s: s}
+	p.skipSP()
+
+	var members []sfMember
+	for p.i < len(p.s) {
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		m := sfMember{key: key, value: true}
+		start := p.i
+		if p.peek() == '=' {
+			p.i++
+			start = p.i
+			if p.peek() == '(' {
+				m.list = true
+				if m.items, err = p.parseInnerList(); err != nil {
+					return nil, err
+				}
+			} else if m.value, err = p.parseBareItem(); err != nil {
+				return nil, err
+			}
+		}
+		if m.params, err = p.parseParams(); err != nil {
+			return nil, err
+		}
+		m.raw = p.s[start:p.i]
+		members = append(members, m)
+
+		p.skipOWS()
+		if p.i == len(p.s) {
+			break
+		}
+		if p.peek() != ',' {
+			return nil, p.errorf("expected comma")
+		}
+		p.i++
+		p.skipOWS()
+		if p.i == len(p.s) {
+			return nil, p.errorf("trailing comma")
+		}
+	}
+
+	return members, nil
+}
+
+// parseInnerList parses the inner list of strings, e.g. ("@method" "content-digest")
+func (p *sfParser) parseInnerList() ([]string, error) {
+	p.i++ // (
+	items := []string{}
+	for {
+		p.skipSP()
+		if p.peek() == ')' {
+			p.i++
+			return items, nil
+		}
+
+		v, err := p.parseBareItem()
+		if err != nil {
+			return nil, err
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, p.errorf("expected string")
+		}
+		if p.peek() == ';' {
+			return nil, fmt.Errorf("%w: %s with parameters", ErrUnsupportedComponent, strconv.Quote(s))
+		}
+		items = append(items, s)
+
+		if c := p.peek(); c != ' ' && c != ')' {
+			return nil, p.errorf("expected space or closing parenthesis")
+		}
+	}
+}
+
+func (p *sfParser) parseParams() ([]sfParam, error) {
+	var params []sfParam
+	for p.peek() == ';' {
+		p.i++
+		p.skipSP()
+
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+
+		var value any = true
+		if p.peek() == '=' {
+			p.i++
+			if value, err = p.parseBareItem(); err != nil {
+				return nil, err
+			}
+		}
+		params = append(params, sfParam{key: key, value: value})
+	}
+
+	return params, nil
+}
+
+func (p *sfParser) parseKey() (string, error) {
+	start := p.i
+	if c := p.peek(); !isLCAlpha(c) && c != '*' {
+		return "", p.errorf("expected key")
+	}
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		if !isLCAlpha(c) && !isDigit(c) && !strings.ContainsRune("_-.*", rune(c)) {
+			break
+		}
+		p.i++
+	}
+
+	return p.s[start:p.i], nil
+}
+
+func (p *sfParser) parseBareItem() (any, error) {
+	switch c := p.peek(); {
+	case c == '"':
+		return p.parseString()
+	case c == ':':
+		return p.parseByteSequence()
+	case c == '?':
+		if p.i+1 < len(p.s) && (p.s[p.i+1] == '0' || p.s[p.i+1] == '1') {
+			p.i += 2
+			return p.s[p.i-1] == '1', nil
+		}
+		return nil, p.errorf("invalid boolean")
+	case c == '-' || isDigit(c):
+		return p.parseInteger()
+	case isAlpha(c) || c == '*':
+		return p.parseToken(), nil
+	}
+
+	return nil, p.errorf("unexpected character")
+}
+
+func (p *sfParser) parseString() (string, error) {
+	p.i++ // "
+	var b strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		p.i++
+		switch {
+		case c == '\\':
+			if p.i == len(p.s) || (p.s[p.i] != '"' && p.s[p.i] != '\\') {
+				return "", p.errorf("invalid escape")
+			}
+			b.WriteByte(p.s[p.i])
+			p.i++
+		case c == '"':
+			return b.String(), nil
+		case c < 0x20 || c > 0x7e:
+			return "", p.errorf("invalid string character")
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *sfParser) parseByteSequence() ([]byte, error) {
+	p.i++ // :
+	end := strings.IndexByte(p.s[p.i:], ':')
+	if end < 0 {
+		return nil, p.errorf("unterminated byte sequence")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(p.s[p.i : p.i+end])
+	if err != nil {
+		return nil, p.errorf("invalid byte sequence")
+	}
+	p.i += end + 1
+
+	return b, nil
+}
+
+func (p *sfParser) parseInteger() (int64, error) {
+	start := p.i
+	if p.peek() == '-' {
+		p.i++
+	}
+	for p.i < len(p.s) && isDigit(p.s[p.i]) {
+		p.i++
+	}
+
+	n, err := strconv.ParseInt(p.s[start:p.i], 10, 64)
+	if err != nil || p.i-start > 16 {
+		return 0, p.errorf("invalid integer")
+	}
+
+	return n, nil
+}
+
+func (p *sfParser) parseToken() sfToken {
+	start := p.i
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		if c <= ' ' || c >= 0x7f || strings.ContainsRune(`"(),;<=>?@[\]{}`, rune(c)) {
+			break
+		}
+		p.i++
+	}
+
+	return sfToken(p.s[start:p.i])
+}
+
+func (p *sfParser) peek() byte {
+	if p.i < len(p.s) {
+		return p.s[p.i]
+	}
+
+	return 0
+}
+
+func (p *sfParser) skipSP() {
+	for p.peek() == ' ' {
+		p.i++
+	}
+}
+
+func (p *sfParser) skipOWS() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.i++
+	}
+}
+
+func (p *sfParser) errorf(msg string) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrMalformedSignature, msg, p.i)
+}
+
+// serializeString serializes the structured field string
+func serializeString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// serializeByteSequence serializes the structured field byte sequence
+func serializeByteSequence(b []byte) string {
+	return ":" + base64.StdEncoding.EncodeToString(b) + ":"
+}
+
+func isLCAlpha(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isAlpha(c byte) bool {
+	return isLCAlpha(c) || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,142 @@
+package httpsig
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDictionary(t *testing.T) {
+	tcs := map[string]struct {
+		given  string
+		exp    []sfMember
+		expErr error
+	}{
+		"signature input": {
+			given: `sig1=("@method" "@target-uri" "content-digest");created=1618884473;keyid="test-key-rsa-pss", sig2=();nonce="b3k2pp5k7z-50gnwp.yemd"`,
+			exp: []sfMember{
+				{
+					key:   "sig1",
+					items: []string{"@method", "@target-uri", "content-digest"},
+					list:  true,
+					value: true,
+					params: []sfParam{
+						{key: "created", value: int64(1618884473)},
+						{key: "keyid", value: "test-key-rsa-pss"},
+					},
+					raw: `("@method" "@target-uri" "content-digest");created=1618884473;keyid="test-key-rsa-pss"`,
+				},
+				{
+					key:    "sig2",
+					items:  []string{},
+					list:   true,
+					value:  true,
+					params: []sfParam{{key: "nonce", value: "b3k2pp5k7z-50gnwp.yemd"}},
+					raw:    `();nonce="b3k2pp5k7z-50gnwp.yemd"`,
+				},
+			},
+		},
+		"signatures & digests": {
+			given: `sig1=:aGVsbG8=:,sha-256=:d29ybGQ=:;alg=hmac, flag`,
+			exp: []sfMember{
+				{key: "sig1", value: []byte("hello"), raw: ":aGVsbG8=:"},
+				{key: "sha-256", value: []byte("world"), params: []sfParam{{key: "alg", value: sfToken("hmac")}}, raw: ":d29ybGQ=:;alg=hmac"},
+				{key: "flag", value: true, raw: ""},
+			},
+		},
+		"escaped string": {
+			given: `sig1=("x-quote");tag="a\"b\\c"`,
+			exp: []sfMember{
+				{
+					key:    "sig1",
+					items:  []string{"x-quote"},
+					list:   true,
+					value:  true,
+					params: []sfParam{{key: "tag", value: `a"b\c`}},
+					raw:    `("x-quote");tag="a\"b\\c"`,
+				},
+			},
+		},
+		"error - component with parameters": {
+			given:  `sig1=("@query-param";name="id")`,
+			expErr: ErrUnsupportedComponent,
+		},
+		"error - unterminated inner list": {
+			given:  `sig1=("@method"`,
+			expErr: ErrMalformedSignature,
+		},
+		"error - trailing comma": {
+			given:  `sig1=:aGVsbG8=:,`,
+			expErr: ErrMalformedSignature,
+		},
+		"error - invalid key": {
+			given:  `Sig1=:aGVsbG8=:`,
+			expErr: ErrMalformedSignature,
+		},
+		"error - invalid byte sequence": {
+			given:  `sig1=:not base64:`,
+			expErr: ErrMalformedSignature,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given & When
+			members, err := parseDictionary(tc.given)
+
+			// Then
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, members)
+		})
+	}
+}
+
+func TestVerifyContentDigest(t *testing.T) {
+	tcs := map[string]struct {
+		givenDigest string
+		givenBody   string
+		expErr      error
+	}{
+		"sha-256": {
+			givenDigest: ContentDigest([]byte(`{"hello": "world"}`)),
+			givenBody:   `{"hello": "world"}`,
+		},
+		"sha-512 preferred": {
+			givenDigest: "sha-256=:AAAA:, sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:",
+			givenBody:   `{"hello": "world"}`,
+		},
+		"error - mismatch": {
+			givenDigest: ContentDigest([]byte(`{"hello": "world"}`)),
+			givenBody:   `{"hello": "mars"}`,
+			expErr:      ErrContentDigestMismatch,
+		},
+		"error - unsupported algorithm": {
+			givenDigest: "md5=:AAAA:",
+			expErr:      ErrUnsupportedDigestAlgorithm,
+		},
+		"error - missing": {
+			expErr: ErrMissingComponent,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			h := http.Header{}
+			if tc.givenDigest != "" {
+				h[HeaderContentDigest] = []string{tc.givenDigest}
+			}
+
+			// When
+			err := verifyContentDigest(h, []byte(tc.givenBody))
+
+			// Then
+			require.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package httpsig
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+
+	"github.com/viebiz/lit/jwt"
+)
+
+const (
+	defaultMaxAge    = 5 * time.Minute
+	defaultClockSkew = 30 * time.Second
+)
+
+// defaultRequiredComponents must be covered by the signatures unless overridden
+var defaultRequiredComponents = []string{ComponentMethod, ComponentTargetURI}
+
+// VerificationKey is the key verifying the signatures of a key id
+type VerificationKey struct {
+	// Algorithm of the signatures made with the key
+	Algorithm Algorithm
+	// Key verifies the signatures, e.g. jwt.HMACPrivateKey, *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey
+	Key jwt.VerifyKey
+}
+
+// KeyResolver returns the key of the key id, or ErrUnknownKey if there is no such key
+type KeyResolver func(ctx context.Context, keyID string) (VerificationKey, error)
+
+// NonceStore remembers the nonces of the verified signatures to detect the replayed requests,
+// e.g. redis.NonceStore
+type NonceStore interface {
+	// Remember stores the nonce for the ttl, returning false if it is already stored
+	Remember(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// VerifierConfig holds the config of the Verifier
+type VerifierConfig struct {
+	// Resolver returns the key of the keyid parameter of the signature
+	Resolver KeyResolver
+	// Label of the signature to verify
+	// Default: the first signature of the Signature-Input field
+	Label string
+	// RequiredComponents must be covered by the signature
+	// Default: @method, @target-uri, and content-digest when the request has a body
+	RequiredComponents []string
+	// MaxAge of the signature from its created parameter
+	// Default: 5m
+	MaxAge time.Duration
+	// ClockSkew tolerated between the clocks of the signer and the verifier
+	// Default: 30s
+	ClockSkew time.Duration
+	// Nonces enables the replay protection, the signatures must then have a nonce parameter
+	Nonces NonceStore
+	// Scheme of the requests as sent by the signer, e.g. https behind a TLS terminating proxy
+	// Default: https if the request is received over TLS, http otherwise
+	Scheme string
+	// Authority of the requests as sent by the signer, e.g. the public host behind a proxy rewriting the Host header
+	// Default: the Host header of the request
+	Authority string
+}
+
+// Verifier verifies the signatures of the requests per RFC 9421
+type Verifier struct {
+	cfg VerifierConfig
+	// bodyDigestRequired requires content-digest to be covered when the request has a body
+	bodyDigestRequired bool
+	now                func() time.Time
+}
+
+// NewVerifier creates the Verifier
+//
+// Example:
+//
+//	verifier, err := httpsig.NewVerifier(httpsig.VerifierConfig{
+//		Resolver: func(ctx context.Context, keyID string) (httpsig.VerificationKey, error) {
+//			key, ok := partnerKeys[keyID]
+//			if !ok {
+//				return httpsig.VerificationKey{}, httpsig.ErrUnknownKey
+//			}
+//			return httpsig.VerificationKey{Algorithm: httpsig.AlgorithmECDSAP256SHA256, Key: key}, nil
+//		},
+//		Nonces: redis.NewNonceStore(redisClient),
+//		// Behind a TLS terminating proxy, the @target-uri as sent by the signer
+//		Scheme:    "https",
+//		Authority: "orders.example.com",
+//	})
+func NewVerifier(cfg VerifierConfig) (Verifier, error) {
+	if cfg.Resolver == nil || cfg.MaxAge < 0 || cfg.ClockSkew < 0 {
+		return Verifier{}, ErrVerifierConfigInvalid
+	}
+	bodyDigestRequired := false
+	if len(cfg.RequiredComponents) == 0 {
+		cfg.RequiredComponents = defaultRequiredComponents
+		bodyDigestRequired = true
+	}
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = defaultMaxAge
+	}
+	if cfg.ClockSkew == 0 {
+		cfg.ClockSkew = defaultClockSkew
+	}
+
+	return Verifier{cfg: cfg, bodyDigestRequired: bodyDigestRequired, now: time.Now}, nil
+}
+
+// Verify verifies the signature of the request with the body, returning the key id of the signature.
+// The verification failures are of type Error
+func (v Verifier) Verify(r *http.Request, body []byte) (string, error) {
+	// 1. Find the signature to verify
+	m, sig, err := v.findSignature(r.Header)
+	if err != nil {
+		return "", err
+	}
+
+	sp, err := parseSignatureParams(m)
+	if err != nil {
+		return "", err
+	}
+	if sp.keyID == "" || sp.created.IsZero() {
+		return "", fmt.Errorf("%w: missing keyid or created parameter", ErrMalformedSignature)
+	}
+
+	// 2. Check the validity period
+	now := v.now()
+	switch {
+	case sp.created.After(now.Add(v.cfg.ClockSkew)):
+		return "", ErrSignatureNotValidYet
+	case sp.created.Add(v.cfg.MaxAge + v.cfg.ClockSkew).Before(now):
+		return "", ErrSignatureExpired
+	case !sp.expires.IsZero() && sp.expires.Add(v.cfg.ClockSkew).Before(now):
+		return "", ErrSignatureExpired
+	}
+
+	// 3. Check the covered components
+	for _, c := range v.cfg.RequiredComponents {
+		if !sp.covers(c) {
+			return "", fmt.Errorf("%w: %s", ErrMissingComponent, c)
+		}
+	}
+	if v.bodyDigestRequired && len(body) > 0 && !sp.covers(componentContentDigest) {
+		return "", fmt.Errorf("%w: %s", ErrMissingComponent, componentContentDigest)
+	}
+	if v.cfg.Nonces != nil && sp.nonce == "" {
+		return "", ErrMissingNonce
+	}
+
+	// 4. Verify the signature with the key
+	key, err := v.cfg.Resolver(r.Context(), sp.keyID)
+	if err != nil {
+		return "", err
+	}
+	if sp.alg != "" && sp.alg != key.Algorithm {
+		return "", ErrAlgorithmMismatch
+	}
+
+	base, err := signatureBase(v.asSent(r), sp.components, m.raw)
+	if err != nil {
+		return "", err
+	}
+	if err := key.Algorithm.verify(base, sig, key.Key); err != nil {
+		return "", err
+	}
+
+	// 5. Verify the body if covered
+	if sp.covers(componentContentDigest) {
+		if err := verifyContentDigest(r.Header, body); err != nil {
+			return "", err
+		}
+	}
+
+	// 6. Reject the replayed requests, once the signature is known to be genuine
+	if v.cfg.Nonces != nil {
+		ok, err := v.cfg.Nonces.Remember(r.Context(), sp.keyID+":"+sp.nonce, v.cfg.MaxAge+2*v.cfg.ClockSkew)
+		if err != nil {
+			return "", pkgerrors.WithStack(err)
+		}
+		if !ok {
+			return "", ErrReplayedRequest
+		}
+	}
+
+	return sp.keyID, nil
+}
+
+// asSent returns the request with the configured scheme & authority, as sent by the signer
+func (v Verifier) asSent(r *http.Request) *http.Request {
+	if v.cfg.Scheme == "" && v.cfg.Authority == "" {
+		return r
+	}
+
+	sent := *r
+	u := *r.URL
+	if v.cfg.Scheme != "" {
+		u.Scheme = v.cfg.Scheme
+	}
+	if v.cfg.Authority != "" {
+		sent.Host = v.cfg.Authority
+	}
+	sent.URL = &u
+
+	return &sent
+}
+
+// findSignature returns the Signature-Input member and the signature of the label to verify
+func (v Verifier) findSignature(h http.Header) (sfMember, []byte, error) {
+	rawInput, rawSig := strings.Join(h.Values(HeaderSignatureInput), ", "), strings.Join(h.Values(HeaderSignature), ", ")
+	if rawInput == "" || rawSig == "" {
+		return sfMember{}, nil, ErrMissingSignature
+	}
+
+	inputs, err := parseDictionary(rawInput)
+	if err != nil {
+		return sfMember{}, nil, err
+	}
+	sigs, err := parseDictionary(rawSig)
+	if err != nil {
+		return sfMember{}, nil, err
+	}
+
+	for _, in := range inputs {
+		if v.cfg.Label != "" && in.key != v.cfg.Label {
+			continue
+		}
+
+		for _, s := range sigs {
+			if s.key != in.key {
+				continue
+			}
+			sig, ok := s.value.([]byte)
+			if !ok {
+				return sfMember{}, nil, fmt.Errorf("%w: signature %s is not a byte sequence", ErrMalformedSignature, s.key)
+			}
+			return in, sig, nil
+		}
+
+		return sfMember{}, nil, fmt.Errorf("%w: no signature of %s", ErrMalformedSignature, in.key)
+	}
+
+	return sfMember{}, nil, ErrMissingSignature
+}
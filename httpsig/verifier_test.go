@@ -0,0 +1,313 @@
+package httpsig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/viebiz/lit/jwt"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	now := time.Date(2024, time.July, 24, 10, 0, 0, 0, time.UTC)
+	secret := jwt.HMACPrivateKey("orders-secret")
+
+	type mockNonce struct {
+		expCall bool
+		outOK   bool
+		outErr  error
+	}
+	tcs := map[string]struct {
+		givenComponents   []string
+		givenCreated      time.Time
+		givenTTL          time.Duration
+		givenDisableNonce bool
+		givenIncludeAlg   bool
+		givenKeyAlg       Algorithm
+		givenResolverErr  error
+		givenTamper       func(r *http.Request)
+		givenBody         string
+		givenScheme       string
+		givenAuthority    string
+		mockNonce         mockNonce
+		expErr            error
+	}{
+		"valid": {
+			givenCreated: now,
+			givenBody:    `{"id":1}`,
+			mockNonce:    mockNonce{expCall: true, outOK: true},
+		},
+		"valid - created ahead within the clock skew": {
+			givenCreated: now.Add(20 * time.Second),
+			givenBody:    `{"id":1}`,
+			mockNonce:    mockNonce{expCall: true, outOK: true},
+		},
+		"valid - expired within the clock skew": {
+			givenCreated: now.Add(-time.Minute),
+			givenTTL:     50 * time.Second,
+			givenBody:    `{"id":1}`,
+			mockNonce:    mockNonce{expCall: true, outOK: true},
+		},
+		"valid - behind a TLS terminating proxy": {
+			givenCreated: now,
+			givenTamper: func(r *http.Request) {
+				r.URL.Scheme, r.URL.Host, r.Host = "", "", "orders-svc:8080"
+			},
+			givenBody:      `{"id":1}`,
+			givenScheme:    "https",
+			givenAuthority: "orders.example.com",
+			mockNonce:      mockNonce{expCall: true, outOK: true},
+		},
+		"error - behind a TLS terminating proxy without the scheme & authority": {
+			givenCreated: now,
+			givenTamper: func(r *http.Request) {
+				r.URL.Scheme, r.URL.Host, r.Host = "", "", "orders-svc:8080"
+			},
+			givenBody: `{"id":1}`,
+			expErr:    ErrInvalidSignature,
+		},
+		"error - not valid yet": {
+			givenCreated: now.Add(time.Minute),
+			expErr:       ErrSignatureNotValidYet,
+		},
+		"error - older than max age": {
+			givenCreated: now.Add(-6 * time.Minute),
+			expErr:       ErrSignatureExpired,
+		},
+		"error - expired": {
+			givenCreated: now.Add(-2 * time.Minute),
+			givenTTL:     time.Minute,
+			expErr:       ErrSignatureExpired,
+		},
+		"error - missing required component": {
+			givenComponents: []string{ComponentMethod, componentContentDigest},
+			givenCreated:    now,
+			expErr:          ErrMissingComponent,
+		},
+		"error - body not covered by content digest": {
+			givenComponents: []string{ComponentMethod, ComponentTargetURI},
+			givenCreated:    now,
+			givenBody:       `{"id":1}`,
+			expErr:          ErrMissingComponent,
+		},
+		"error - missing nonce": {
+			givenCreated:      now,
+			givenDisableNonce: true,
+			expErr:            ErrMissingNonce,
+		},
+		"error - unknown key": {
+			givenCreated:     now,
+			givenResolverErr: ErrUnknownKey,
+			expErr:           ErrUnknownKey,
+		},
+		"error - algorithm mismatch": {
+			givenCreated:    now,
+			givenIncludeAlg: true,
+			givenKeyAlg:     AlgorithmRSAPSSSHA512,
+			expErr:          ErrAlgorithmMismatch,
+		},
+		"error - tampered target uri": {
+			givenCreated: now,
+			givenTamper: func(r *http.Request) {
+				r.URL.Path = "/orders/2"
+			},
+			expErr: ErrInvalidSignature,
+		},
+		"error - tampered signature params": {
+			givenCreated: now,
+			givenTamper: func(r *http.Request) {
+				r.Header.Set(HeaderSignatureInput, strings.Replace(r.Header.Get(HeaderSignatureInput), `keyid="orders"`, `keyid="orders";tag="x"`, 1))
+			},
+			expErr: ErrInvalidSignature,
+		},
+		"error - tampered body": {
+			givenCreated: now,
+			givenBody:    `{"id":2}`,
+			expErr:       ErrContentDigestMismatch,
+		},
+		"error - missing signature": {
+			givenCreated: now,
+			givenTamper: func(r *http.Request) {
+				r.Header.Del(HeaderSignature)
+			},
+			expErr: ErrMissingSignature,
+		},
+		"error - malformed signature": {
+			givenCreated: now,
+			givenTamper: func(r *http.Request) {
+				r.Header.Set(HeaderSignature, "sig1=:not base64:")
+			},
+			expErr: ErrMalformedSignature,
+		},
+		"error - replayed": {
+			givenCreated: now,
+			givenBody:    `{"id":1}`,
+			mockNonce:    mockNonce{expCall: true, outOK: false},
+			expErr:       ErrReplayedRequest,
+		},
+		"error - nonce store failure": {
+			givenCreated: now,
+			givenBody:    `{"id":1}`,
+			mockNonce:    mockNonce{expCall: true, outErr: errors.New("connection refused")},
+			expErr:       errors.New("connection refused"),
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			signer, err := NewSigner(SignerConfig{
+				KeyID:            "orders",
+				Key:              secret,
+				Algorithm:        AlgorithmHMACSHA256,
+				Components:       tc.givenComponents,
+				TTL:              tc.givenTTL,
+				DisableNonce:     tc.givenDisableNonce,
+				IncludeAlgorithm: tc.givenIncludeAlg,
+			})
+			require.NoError(t, err)
+			signer.now = func() time.Time { return tc.givenCreated }
+
+			r, err := http.NewRequest(http.MethodPost, "https://orders.example.com/orders/1?expand=items", nil)
+			require.NoError(t, err)
+			require.NoError(t, signer.Sign(r, []byte(`{"id":1}`)))
+			if tc.givenTamper != nil {
+				tc.givenTamper(r)
+			}
+
+			keyAlg := AlgorithmHMACSHA256
+			if tc.givenKeyAlg != "" {
+				keyAlg = tc.givenKeyAlg
+			}
+			nonces := NewMockNonceStore(t)
+			if tc.mockNonce.expCall {
+				nonces.EXPECT().
+					Remember(mock.Anything, mock.MatchedBy(func(nonce string) bool { return strings.HasPrefix(nonce, "orders:") }), 6*time.Minute).
+					Return(tc.mockNonce.outOK, tc.mockNonce.outErr)
+			}
+			v, err := NewVerifier(VerifierConfig{
+				Resolver: func(ctx context.Context, keyID string) (VerificationKey, error) {
+					require.Equal(t, "orders", keyID)
+					return VerificationKey{Algorithm: keyAlg, Key: secret}, tc.givenResolverErr
+				},
+				Nonces:    nonces,
+				Scheme:    tc.givenScheme,
+				Authority: tc.givenAuthority,
+			})
+			require.NoError(t, err)
+			v.now = func() time.Time { return now }
+
+			// When
+			keyID, err := v.Verify(r, []byte(tc.givenBody))
+
+			// Then
+			if tc.expErr != nil {
+				var sigErr Error
+				if errors.As(tc.expErr, &sigErr) {
+					require.ErrorIs(t, err, tc.expErr)
+				} else {
+					require.EqualError(t, err, tc.expErr.Error())
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "orders", keyID)
+		})
+	}
+}
+
+func TestAlgorithm_SignVerify(t *testing.T) {
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		givenAlg       Algorithm
+		givenKey       jwt.Signer
+		givenVerifyKey jwt.VerifyKey
+		expVerifyErr   error
+	}{
+		"hmac-sha256": {
+			givenAlg:       AlgorithmHMACSHA256,
+			givenKey:       jwt.HMACPrivateKey("secret"),
+			givenVerifyKey: jwt.HMACPrivateKey("secret"),
+		},
+		"rsa-v1_5-sha256": {
+			givenAlg:       AlgorithmRSAV15SHA256,
+			givenKey:       rsaKey,
+			givenVerifyKey: &rsaKey.PublicKey,
+		},
+		"rsa-pss-sha512": {
+			givenAlg:       AlgorithmRSAPSSSHA512,
+			givenKey:       rsaKey,
+			givenVerifyKey: &rsaKey.PublicKey,
+		},
+		"ecdsa-p256-sha256": {
+			givenAlg:       AlgorithmECDSAP256SHA256,
+			givenKey:       p256Key,
+			givenVerifyKey: &p256Key.PublicKey,
+		},
+		"ecdsa-p384-sha384": {
+			givenAlg:       AlgorithmECDSAP384SHA384,
+			givenKey:       p384Key,
+			givenVerifyKey: &p384Key.PublicKey,
+		},
+		"ed25519": {
+			givenAlg:       AlgorithmEd25519,
+			givenKey:       edKey,
+			givenVerifyKey: edPub,
+		},
+		"error - wrong hmac secret": {
+			givenAlg:       AlgorithmHMACSHA256,
+			givenKey:       jwt.HMACPrivateKey("secret"),
+			givenVerifyKey: jwt.HMACPrivateKey("another"),
+			expVerifyErr:   ErrInvalidSignature,
+		},
+		"error - public key of another curve": {
+			givenAlg:       AlgorithmECDSAP256SHA256,
+			givenKey:       p256Key,
+			givenVerifyKey: &p384Key.PublicKey,
+			expVerifyErr:   ErrInvalidKeyType,
+		},
+		"error - public key of another type": {
+			givenAlg:       AlgorithmRSAPSSSHA512,
+			givenKey:       rsaKey,
+			givenVerifyKey: &p256Key.PublicKey,
+			expVerifyErr:   ErrInvalidKeyType,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			base := []byte(`"@method": POST` + "\n" + `"@signature-params": ("@method");created=1618884473`)
+			sig, err := tc.givenAlg.sign(base, tc.givenKey)
+			require.NoError(t, err)
+
+			// When
+			err = tc.givenAlg.verify(base, sig, tc.givenVerifyKey)
+
+			// Then
+			require.ErrorIs(t, err, tc.expVerifyErr)
+			if tc.expVerifyErr == nil {
+				require.ErrorIs(t, tc.givenAlg.verify(append(base, ' '), sig, tc.givenVerifyKey), ErrInvalidSignature)
+			}
+		})
+	}
+}
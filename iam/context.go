@@ -9,6 +9,7 @@ type contextkey string
 const (
 	contextKeyM2MProfile  = contextkey("m2m-profile")
 	contextKeyUserProfile = contextkey("user-profile")
+	contextKeyAccessToken = contextkey("access-token")
 )
 
 func SetM2MProfileInContext(ctx context.Context, profile M2MProfile) context.Context {
@@ -34,3 +35,16 @@ func GetUserProfileFromContext(ctx context.Context) UserProfile {
 
 	return UserProfile{}
 }
+
+// SetAccessTokenInContext keeps the raw access token of the caller, e.g. to forward it to downstream services
+func SetAccessTokenInContext(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, contextKeyAccessToken, token)
+}
+
+func GetAccessTokenFromContext(ctx context.Context) string {
+	if t, ok := ctx.Value(contextKeyAccessToken).(string); ok {
+		return t
+	}
+
+	return ""
+}
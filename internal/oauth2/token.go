@@ -0,0 +1,114 @@
+// Package oauth2 requests the access tokens of the OAuth2 client credentials grant, shared by httpclient & grpcclient
+// Refer https://datatracker.ietf.org/doc/html/rfc6749#section-4.4
+package oauth2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+const (
+	bearerPrefix = "Bearer"
+
+	maxTokenResponseSize = 1 << 20
+
+	// DefaultTokenLifetime is the lifetime assumed for the tokens without expires_in
+	DefaultTokenLifetime = 5 * time.Minute
+)
+
+// ErrInvalidTokenResponse is returned when the token endpoint does not return a bearer access token
+var ErrInvalidTokenResponse = errors.New("invalid token response")
+
+// TokenRequest is the client credentials token request
+type TokenRequest struct {
+	TokenURL string
+	Scopes   []string
+	// Audience is sent as audience parameter when it is not empty, as required by some providers e.g. Auth0
+	Audience string
+	// Authenticate sets the client authentication to the token request form or request
+	Authenticate func(form url.Values, r *http.Request) error
+}
+
+// BasicAuth authenticates the token request with the client id & secret as HTTP basic auth
+// Refer https://datatracker.ietf.org/doc/html/rfc6749#section-2.3.1
+func BasicAuth(clientID, clientSecret string) func(url.Values, *http.Request) error {
+	return func(_ url.Values, r *http.Request) error {
+		r.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+		return nil
+	}
+}
+
+// RequestToken requests the bearer access token from the token endpoint, returning the token and its lifetime
+// The lifetime is 0 when the token endpoint does not return expires_in
+func RequestToken(ctx context.Context, client *http.Client, tr TokenRequest) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(tr.Scopes) > 0 {
+		form.Set("scope", strings.Join(tr.Scopes, " "))
+	}
+	if tr.Audience != "" {
+		form.Set("audience", tr.Audience)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tr.TokenURL, nil)
+	if err != nil {
+		return "", 0, pkgerrors.WithStack(err)
+	}
+	if tr.Authenticate != nil {
+		if err := tr.Authenticate(form, req); err != nil {
+			return "", 0, err
+		}
+	}
+	body := form.Encode() // Encoded after the authentication, which may add the client assertion to the form
+	req.Body, req.ContentLength = io.NopCloser(strings.NewReader(body)), int64(len(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, pkgerrors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenResponseSize))
+	if err != nil {
+		return "", 0, pkgerrors.WithStack(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, pkgerrors.WithStack(fmt.Errorf("%w: status %d, body %s", ErrInvalidTokenResponse, resp.StatusCode, respBody))
+	}
+
+	var rs struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(respBody, &rs); err != nil {
+		return "", 0, pkgerrors.WithStack(fmt.Errorf("%w: %s", ErrInvalidTokenResponse, err))
+	}
+	if rs.AccessToken == "" || (rs.TokenType != "" && !strings.EqualFold(rs.TokenType, bearerPrefix)) {
+		return "", 0, pkgerrors.WithStack(fmt.Errorf("%w: missing bearer access token", ErrInvalidTokenResponse))
+	}
+
+	return rs.AccessToken, time.Duration(rs.ExpiresIn) * time.Second, nil
+}
+
+// ReuseDuration returns how long the token of the lifetime is reused before it is refreshed. The tokens without
+// lifetime are given DefaultTokenLifetime, and the refresh margin is clamped to half of the lifetime, so that the
+// short-lived tokens are still reused instead of being requested for every call
+func ReuseDuration(lifetime, refreshBefore time.Duration) time.Duration {
+	if lifetime <= 0 {
+		lifetime = DefaultTokenLifetime
+	}
+
+	return lifetime - min(max(refreshBefore, 0), lifetime/2)
+}
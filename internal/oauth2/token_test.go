@@ -0,0 +1,127 @@
+package oauth2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestToken(t *testing.T) {
+	tcs := map[string]struct {
+		givenStatus    int
+		givenBody      string
+		expToken       string
+		expLifetime    time.Duration
+		expErr         error
+		expErrContains string
+	}{
+		"success": {
+			givenStatus: http.StatusOK,
+			givenBody:   `{"access_token":"tk-1","token_type":"bearer","expires_in":3600}`,
+			expToken:    "tk-1",
+			expLifetime: time.Hour,
+		},
+		"without expiry": {
+			givenStatus: http.StatusOK,
+			givenBody:   `{"access_token":"tk-1"}`,
+			expToken:    "tk-1",
+		},
+		"error status": {
+			givenStatus:    http.StatusBadRequest,
+			givenBody:      `{"error":"invalid_client"}`,
+			expErr:         ErrInvalidTokenResponse,
+			expErrContains: "status 400",
+		},
+		"invalid json": {
+			givenStatus:    http.StatusOK,
+			givenBody:      `not json`,
+			expErr:         ErrInvalidTokenResponse,
+			expErrContains: "invalid character",
+		},
+		"not a bearer token": {
+			givenStatus:    http.StatusOK,
+			givenBody:      `{"access_token":"tk-1","token_type":"mac"}`,
+			expErr:         ErrInvalidTokenResponse,
+			expErrContains: "missing bearer access token",
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+				require.Equal(t, "orders:read orders:write", r.PostForm.Get("scope"))
+				require.Equal(t, "https://api.example.com", r.PostForm.Get("audience"))
+				id, secret, ok := r.BasicAuth()
+				require.True(t, ok)
+				require.Equal(t, "order-svc", id)
+				require.Equal(t, "s3cr3t", secret)
+
+				w.WriteHeader(tc.givenStatus)
+				w.Write([]byte(tc.givenBody))
+			}))
+			defer srv.Close()
+
+			// When
+			tk, lifetime, err := RequestToken(context.Background(), srv.Client(), TokenRequest{
+				TokenURL:     srv.URL,
+				Scopes:       []string{"orders:read", "orders:write"},
+				Audience:     "https://api.example.com",
+				Authenticate: BasicAuth("order-svc", "s3cr3t"),
+			})
+
+			// Then
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				require.ErrorContains(t, err, tc.expErrContains)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expToken, tk)
+			require.Equal(t, tc.expLifetime, lifetime)
+		})
+	}
+}
+
+func TestReuseDuration(t *testing.T) {
+	tcs := map[string]struct {
+		givenLifetime      time.Duration
+		givenRefreshBefore time.Duration
+		exp                time.Duration
+	}{
+		"refreshed before expiry": {
+			givenLifetime:      time.Hour,
+			givenRefreshBefore: time.Minute,
+			exp:                59 * time.Minute,
+		},
+		"without lifetime": {
+			givenRefreshBefore: time.Minute,
+			exp:                4 * time.Minute,
+		},
+		"lifetime shorter than the refresh margin": {
+			givenLifetime:      30 * time.Second,
+			givenRefreshBefore: time.Minute,
+			exp:                15 * time.Second,
+		},
+		"lifetime equal to the refresh margin": {
+			givenLifetime:      time.Minute,
+			givenRefreshBefore: time.Minute,
+			exp:                30 * time.Second,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given & When & Then
+			require.Equal(t, tc.exp, ReuseDuration(tc.givenLifetime, tc.givenRefreshBefore))
+		})
+	}
+}
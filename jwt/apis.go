@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"crypto"
+	"crypto/elliptic"
 )
 
 func NewHS256() HMAC {
@@ -49,6 +50,64 @@ func NewRS512() RSA {
 	}
 }
 
+// NewPS256 creates a new PS256 signing method struct
+func NewPS256() RSAPSS {
+	return RSAPSS{
+		Name: SigningMethodNamePS256,
+		Hash: crypto.SHA256,
+	}
+}
+
+// NewPS384 creates a new PS384 signing method struct
+func NewPS384() RSAPSS {
+	return RSAPSS{
+		Name: SigningMethodNamePS384,
+		Hash: crypto.SHA384,
+	}
+}
+
+// NewPS512 creates a new PS512 signing method struct
+func NewPS512() RSAPSS {
+	return RSAPSS{
+		Name: SigningMethodNamePS512,
+		Hash: crypto.SHA512,
+	}
+}
+
+// NewES256 creates a new ES256 signing method struct
+func NewES256() ECDSA {
+	return ECDSA{
+		Name:  SigningMethodNameES256,
+		Hash:  crypto.SHA256,
+		Curve: elliptic.P256(),
+	}
+}
+
+// NewES384 creates a new ES384 signing method struct
+func NewES384() ECDSA {
+	return ECDSA{
+		Name:  SigningMethodNameES384,
+		Hash:  crypto.SHA384,
+		Curve: elliptic.P384(),
+	}
+}
+
+// NewES512 creates a new ES512 signing method struct
+func NewES512() ECDSA {
+	return ECDSA{
+		Name:  SigningMethodNameES512,
+		Hash:  crypto.SHA512,
+		Curve: elliptic.P521(),
+	}
+}
+
+// NewEdDSA creates a new EdDSA signing method struct
+func NewEdDSA() EdDSA {
+	return EdDSA{
+		Name: SigningMethodNameEdDSA,
+	}
+}
+
 // NewParser creates a new Parser with the default signing methods and validator.
 func NewParser[T Claims](opts ...ParserOptions) Parser[T] {
 	p := NewDefaultParser[T]()
@@ -68,6 +127,13 @@ func NewDefaultParser[T Claims]() Parser[T] {
 			SigningMethodNameHS256: NewHS256(),
 			SigningMethodNameHS384: NewHS384(),
 			SigningMethodNameHS512: NewHS512(),
+			SigningMethodNameES256: NewES256(),
+			SigningMethodNameES384: NewES384(),
+			SigningMethodNameES512: NewES512(),
+			SigningMethodNamePS256: NewPS256(),
+			SigningMethodNamePS384: NewPS384(),
+			SigningMethodNamePS512: NewPS512(),
+			SigningMethodNameEdDSA: NewEdDSA(),
 		},
 	}
 }
@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type signingMethodForBench struct {
+	method    SigningMethod
+	key       Signer
+	verifyKey VerifyKey
+}
+
+func signingMethodsForBench(b *testing.B) map[string]signingMethodForBench {
+	rsaKey := readKeyForTest[*rsa.PrivateKey](b, "testdata/sample_rsa_private_key")
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(b, err)
+	edKey := readEd25519KeyForTest(b)
+	hmacKey := HMACPrivateKey("muryōkūsho")
+
+	return map[string]signingMethodForBench{
+		"HS256": {method: NewHS256(), key: hmacKey, verifyKey: hmacKey},
+		"RS256": {method: NewRS256(), key: rsaKey, verifyKey: rsaKey.Public()},
+		"PS256": {method: NewPS256(), key: rsaKey, verifyKey: rsaKey.Public()},
+		"ES256": {method: NewES256(), key: ecKey, verifyKey: ecKey.Public()},
+		"EdDSA": {method: NewEdDSA(), key: edKey, verifyKey: edKey.Public()},
+	}
+}
+
+func BenchmarkSigningMethod_Sign(b *testing.B) {
+	signingString := []byte("developers-prefer-dark-mode-because-light-attracts-bugs")
+
+	for alg, sm := range signingMethodsForBench(b) {
+		b.Run(alg, func(b *testing.B) {
+			b.ResetTimer()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = sm.method.Sign(signingString, sm.key)
+			}
+		})
+	}
+}
+
+func BenchmarkSigningMethod_Verify(b *testing.B) {
+	signingString := []byte("developers-prefer-dark-mode-because-light-attracts-bugs")
+
+	for alg, sm := range signingMethodsForBench(b) {
+		sig, err := sm.method.Sign(signingString, sm.key)
+		require.NoError(b, err)
+
+		b.Run(alg, func(b *testing.B) {
+			b.ResetTimer()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = sm.method.Verify(signingString, sig, sm.verifyKey)
+			}
+		})
+	}
+}
@@ -1,6 +1,6 @@
 // Package jwt provided method for signing and verify JWT
 //
-// Supported signing methods: RSA, HMAC, ECDSA
+// Supported signing methods: RSA, RSA-PSS, HMAC, ECDSA, EdDSA
 //
 // Usage example:
 //
@@ -0,0 +1,107 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/asn1"
+	"math/big"
+)
+
+const (
+	SigningMethodNameES256 string = "ES256"
+	SigningMethodNameES384 string = "ES384"
+	SigningMethodNameES512 string = "ES512"
+)
+
+// ECDSA implements the ECDSA family of signing methods, the signature is the concatenation of R and S as of RFC 7518
+type ECDSA struct {
+	Name  string
+	Hash  crypto.Hash
+	Curve elliptic.Curve
+}
+
+// Sign implements token signing for the SigningMethod, that take crypto.Signer with *ecdsa.PublicKey (e.g.
+// *ecdsa.PrivateKey or a HSM/KMS backed key) for sign a token. The key curve must match the one of the method
+func (sm ECDSA) Sign(signingString []byte, key Signer) ([]byte, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrInvalidKeyType
+	}
+	publicKey, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return nil, ErrInvalidKeyType
+	}
+	if publicKey.Curve != sm.Curve {
+		return nil, ErrInvalidKeyCurve
+	}
+
+	if !sm.Hash.Available() {
+		return nil, ErrHashUnavailable
+	}
+
+	hash := sm.Hash.New()
+	hash.Write(signingString)
+
+	// crypto.Signer returns the ASN.1 DER encoded signature, which is converted to R || S
+	der, err := signer.Sign(rand.Reader, hash.Sum(nil), sm.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(der, &parsed); err != nil || len(rest) > 0 {
+		return nil, ErrInvalidSignature
+	}
+
+	size := sm.keySize()
+	if (parsed.R.BitLen()+7)/8 > size || (parsed.S.BitLen()+7)/8 > size {
+		return nil, ErrInvalidSignature
+	}
+
+	sig := make([]byte, 2*size)
+	parsed.R.FillBytes(sig[:size])
+	parsed.S.FillBytes(sig[size:])
+
+	return sig, nil
+}
+
+// Verify verifies the signingString with signature by provided VerifyKey, that can consider as *ecdsa.PublicKey
+func (sm ECDSA) Verify(signingString []byte, sig []byte, key VerifyKey) error {
+	publicKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return ErrInvalidKeyType
+	}
+	if publicKey.Curve != sm.Curve {
+		return ErrInvalidKeyCurve
+	}
+
+	if !sm.Hash.Available() {
+		return ErrHashUnavailable
+	}
+
+	size := sm.keySize()
+	if len(sig) != 2*size {
+		return ErrInvalidSignature
+	}
+
+	hash := sm.Hash.New()
+	hash.Write(signingString)
+
+	r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+	if !ecdsa.Verify(publicKey, hash.Sum(nil), r, s) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func (sm ECDSA) Alg() string {
+	return sm.Name
+}
+
+// keySize returns the size in bytes of R and S
+func (sm ECDSA) keySize() int {
+	return (sm.Curve.Params().BitSize + 7) / 8
+}
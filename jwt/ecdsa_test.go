@@ -0,0 +1,223 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// kmsSigner is a crypto.Signer not exposing the private key, as of the HSM/KMS backed keys
+type kmsSigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s kmsSigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s kmsSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.key.Sign(rand, digest, opts)
+}
+
+func TestSigningMethodECDSA_Sign(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	p521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		method  ECDSA
+		key     Signer
+		expSize int
+		expErr  error
+	}{
+		"success - ES256": {
+			method:  NewES256(),
+			key:     p256,
+			expSize: 64,
+		},
+		"success - ES384": {
+			method:  NewES384(),
+			key:     p384,
+			expSize: 96,
+		},
+		"success - ES512": {
+			method:  NewES512(),
+			key:     p521,
+			expSize: 132,
+		},
+		"success - crypto.Signer": {
+			method:  NewES256(),
+			key:     kmsSigner{key: p256},
+			expSize: 64,
+		},
+		"error - key curve not match": {
+			method: NewES256(),
+			key:    p384,
+			expErr: ErrInvalidKeyCurve,
+		},
+		"error - key type not supported": {
+			method: NewES256(),
+			key:    &rsa.PrivateKey{},
+			expErr: ErrInvalidKeyType,
+		},
+		"error - key without public key": {
+			method: NewES256(),
+			key:    HMACPrivateKey("muryōkūsho"),
+			expErr: ErrInvalidKeyType,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			signingString := []byte("developers-prefer-dark-mode-because-light-attracts-bugs")
+
+			// When
+			sig, err := tc.method.Sign(signingString, tc.key)
+
+			// Then
+			if tc.expErr != nil {
+				require.Equal(t, tc.expErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, sig, tc.expSize)
+
+			hash := tc.method.Hash.New()
+			hash.Write(signingString)
+			publicKey := tc.key.(crypto.Signer).Public().(*ecdsa.PublicKey)
+			size := tc.expSize / 2
+			r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+			require.True(t, ecdsa.Verify(publicKey, hash.Sum(nil), r, s))
+		})
+	}
+}
+
+func TestSigningMethodECDSA_Verify(t *testing.T) {
+	// Example of RFC 7515 appendix A.3
+	rfcKey := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     decodeBigIntForTest(t, "f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU"),
+		Y:     decodeBigIntForTest(t, "x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"),
+	}
+	rfcSigningString := "eyJhbGciOiJFUzI1NiJ9.eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ"
+	rfcSignature := "DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q"
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		method        ECDSA
+		signingString string
+		signature     string
+		key           VerifyKey
+		expErr        error
+	}{
+		"success - ES256": {
+			method:        NewES256(),
+			key:           rfcKey,
+			signingString: rfcSigningString,
+			signature:     rfcSignature,
+		},
+		"error - tampered signing string": {
+			method:        NewES256(),
+			key:           rfcKey,
+			signingString: rfcSigningString + "x",
+			signature:     rfcSignature,
+			expErr:        ErrInvalidSignature,
+		},
+		"error - ASN.1 signature": {
+			method:        NewES256(),
+			key:           rfcKey,
+			signingString: rfcSigningString,
+			signature:     "MEUCIQDkHajo3t-8YgM2Iv1b97y1shKfdCrPJyR3YxdgGS2ejgIgDAhDhjKFN3XORy5W5ImmgJLpINTWMO3_P4sb2TYeOUM",
+			expErr:        ErrInvalidSignature,
+		},
+		"error - key curve not match": {
+			method:        NewES256(),
+			key:           &p384.PublicKey,
+			signingString: rfcSigningString,
+			signature:     rfcSignature,
+			expErr:        ErrInvalidKeyCurve,
+		},
+		"error - invalid key type": {
+			method:        NewES256(),
+			key:           &rsa.PublicKey{},
+			signingString: rfcSigningString,
+			signature:     rfcSignature,
+			expErr:        ErrInvalidKeyType,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			// Given
+			method := tc.method
+
+			// When
+			sig, err := base64.RawURLEncoding.DecodeString(tc.signature)
+			require.NoError(t, err)
+
+			actualErr := method.Verify([]byte(tc.signingString), sig, tc.key)
+
+			// Then
+			if tc.expErr != nil {
+				require.Equal(t, tc.expErr, actualErr)
+			} else {
+				require.NoError(t, actualErr)
+			}
+		})
+	}
+}
+
+func TestParser_Parse_ECDSA(t *testing.T) {
+	tcs := map[string]struct {
+		method SigningMethod
+		curve  elliptic.Curve
+	}{
+		"ES256": {method: NewES256(), curve: elliptic.P256()},
+		"ES384": {method: NewES384(), curve: elliptic.P384()},
+		"ES512": {method: NewES512(), curve: elliptic.P521()},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			key, err := ecdsa.GenerateKey(tc.curve, rand.Reader)
+			require.NoError(t, err)
+			exp := time.Now().Add(time.Hour).Unix()
+			claims := RegisteredClaims{Issuer: "https://limitless.mukagen.com", ExpiresAt: &exp}
+			tokenString, err := NewToken(tc.method, claims).SignedString(key)
+			require.NoError(t, err)
+
+			// When
+			tk, err := NewParser[RegisteredClaims]().Parse(tokenString, func(string) (crypto.PublicKey, error) {
+				return key.Public(), nil
+			})
+
+			// Then
+			require.NoError(t, err)
+			require.Equal(t, scenario, tk.Header["alg"])
+			require.Equal(t, "https://limitless.mukagen.com", tk.Claims.Issuer)
+		})
+	}
+}
+
+func decodeBigIntForTest(t *testing.T, s string) *big.Int {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	require.NoError(t, err)
+
+	return new(big.Int).SetBytes(b)
+}
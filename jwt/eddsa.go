@@ -0,0 +1,49 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+)
+
+const (
+	SigningMethodNameEdDSA string = "EdDSA"
+)
+
+// EdDSA implements the EdDSA signing method with Ed25519 keys as of RFC 8037
+type EdDSA struct {
+	Name string
+}
+
+// Sign implements token signing for the SigningMethod, that take crypto.Signer with ed25519.PublicKey (e.g.
+// ed25519.PrivateKey or a HSM/KMS backed key) for sign a token
+func (sm EdDSA) Sign(signingString []byte, key Signer) ([]byte, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrInvalidKeyType
+	}
+	if _, ok := signer.Public().(ed25519.PublicKey); !ok {
+		return nil, ErrInvalidKeyType
+	}
+
+	// Ed25519 signs the message itself instead of its digest
+	return signer.Sign(rand.Reader, signingString, crypto.Hash(0))
+}
+
+// Verify verifies the signingString with signature by provided VerifyKey, that can consider as ed25519.PublicKey
+func (sm EdDSA) Verify(signingString []byte, sig []byte, key VerifyKey) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok || len(publicKey) != ed25519.PublicKeySize {
+		return ErrInvalidKeyType
+	}
+
+	if !ed25519.Verify(publicKey, signingString, sig) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func (sm EdDSA) Alg() string {
+	return sm.Name
+}
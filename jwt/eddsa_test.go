@@ -0,0 +1,132 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Example of RFC 8037 appendix A.4
+const (
+	sampleEd25519Seed          = "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A"
+	sampleEd25519SigningString = "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc"
+	sampleEd25519Signature     = "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+)
+
+func readEd25519KeyForTest(t require.TestingT) ed25519.PrivateKey {
+	seed, err := base64.RawURLEncoding.DecodeString(sampleEd25519Seed)
+	require.NoError(t, err)
+
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+func TestSigningMethodEdDSA_Sign(t *testing.T) {
+	key := readEd25519KeyForTest(t)
+
+	tcs := map[string]struct {
+		givenString string
+		key         Signer
+		expResult   string
+		expErr      error
+	}{
+		"success": {
+			key:         key,
+			givenString: sampleEd25519SigningString,
+			expResult:   sampleEd25519Signature,
+		},
+		"error - key type not supported": {
+			key:    &ecdsa.PrivateKey{},
+			expErr: ErrInvalidKeyType,
+		},
+		"error - key without public key": {
+			key:    HMACPrivateKey("muryōkūsho"),
+			expErr: ErrInvalidKeyType,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			method := NewEdDSA()
+
+			// When
+			actualResult, err := method.Sign([]byte(tc.givenString), tc.key)
+
+			// Then
+			if tc.expErr != nil {
+				require.Equal(t, tc.expErr, err)
+			} else {
+				require.NoError(t, err)
+
+				expResult, err := base64.RawURLEncoding.DecodeString(tc.expResult)
+				require.NoError(t, err)
+				require.Equal(t, expResult, actualResult)
+			}
+		})
+	}
+}
+
+func TestSigningMethodEdDSA_Verify(t *testing.T) {
+	key := readEd25519KeyForTest(t)
+
+	tcs := map[string]struct {
+		signingString string
+		signature     string
+		key           VerifyKey
+		expErr        error
+	}{
+		"success": {
+			key:           key.Public(),
+			signingString: sampleEd25519SigningString,
+			signature:     sampleEd25519Signature,
+		},
+		"error - tampered signing string": {
+			key:           key.Public(),
+			signingString: sampleEd25519SigningString + "x",
+			signature:     sampleEd25519Signature,
+			expErr:        ErrInvalidSignature,
+		},
+		"error - invalid key type": {
+			key:           &rsa.PublicKey{},
+			signingString: sampleEd25519SigningString,
+			signature:     sampleEd25519Signature,
+			expErr:        ErrInvalidKeyType,
+		},
+		"error - private key": {
+			key:           key,
+			signingString: sampleEd25519SigningString,
+			signature:     sampleEd25519Signature,
+			expErr:        ErrInvalidKeyType,
+		},
+		"error - invalid key size": {
+			key:           ed25519.PublicKey("short"),
+			signingString: sampleEd25519SigningString,
+			signature:     sampleEd25519Signature,
+			expErr:        ErrInvalidKeyType,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			// Given
+			method := NewEdDSA()
+
+			// When
+			sig, err := base64.RawURLEncoding.DecodeString(tc.signature)
+			require.NoError(t, err)
+
+			actualErr := method.Verify([]byte(tc.signingString), sig, tc.key)
+
+			// Then
+			if tc.expErr != nil {
+				require.Equal(t, tc.expErr, actualErr)
+			} else {
+				require.NoError(t, actualErr)
+			}
+		})
+	}
+}
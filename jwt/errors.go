@@ -7,6 +7,8 @@ import (
 var (
 	ErrInvalidKeyType = errors.New("invalid key type")
 
+	ErrInvalidKeyCurve = errors.New("key curve does not match signing method")
+
 	ErrHashUnavailable = errors.New("unavailable hash function")
 
 	ErrTokenMalformed = errors.New("malformed token")
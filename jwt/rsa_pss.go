@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+)
+
+const (
+	SigningMethodNamePS256 string = "PS256"
+	SigningMethodNamePS384 string = "PS384"
+	SigningMethodNamePS512 string = "PS512"
+)
+
+// RSAPSS implements the RSASSA-PSS family of signing methods, the salt length equals the hash size as of RFC 7518
+type RSAPSS struct {
+	Name string
+	Hash crypto.Hash
+}
+
+// Sign implements token signing for the SigningMethod, that take crypto.Signer with *rsa.PublicKey (e.g.
+// *rsa.PrivateKey or a HSM/KMS backed key) for sign a token
+func (sm RSAPSS) Sign(signingString []byte, key Signer) ([]byte, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrInvalidKeyType
+	}
+	if _, ok := signer.Public().(*rsa.PublicKey); !ok {
+		return nil, ErrInvalidKeyType
+	}
+
+	if !sm.Hash.Available() {
+		return nil, ErrHashUnavailable
+	}
+
+	hash := sm.Hash.New()
+	hash.Write(signingString)
+
+	return signer.Sign(rand.Reader, hash.Sum(nil), sm.pssOptions())
+}
+
+// Verify verifies the signingString with signature by provided VerifyKey, that can consider as *rsa.PublicKey
+func (sm RSAPSS) Verify(signingString []byte, sig []byte, key VerifyKey) error {
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return ErrInvalidKeyType
+	}
+
+	if !sm.Hash.Available() {
+		return ErrHashUnavailable
+	}
+
+	hash := sm.Hash.New()
+	hash.Write(signingString)
+
+	if err := rsa.VerifyPSS(publicKey, sm.Hash, hash.Sum(nil), sig, sm.pssOptions()); err != nil {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func (sm RSAPSS) Alg() string {
+	return sm.Name
+}
+
+func (sm RSAPSS) pssOptions() *rsa.PSSOptions {
+	return &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthEqualsHash,
+		Hash:       sm.Hash,
+	}
+}
@@ -0,0 +1,157 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningMethodRSAPSS_Sign(t *testing.T) {
+	privateKeyPath := "testdata/sample_rsa_private_key"
+	key := readKeyForTest[*rsa.PrivateKey](t, privateKeyPath)
+
+	tcs := map[string]struct {
+		method RSAPSS
+		key    Signer
+		expErr error
+	}{
+		"success - PS256": {
+			method: NewPS256(),
+			key:    key,
+		},
+		"success - PS384": {
+			method: NewPS384(),
+			key:    key,
+		},
+		"success - PS512": {
+			method: NewPS512(),
+			key:    key,
+		},
+		"error - key type not supported": {
+			method: NewPS256(),
+			key:    &ecdsa.PrivateKey{},
+			expErr: ErrInvalidKeyType,
+		},
+		"error - key without public key": {
+			method: NewPS256(),
+			key:    HMACPrivateKey("muryōkūsho"),
+			expErr: ErrInvalidKeyType,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			signingString := []byte("developers-prefer-dark-mode-because-light-attracts-bugs")
+
+			// When
+			sig, err := tc.method.Sign(signingString, tc.key)
+
+			// Then
+			if tc.expErr != nil {
+				require.Equal(t, tc.expErr, err)
+				return
+			}
+			require.NoError(t, err)
+
+			hash := tc.method.Hash.New()
+			hash.Write(signingString)
+			require.NoError(t, rsa.VerifyPSS(&key.PublicKey, tc.method.Hash, hash.Sum(nil), sig, &rsa.PSSOptions{
+				SaltLength: tc.method.Hash.Size(), // Salt length must equal the hash size
+				Hash:       tc.method.Hash,
+			}))
+		})
+	}
+}
+
+func TestSigningMethodRSAPSS_Verify(t *testing.T) {
+	privateKeyPath := "testdata/sample_rsa_private_key"
+	key := readKeyForTest[*rsa.PrivateKey](t, privateKeyPath)
+
+	signingString := []byte("developers-prefer-dark-mode-because-light-attracts-bugs")
+	signPSS := func(hash crypto.Hash, saltLength int) []byte {
+		h := hash.New()
+		h.Write(signingString)
+		sig, err := key.Sign(rand.Reader, h.Sum(nil), &rsa.PSSOptions{SaltLength: saltLength, Hash: hash})
+		require.NoError(t, err)
+		return sig
+	}
+	pkcs1v15Sig, err := NewRS256().Sign(signingString, key)
+	require.NoError(t, err)
+
+	tcs := map[string]struct {
+		method        RSAPSS
+		signingString []byte
+		signature     []byte
+		key           VerifyKey
+		expErr        error
+	}{
+		"success - PS256": {
+			method:        NewPS256(),
+			key:           key.Public(),
+			signingString: signingString,
+			signature:     signPSS(crypto.SHA256, rsa.PSSSaltLengthEqualsHash),
+		},
+		"success - PS384": {
+			method:        NewPS384(),
+			key:           key.Public(),
+			signingString: signingString,
+			signature:     signPSS(crypto.SHA384, rsa.PSSSaltLengthEqualsHash),
+		},
+		"success - PS512": {
+			method:        NewPS512(),
+			key:           key.Public(),
+			signingString: signingString,
+			signature:     signPSS(crypto.SHA512, rsa.PSSSaltLengthEqualsHash),
+		},
+		"error - tampered signing string": {
+			method:        NewPS256(),
+			key:           key.Public(),
+			signingString: append([]byte("x"), signingString...),
+			signature:     signPSS(crypto.SHA256, rsa.PSSSaltLengthEqualsHash),
+			expErr:        ErrInvalidSignature,
+		},
+		"error - salt length not equal hash size": {
+			method:        NewPS256(),
+			key:           key.Public(),
+			signingString: signingString,
+			signature:     signPSS(crypto.SHA256, 20),
+			expErr:        ErrInvalidSignature,
+		},
+		"error - PKCS #1 v1.5 signature": {
+			method:        NewPS256(),
+			key:           key.Public(),
+			signingString: signingString,
+			signature:     pkcs1v15Sig,
+			expErr:        ErrInvalidSignature,
+		},
+		"error - invalid key type": {
+			method:        NewPS256(),
+			key:           &ecdsa.PublicKey{},
+			signingString: signingString,
+			signature:     signPSS(crypto.SHA256, rsa.PSSSaltLengthEqualsHash),
+			expErr:        ErrInvalidKeyType,
+		},
+	}
+
+	for desc, tc := range tcs {
+		t.Run(desc, func(t *testing.T) {
+			// Given
+			method := tc.method
+
+			// When
+			actualErr := method.Verify(tc.signingString, tc.signature, tc.key)
+
+			// Then
+			if tc.expErr != nil {
+				require.Equal(t, tc.expErr, actualErr)
+			} else {
+				require.NoError(t, actualErr)
+			}
+		})
+	}
+}
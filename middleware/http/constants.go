@@ -5,4 +5,9 @@ const (
 	// headerXRequestID represents x-request-id key response header
 	headerXRequestID = "x-request-id"
 	httpRequestIDKey = "http.request.id"
+
+	httpSignatureKeyIDKey = "http.signature.key_id"
+	invalidRequestKey     = "invalid_request"
+	invalidSignatureKey   = "invalid_signature"
+	requestTooLargeKey    = "request_too_large"
 )
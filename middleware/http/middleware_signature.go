@@ -0,0 +1,83 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/viebiz/lit"
+	"github.com/viebiz/lit/httpsig"
+	"github.com/viebiz/lit/monitoring"
+)
+
+const defaultMaxSignedBodySize = 10 << 20 // 10MB
+
+// SignatureVerificationOption is an optional configuration of SignatureVerificationMiddleware
+type SignatureVerificationOption func(cfg *signatureVerificationConfig)
+
+type signatureVerificationConfig struct {
+	maxBodySize int64
+}
+
+// WithMaxSignedBodySize overrides the max size of the request body read to verify its Content-Digest, the larger
+// requests are rejected with 413 before their signature is verified
+// Default: 10MB
+func WithMaxSignedBodySize(size int64) SignatureVerificationOption {
+	return func(cfg *signatureVerificationConfig) {
+		if size > 0 {
+			cfg.maxBodySize = size
+		}
+	}
+}
+
+// SignatureVerificationMiddleware verifies the HTTP Message Signature (RFC 9421) of the request, rejecting it
+// with 401 if invalid. The key id of the signature is injected into the request context, see
+// httpsig.GetKeyIDFromContext
+func SignatureVerificationMiddleware(verifier httpsig.Verifier, opts ...SignatureVerificationOption) lit.HandlerFunc {
+	cfg := signatureVerificationConfig{maxBodySize: defaultMaxSignedBodySize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(c lit.Context) {
+		req := c.Request()
+
+		// 1. Read the body for the Content-Digest, and restore it for the next handlers
+		var body []byte
+		if req.Body != nil && req.Body != http.NoBody {
+			var err error
+			if body, err = io.ReadAll(io.LimitReader(req.Body, cfg.maxBodySize+1)); err != nil {
+				c.AbortWithError(lit.HttpError{Status: http.StatusBadRequest, Code: invalidRequestKey, Desc: "Failed to read request body"})
+				return
+			}
+			if int64(len(body)) > cfg.maxBodySize {
+				c.AbortWithError(lit.HttpError{Status: http.StatusRequestEntityTooLarge, Code: requestTooLargeKey, Desc: "Request body too large"})
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		// 2. Verify the signature
+		keyID, err := verifier.Verify(req, body)
+		if err != nil {
+			var sigErr httpsig.Error
+			if errors.As(err, &sigErr) {
+				c.AbortWithError(lit.HttpError{Status: http.StatusUnauthorized, Code: invalidSignatureKey, Desc: err.Error()})
+				return
+			}
+
+			monitoring.FromContext(req.Context()).Errorf(err, "failed to verify request signature")
+			c.AbortWithError(err)
+			return
+		}
+
+		// 3. Inject the key id to request context
+		ctx := httpsig.SetKeyIDInContext(req.Context(), keyID)
+		ctx = monitoring.InjectField(ctx, httpSignatureKeyIDKey, keyID)
+		c.SetRequestContext(ctx)
+
+		// 4. Continue handle request
+		c.Next()
+	}
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/viebiz/lit"
+	"github.com/viebiz/lit/httpsig"
+	"github.com/viebiz/lit/jwt"
+)
+
+func TestSignatureVerificationMiddleware(t *testing.T) {
+	secret := jwt.HMACPrivateKey("orders-secret")
+
+	type mockNonce struct {
+		expCall bool
+		outOK   bool
+		outErr  error
+	}
+	tcs := map[string]struct {
+		givenSign bool
+		givenBody string
+		givenOpts []SignatureVerificationOption
+		mockNonce mockNonce
+		expStatus int
+		expBody   string
+	}{
+		"success": {
+			givenSign: true,
+			givenBody: `{"id":1}`,
+			mockNonce: mockNonce{expCall: true, outOK: true},
+			expStatus: http.StatusOK,
+			expBody:   `{"body":"{\"id\":1}","key_id":"orders"}`,
+		},
+		"error - body too large": {
+			givenSign: true,
+			givenBody: `{"id":1}`,
+			givenOpts: []SignatureVerificationOption{WithMaxSignedBodySize(7)},
+			expStatus: http.StatusRequestEntityTooLarge,
+			expBody:   `{"error":"request_too_large","error_description":"Request body too large"}`,
+		},
+		"success - body at the max size": {
+			givenSign: true,
+			givenBody: `{"id":1}`,
+			givenOpts: []SignatureVerificationOption{WithMaxSignedBodySize(8)},
+			mockNonce: mockNonce{expCall: true, outOK: true},
+			expStatus: http.StatusOK,
+			expBody:   `{"body":"{\"id\":1}","key_id":"orders"}`,
+		},
+		"error - missing signature": {
+			givenBody: `{"id":1}`,
+			expStatus: http.StatusUnauthorized,
+			expBody:   `{"error":"invalid_signature","error_description":"missing signature"}`,
+		},
+		"error - tampered body": {
+			givenSign: true,
+			givenBody: `{"id":2}`,
+			expStatus: http.StatusUnauthorized,
+			expBody:   `{"error":"invalid_signature","error_description":"content digest mismatch"}`,
+		},
+		"error - replayed": {
+			givenSign: true,
+			givenBody: `{"id":1}`,
+			mockNonce: mockNonce{expCall: true, outOK: false},
+			expStatus: http.StatusUnauthorized,
+			expBody:   `{"error":"invalid_signature","error_description":"replayed request"}`,
+		},
+		"error - nonce store failure": {
+			givenSign: true,
+			givenBody: `{"id":1}`,
+			mockNonce: mockNonce{expCall: true, outErr: errors.New("connection refused")},
+			expStatus: http.StatusInternalServerError,
+			expBody:   `{"error":"internal_server_error","error_description":"Something went wrong"}`,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			signer, err := httpsig.NewSigner(httpsig.SignerConfig{KeyID: "orders", Key: secret, Algorithm: httpsig.AlgorithmHMACSHA256})
+			require.NoError(t, err)
+
+			nonces := httpsig.NewMockNonceStore(t)
+			if tc.mockNonce.expCall {
+				nonces.EXPECT().Remember(mock.Anything, mock.Anything, mock.Anything).Return(tc.mockNonce.outOK, tc.mockNonce.outErr)
+			}
+			verifier, err := httpsig.NewVerifier(httpsig.VerifierConfig{
+				Resolver: func(ctx context.Context, keyID string) (httpsig.VerificationKey, error) {
+					return httpsig.VerificationKey{Algorithm: httpsig.AlgorithmHMACSHA256, Key: secret}, nil
+				},
+				Nonces:    nonces,
+				ClockSkew: time.Second,
+			})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(tc.givenBody))
+			if tc.givenSign {
+				signed, err := http.NewRequest(http.MethodPost, "http://example.com/orders", nil)
+				require.NoError(t, err)
+				require.NoError(t, signer.Sign(signed, []byte(`{"id":1}`)))
+				req.Header = signed.Header
+			}
+
+			w := httptest.NewRecorder()
+			route, c, hdlRequest := lit.NewRouterForTest(w)
+			route.Use(SignatureVerificationMiddleware(verifier, tc.givenOpts...))
+			route.HandleWithErr(http.MethodPost, "/orders", func(c lit.Context) error {
+				b, err := io.ReadAll(c.Request().Body)
+				if err != nil {
+					return err
+				}
+
+				c.JSON(http.StatusOK, gin.H{"body": string(b), "key_id": httpsig.GetKeyIDFromContext(c.Request().Context())})
+				return nil
+			})
+			c.SetRequest(req)
+
+			// When
+			hdlRequest()
+
+			// Then
+			require.Equal(t, tc.expStatus, w.Code)
+			require.Equal(t, tc.expBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package lit
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockAdminOption is an autogenerated mock type for the AdminOption type
+type MockAdminOption struct {
+	mock.Mock
+}
+
+type MockAdminOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAdminOption) EXPECT() *MockAdminOption_Expecter {
+	return &MockAdminOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: _a0
+func (_m *MockAdminOption) Execute(_a0 *adminConfig) {
+	_m.Called(_a0)
+}
+
+// MockAdminOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type MockAdminOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - _a0 *adminConfig
+func (_e *MockAdminOption_Expecter) Execute(_a0 interface{}) *MockAdminOption_Execute_Call {
+	return &MockAdminOption_Execute_Call{Call: _e.mock.On("Execute", _a0)}
+}
+
+func (_c *MockAdminOption_Execute_Call) Run(run func(_a0 *adminConfig)) *MockAdminOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*adminConfig))
+	})
+	return _c
+}
+
+func (_c *MockAdminOption_Execute_Call) Return() *MockAdminOption_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockAdminOption_Execute_Call) RunAndReturn(run func(*adminConfig)) *MockAdminOption_Execute_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMockAdminOption creates a new instance of MockAdminOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAdminOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAdminOption {
+	mock := &MockAdminOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,65 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package lit
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockGatewayOption is an autogenerated mock type for the GatewayOption type
+type MockGatewayOption struct {
+	mock.Mock
+}
+
+type MockGatewayOption_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockGatewayOption) EXPECT() *MockGatewayOption_Expecter {
+	return &MockGatewayOption_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: _a0
+func (_m *MockGatewayOption) Execute(_a0 *gatewayConfig) {
+	_m.Called(_a0)
+}
+
+// MockGatewayOption_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type MockGatewayOption_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - _a0 *gatewayConfig
+func (_e *MockGatewayOption_Expecter) Execute(_a0 interface{}) *MockGatewayOption_Execute_Call {
+	return &MockGatewayOption_Execute_Call{Call: _e.mock.On("Execute", _a0)}
+}
+
+func (_c *MockGatewayOption_Execute_Call) Run(run func(_a0 *gatewayConfig)) *MockGatewayOption_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*gatewayConfig))
+	})
+	return _c
+}
+
+func (_c *MockGatewayOption_Execute_Call) Return() *MockGatewayOption_Execute_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockGatewayOption_Execute_Call) RunAndReturn(run func(*gatewayConfig)) *MockGatewayOption_Execute_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMockGatewayOption creates a new instance of MockGatewayOption. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGatewayOption(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGatewayOption {
+	mock := &MockGatewayOption{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
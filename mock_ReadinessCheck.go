@@ -0,0 +1,82 @@
+// Code generated by mockery v2.52.2. DO NOT EDIT.
+
+package lit
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockReadinessCheck is an autogenerated mock type for the ReadinessCheck type
+type MockReadinessCheck struct {
+	mock.Mock
+}
+
+type MockReadinessCheck_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockReadinessCheck) EXPECT() *MockReadinessCheck_Expecter {
+	return &MockReadinessCheck_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: ctx
+func (_m *MockReadinessCheck) Execute(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockReadinessCheck_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type MockReadinessCheck_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockReadinessCheck_Expecter) Execute(ctx interface{}) *MockReadinessCheck_Execute_Call {
+	return &MockReadinessCheck_Execute_Call{Call: _e.mock.On("Execute", ctx)}
+}
+
+func (_c *MockReadinessCheck_Execute_Call) Run(run func(ctx context.Context)) *MockReadinessCheck_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockReadinessCheck_Execute_Call) Return(_a0 error) *MockReadinessCheck_Execute_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReadinessCheck_Execute_Call) RunAndReturn(run func(context.Context) error) *MockReadinessCheck_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockReadinessCheck creates a new instance of MockReadinessCheck. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockReadinessCheck(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockReadinessCheck {
+	mock := &MockReadinessCheck{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/viebiz/lit/monitoring/tracing"
 )
@@ -20,6 +21,8 @@ type Config struct {
 	SentryDSN       string    // To capture error, skip init Sentry if it's not provided
 	OtelExporterURL string    // To support OpenTelemetry
 	ExtraTags       map[string]string
+	LogLevel        string            // Minimum enabled log level, e.g. debug, info, warn, error. Default is info
+	LoggerLevels    map[string]string // Minimum enabled log level by logger name, overrides LogLevel
 }
 
 // New creates a new Monitor instance
@@ -30,9 +33,15 @@ func New(cfg Config) (*Monitor, error) {
 		w = cfg.Writer
 	}
 
+	levels, err := parseLevels(cfg.LogLevel, cfg.LoggerLevels)
+	if err != nil {
+		return nil, err
+	}
+
 	m := &Monitor{
-		logger:  zap.New(newZapCore(w)),
+		logger:  zap.New(newZapCore(w, levels)),
 		logTags: map[string]string{},
+		levels:  levels,
 	}
 
 	if cfg.ExtraTags == nil {
@@ -67,3 +76,25 @@ func New(cfg Config) (*Monitor, error) {
 
 	return m, nil
 }
+
+func parseLevels(global string, named map[string]string) (*levelController, error) {
+	globalLvl := zapcore.InfoLevel
+	if global != "" {
+		lvl, err := zapcore.ParseLevel(global)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		globalLvl = lvl
+	}
+
+	namedLvls := make(map[string]zapcore.Level, len(named))
+	for name, v := range named {
+		lvl, err := zapcore.ParseLevel(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "logger %s", name)
+		}
+		namedLvls[name] = lvl
+	}
+
+	return newLevelController(globalLvl, namedLvls), nil
+}
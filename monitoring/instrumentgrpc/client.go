@@ -2,6 +2,7 @@ package instrumentgrpc
 
 import (
 	"context"
+	"net"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -14,6 +15,15 @@ import (
 )
 
 func StartGRPCUnaryCallSegment(ctx context.Context, svcInfo monitoring.ExternalServiceInfo, fullMethod string) (context.Context, func(error)) {
+	ctx, span := startOutgoingSpan(ctx, svcInfo, fullMethod, unaryOutgoingCallSpanName)
+
+	return ctx, func(err error) {
+		endOutgoingSpan(span, err)
+	}
+}
+
+// startOutgoingSpan starts the client span of the call, and injects the trace context into the outgoing metadata
+func startOutgoingSpan(ctx context.Context, svcInfo monitoring.ExternalServiceInfo, fullMethod string, spanName string) (context.Context, trace.Span) {
 	logTags := map[string]string{
 		rpcSystemKey:     "grpc",
 		serverAddressKey: svcInfo.Hostname + ":" + svcInfo.Port,
@@ -34,7 +44,7 @@ func StartGRPCUnaryCallSegment(ctx context.Context, svcInfo monitoring.ExternalS
 		)
 	}
 
-	ctx, span := tracer.Start(ctx, unaryOutgoingCallSpanName, trace.WithSpanKind(trace.SpanKindClient),
+	ctx, span := tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(attrs...),
 	)
 
@@ -54,12 +64,41 @@ func StartGRPCUnaryCallSegment(ctx context.Context, svcInfo monitoring.ExternalS
 	m = m.With(logTags)
 	ctx = monitoring.SetInContext(ctx, m)
 
-	return ctx, func(err error) {
-		if err != nil {
-			span.SetStatus(codes.Error, err.Error())
-			span.RecordError(err, trace.WithStackTrace(true))
-		}
+	return ctx, span
+}
 
-		span.End()
+func endOutgoingSpan(span trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err, trace.WithStackTrace(true))
 	}
+
+	span.End()
+}
+
+// RecordGRPCCallAttempt adds the retry or hedged attempt of the outgoing call as event of the span started by StartGRPCUnaryCallSegment
+// attempt starts from 1 for the original call, which is not recorded
+func RecordGRPCCallAttempt(ctx context.Context, attempt int, transparent bool) {
+	if attempt <= 1 {
+		return
+	}
+
+	trace.SpanFromContext(ctx).AddEvent(retryAttemptEventName, trace.WithAttributes(
+		attribute.Int(rpcAttemptKey, attempt),
+		attribute.Bool(rpcTransparentRetryKey, transparent),
+	))
+}
+
+// RecordGRPCPeer sets the backend address chosen for the outgoing call on the span,
+// and returns the context with the address tagged on the monitor
+func RecordGRPCPeer(ctx context.Context, addr net.Addr) context.Context {
+	if addr == nil {
+		return ctx
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(semconv.NetworkPeerAddress(addr.String()))
+
+	return monitoring.SetInContext(ctx, monitoring.FromContext(ctx).With(map[string]string{
+		networkPeerAddressKey: addr.String(),
+	}))
 }
@@ -0,0 +1,133 @@
+package instrumentgrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/viebiz/lit/monitoring"
+)
+
+// StartGRPCStreamCallSegment starts tracing for an outgoing stream call.
+// The returned context carries the span and the trace context in the outgoing metadata, it must be used to open the stream.
+// The returned func wraps the opened stream, or ends the span with the error if the stream cannot be opened.
+func StartGRPCStreamCallSegment(ctx context.Context, svcInfo monitoring.ExternalServiceInfo, fullMethod string) (context.Context, func(grpc.ClientStream, *grpc.StreamDesc, error) grpc.ClientStream) {
+	ctx, span := startOutgoingSpan(ctx, svcInfo, fullMethod, streamOutgoingSpanName)
+
+	return ctx, func(cs grpc.ClientStream, desc *grpc.StreamDesc, err error) grpc.ClientStream {
+		if err != nil {
+			endOutgoingSpan(span, err)
+			return nil
+		}
+
+		return newClientStream(ctx, cs, desc, span)
+	}
+}
+
+// clientStream wraps grpc.ClientStream to record message events and end the span when the stream is done, i.e.
+// RecvMsg returns io.EOF or an error, the single response of a client streaming call is received,
+// any other call fails, or the context is canceled.
+type clientStream struct {
+	grpc.ClientStream
+
+	desc          *grpc.StreamDesc
+	span          trace.Span
+	sentCount     atomic.Int64
+	receivedCount atomic.Int64
+	endOnce       sync.Once
+	done          chan struct{}
+}
+
+func newClientStream(ctx context.Context, cs grpc.ClientStream, desc *grpc.StreamDesc, span trace.Span) *clientStream {
+	s := &clientStream{
+		ClientStream: cs,
+		desc:         desc,
+		span:         span,
+		done:         make(chan struct{}),
+	}
+
+	// The caller must either cancel the context or read until io.EOF, so the span always ends
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.end(status.FromContextError(ctx.Err()).Err()) // Same error as returned by the stream
+		case <-s.done:
+		}
+	}()
+
+	return s
+}
+
+func (s *clientStream) SendMsg(msg any) error {
+	if err := s.ClientStream.SendMsg(msg); err != nil {
+		if !errors.Is(err, io.EOF) { // io.EOF means the stream is done, the status is returned by RecvMsg
+			s.end(err)
+		}
+		return err
+	}
+
+	recordMessageEvent(s.span, semconv.RPCMessageTypeSent, s.sentCount.Add(1), msg)
+
+	return nil
+}
+
+func (s *clientStream) RecvMsg(msg any) error {
+	if err := s.ClientStream.RecvMsg(msg); err != nil {
+		if errors.Is(err, io.EOF) {
+			s.end(nil) // The stream is done successfully
+		} else {
+			s.end(err)
+		}
+		return err
+	}
+
+	recordMessageEvent(s.span, semconv.RPCMessageTypeReceived, s.receivedCount.Add(1), msg)
+
+	if !s.desc.ServerStreams {
+		s.end(nil) // The single response of a client streaming call
+	}
+
+	return nil
+}
+
+func (s *clientStream) CloseSend() error {
+	if err := s.ClientStream.CloseSend(); err != nil {
+		s.end(err)
+		return err
+	}
+
+	s.span.AddEvent(closeSendEventName)
+
+	return nil
+}
+
+func (s *clientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	if err != nil {
+		s.end(err)
+	}
+
+	return md, err
+}
+
+// end ends the span once, with the message counts
+func (s *clientStream) end(err error) {
+	s.endOnce.Do(func() {
+		close(s.done)
+
+		s.span.SetAttributes(
+			attribute.Int64(rpcMessagesSentKey, s.sentCount.Load()),
+			attribute.Int64(rpcMessagesReceivedKey, s.receivedCount.Load()),
+		)
+		endOutgoingSpan(s.span, err)
+	})
+}
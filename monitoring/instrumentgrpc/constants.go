@@ -9,18 +9,27 @@ import (
 const (
 	tracerName                = "github.com/viebiz/lit/monitoring/instrumentgrpc"
 	unaryOutgoingCallSpanName = "grpc.unary_outgoing_call"
+	streamOutgoingSpanName    = "grpc.stream_outgoing_call"
 	unaryIncomingSpanName     = "grpc.unary_incoming_call"
+	streamIncomingSpanName    = "grpc.stream_incoming_call"
+	messageEventName          = "message"
+	retryAttemptEventName     = "retry_attempt"
+	closeSendEventName        = "close_send"
 
 	// Settings
 	shouldLogUnaryRequestBody = true
 
 	// Attributes
-	rpcSystemKey          = "rpc.system"
-	serverAddressKey      = "server.address"
-	rpcServiceKey         = "rpc.service"
-	rpcMethodKey          = "rpc.method"
-	networkPeerAddressKey = "network.peer.address"
-	networkTransportKey   = "network.transport"
+	rpcSystemKey           = "rpc.system"
+	serverAddressKey       = "server.address"
+	rpcServiceKey          = "rpc.service"
+	rpcMethodKey           = "rpc.method"
+	networkPeerAddressKey  = "network.peer.address"
+	networkTransportKey    = "network.transport"
+	rpcMessagesSentKey     = "rpc.grpc.messages_sent"
+	rpcMessagesReceivedKey = "rpc.grpc.messages_received"
+	rpcAttemptKey          = "rpc.grpc.attempt"
+	rpcTransparentRetryKey = "rpc.grpc.transparent_retry"
 )
 
 var (
@@ -19,6 +19,27 @@ import (
 )
 
 func StartUnaryIncomingCall(ctx context.Context, m *monitoring.Monitor, fullMethod string, req any) (context.Context, RequestMetadata, func(error)) {
+	reqMeta := RequestMetadata{
+		ServiceMethod: fullMethod,
+	}
+
+	// Log request body
+	if shouldLogUnaryRequestBody {
+		reqMeta.BodyToLog = serializeProtoMessage(req)
+	}
+
+	ctx, span := startIncomingSpan(ctx, m, fullMethod, unaryIncomingSpanName)
+
+	return ctx,
+		reqMeta,
+		func(err error) {
+			endIncomingSpan(span, err)
+		}
+}
+
+// startIncomingSpan starts a server span continuing the trace from incoming metadata,
+// and injects the monitor with tracing info and rpc log tags to the returned context
+func startIncomingSpan(ctx context.Context, m *monitoring.Monitor, fullMethod string, spanName string) (context.Context, trace.Span) {
 	// Init log fields
 	logTags := map[string]string{
 		rpcSystemKey: "grpc",
@@ -48,15 +69,6 @@ func StartUnaryIncomingCall(ctx context.Context, m *monitoring.Monitor, fullMeth
 		)
 	}
 
-	reqMeta := RequestMetadata{
-		ServiceMethod: fullMethod,
-	}
-
-	// Log request body
-	if shouldLogUnaryRequestBody {
-		reqMeta.BodyToLog = serializeProtoMessage(req)
-	}
-
 	// Extract metadata from incoming context
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -69,29 +81,30 @@ func StartUnaryIncomingCall(ctx context.Context, m *monitoring.Monitor, fullMeth
 	curSpanCtx := otel.GetTextMapPropagator().Extract(ctx, mdCarrier(md))
 	spanCtx := trace.SpanContextFromContext(curSpanCtx)
 
-	ctx, span := tracer.Start(trace.ContextWithRemoteSpanContext(ctx, spanCtx), unaryIncomingSpanName,
+	ctx, span := tracer.Start(trace.ContextWithRemoteSpanContext(ctx, spanCtx), spanName,
 		trace.WithSpanKind(trace.SpanKindServer),
 		trace.WithAttributes(attrs...),
 	)
-	m = monitoring.InjectTracingInfo(m, span.SpanContext())
 
+	m = monitoring.InjectTracingInfo(m, span.SpanContext())
 	m = m.With(logTags)
 	ctx = monitoring.SetInContext(ctx, m)
 
-	return ctx,
-		reqMeta,
-		func(err error) {
-			if err == nil {
-				span.SetStatus(codes.Ok, "")
-				span.SetAttributes(semconv.RPCGRPCStatusCodeOk)
-			} else {
-				span.SetStatus(codes.Error, err.Error())
-				errStatus := status.Convert(err)
-				span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(errStatus.Code())))
-			}
-
-			span.End()
-		}
+	return ctx, span
+}
+
+// endIncomingSpan records the call status and ends the server span
+func endIncomingSpan(span trace.Span, err error) {
+	if err == nil {
+		span.SetStatus(codes.Ok, "")
+		span.SetAttributes(semconv.RPCGRPCStatusCodeOk)
+	} else {
+		span.SetStatus(codes.Error, err.Error())
+		errStatus := status.Convert(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(errStatus.Code())))
+	}
+
+	span.End()
 }
 
 type RequestMetadata struct {
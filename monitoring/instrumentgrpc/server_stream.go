@@ -0,0 +1,84 @@
+package instrumentgrpc
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/viebiz/lit/monitoring"
+)
+
+// StartStreamIncomingCall starts tracing for an incoming stream call.
+// It returns a wrapped grpc.ServerStream whose Context() carries the monitor and span,
+// and records every message sent and received as a span event.
+func StartStreamIncomingCall(ss grpc.ServerStream, m *monitoring.Monitor, fullMethod string) (grpc.ServerStream, func(error)) {
+	ctx, span := startIncomingSpan(ss.Context(), m, fullMethod, streamIncomingSpanName)
+
+	wrapped := &serverStream{
+		ServerStream: ss,
+		ctx:          ctx,
+		span:         span,
+	}
+
+	return wrapped, func(err error) {
+		span.SetAttributes(
+			attribute.Int64(rpcMessagesSentKey, wrapped.sentCount.Load()),
+			attribute.Int64(rpcMessagesReceivedKey, wrapped.receivedCount.Load()),
+		)
+
+		endIncomingSpan(span, err)
+	}
+}
+
+// serverStream wraps grpc.ServerStream to carry the instrumented context and record message events
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx           context.Context
+	span          trace.Span
+	sentCount     atomic.Int64
+	receivedCount atomic.Int64
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) SendMsg(msg any) error {
+	if err := s.ServerStream.SendMsg(msg); err != nil {
+		return err
+	}
+
+	recordMessageEvent(s.span, semconv.RPCMessageTypeSent, s.sentCount.Add(1), msg)
+
+	return nil
+}
+
+func (s *serverStream) RecvMsg(msg any) error {
+	if err := s.ServerStream.RecvMsg(msg); err != nil {
+		return err
+	}
+
+	recordMessageEvent(s.span, semconv.RPCMessageTypeReceived, s.receivedCount.Add(1), msg)
+
+	return nil
+}
+
+// recordMessageEvent adds a message event to the span following the rpc semantic conventions
+func recordMessageEvent(span trace.Span, msgType attribute.KeyValue, id int64, msg any) {
+	attrs := []attribute.KeyValue{
+		msgType,
+		semconv.RPCMessageID(int(id)),
+	}
+
+	if p, ok := msg.(proto.Message); ok {
+		attrs = append(attrs, semconv.RPCMessageUncompressedSize(proto.Size(p)))
+	}
+
+	span.AddEvent(messageEventName, trace.WithAttributes(attrs...))
+}
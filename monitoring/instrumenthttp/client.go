@@ -6,6 +6,7 @@ import (
 
 	"github.com/viebiz/lit/monitoring"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
@@ -86,3 +87,37 @@ func StartOutgoingSegment(
 		span.End()
 	}
 }
+
+// RecordOutgoingAttempt sets the attempt number of the request on the span started by StartOutgoingSegment,
+// attempt starts from 1 for the original request
+func RecordOutgoingAttempt(ctx context.Context, attempt int) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int(httpRequestAttemptKey, attempt))
+	if attempt > 1 {
+		span.SetAttributes(semconv.HTTPRequestResendCount(attempt - 1))
+	}
+}
+
+// RecordCircuitBreakerStateChange adds the state change of the circuit breaker guarding the external service
+// as event of the span started by StartOutgoingGroupSegment
+func RecordCircuitBreakerStateChange(ctx context.Context, serviceName, prevState, state string) {
+	trace.SpanFromContext(ctx).AddEvent(circuitBreakerStateEventName, trace.WithAttributes(
+		semconv.ServiceName(serviceName),
+		attribute.String(circuitBreakerPrevKey, prevState),
+		attribute.String(circuitBreakerStateKey, state),
+	))
+}
+
+// RecordCacheStatus sets the response cache status of the request (e.g. hit, miss, revalidated) on the span started
+// by StartOutgoingGroupSegment, the request is a cache hit when served without calling the external service
+func RecordCacheStatus(ctx context.Context, status string, hit bool) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Bool(httpCacheHitKey, hit),
+		attribute.String(httpCacheStatusKey, status),
+	)
+}
+
+// RecordCoalesced marks the span started by StartOutgoingGroupSegment as served by the identical request in flight
+func RecordCoalesced(ctx context.Context) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool(httpCoalescedKey, true))
+}
@@ -12,6 +12,9 @@ const (
 	httpOutgoingSpanName = "http.outgoing_request"
 	httpRequestSpanName  = "http.request"
 
+	// Events
+	circuitBreakerStateEventName = "circuit_breaker.state_change"
+
 	// Attributes
 	httpRequestMethodKey   = "http.request.method"
 	serverAddressKey       = "server.address"
@@ -21,6 +24,12 @@ const (
 	networkProtocolVersion = "network.protocol.version"
 	httpRequestBodySize    = "http.request.body.size"
 	serviceNameKey         = "service.name"
+	circuitBreakerStateKey = "circuit_breaker.state"
+	circuitBreakerPrevKey  = "circuit_breaker.previous_state"
+	httpRequestAttemptKey  = "http.request.attempt"
+	httpCacheHitKey        = "http.client.cache.hit"
+	httpCacheStatusKey     = "http.client.cache.status"
+	httpCoalescedKey       = "http.client.coalesced"
 
 	// Constants
 	requestHeaderContentType = "Content-Type"
@@ -0,0 +1,186 @@
+package monitoring
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levelController controls the minimum enabled log level, globally and per logger name.
+// It is shared between a Monitor and all of its children.
+type levelController struct {
+	global zap.AtomicLevel
+
+	mu      sync.RWMutex
+	named   map[string]zapcore.Level
+	reverts map[string]*levelRevert // Pending reverts by logger name, empty name for global level
+}
+
+// levelRevert keeps the level to restore when a temporary level expires
+type levelRevert struct {
+	timer *time.Timer
+	prev  *zapcore.Level // nil means no override for a named logger
+}
+
+func newLevelController(global zapcore.Level, named map[string]zapcore.Level) *levelController {
+	if named == nil {
+		named = map[string]zapcore.Level{}
+	}
+
+	return &levelController{
+		global:  zap.NewAtomicLevelAt(global),
+		named:   named,
+		reverts: map[string]*levelRevert{},
+	}
+}
+
+// enabled returns true if the given level is enabled for the given logger name
+func (lc *levelController) enabled(name string, lvl zapcore.Level) bool {
+	if name != "" {
+		lc.mu.RLock()
+		override, ok := lc.named[name]
+		lc.mu.RUnlock()
+
+		if ok {
+			return override.Enabled(lvl)
+		}
+	}
+
+	return lc.global.Enabled(lvl)
+}
+
+// anyEnabled returns true if the given level is enabled for at least one logger
+func (lc *levelController) anyEnabled(lvl zapcore.Level) bool {
+	if lc.global.Enabled(lvl) {
+		return true
+	}
+
+	lc.mu.RLock()
+	defer lc.mu.RUnlock()
+	for _, override := range lc.named {
+		if override.Enabled(lvl) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// set changes the level of the given logger name, or the global level if name is empty.
+// If ttl is positive, the level before the first pending change is restored after ttl.
+func (lc *levelController) set(name string, lvl zapcore.Level, ttl time.Duration) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	var prev *zapcore.Level
+	if pending, ok := lc.reverts[name]; ok {
+		// Keep reverting to the original level instead of the temporary one
+		pending.timer.Stop()
+		prev = pending.prev
+		delete(lc.reverts, name)
+	} else {
+		prev = lc.current(name)
+	}
+
+	lc.apply(name, &lvl)
+
+	if ttl <= 0 {
+		return
+	}
+
+	revert := &levelRevert{prev: prev}
+	revert.timer = time.AfterFunc(ttl, func() {
+		lc.mu.Lock()
+		defer lc.mu.Unlock()
+
+		// Skip if the revert has been replaced by a newer change
+		if lc.reverts[name] != revert {
+			return
+		}
+		delete(lc.reverts, name)
+		lc.apply(name, revert.prev)
+	})
+	lc.reverts[name] = revert
+}
+
+// unset removes the level override of the given logger name
+func (lc *levelController) unset(name string) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if pending, ok := lc.reverts[name]; ok {
+		pending.timer.Stop()
+		delete(lc.reverts, name)
+	}
+	delete(lc.named, name)
+}
+
+// current returns the level of the given logger name, must be called with lock held
+func (lc *levelController) current(name string) *zapcore.Level {
+	if name == "" {
+		lvl := lc.global.Level()
+		return &lvl
+	}
+
+	if lvl, ok := lc.named[name]; ok {
+		return &lvl
+	}
+
+	return nil
+}
+
+// apply sets the level of the given logger name, must be called with lock held
+func (lc *levelController) apply(name string, lvl *zapcore.Level) {
+	switch {
+	case name == "":
+		if lvl != nil {
+			lc.global.SetLevel(*lvl)
+		}
+	case lvl == nil:
+		delete(lc.named, name)
+	default:
+		lc.named[name] = *lvl
+	}
+}
+
+// snapshot returns the global level and a copy of the named overrides
+func (lc *levelController) snapshot() (zapcore.Level, map[string]zapcore.Level) {
+	lc.mu.RLock()
+	defer lc.mu.RUnlock()
+
+	named := make(map[string]zapcore.Level, len(lc.named))
+	for k, v := range lc.named {
+		named[k] = v
+	}
+
+	return lc.global.Level(), named
+}
+
+// leveledCore filters log entries using the levelController,
+// so that per logger name levels can be changed at runtime
+type leveledCore struct {
+	zapcore.Core
+
+	levels *levelController
+}
+
+func (c leveledCore) Enabled(lvl zapcore.Level) bool {
+	return c.levels.anyEnabled(lvl)
+}
+
+func (c leveledCore) With(fields []zapcore.Field) zapcore.Core {
+	return leveledCore{
+		Core:   c.Core.With(fields),
+		levels: c.levels,
+	}
+}
+
+func (c leveledCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.enabled(ent.LoggerName, ent.Level) {
+		return ce
+	}
+
+	return c.Core.Check(ent, ce)
+}
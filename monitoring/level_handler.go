@@ -0,0 +1,128 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AtomicLevel returns the global log level, which can be changed at runtime
+func (m *Monitor) AtomicLevel() zap.AtomicLevel {
+	if m == nil || m.levels == nil {
+		return zap.NewAtomicLevel()
+	}
+
+	return m.levels.global
+}
+
+// SetLevel changes the global log level.
+// If ttl is positive, the previous level is restored automatically after ttl.
+func (m *Monitor) SetLevel(lvl zapcore.Level, ttl time.Duration) {
+	if m == nil || m.levels == nil {
+		return
+	}
+
+	m.levels.set("", lvl, ttl)
+}
+
+// SetLoggerLevel overrides the log level of the Monitor created with the given Named name.
+// If ttl is positive, the previous level is restored automatically after ttl.
+func (m *Monitor) SetLoggerLevel(name string, lvl zapcore.Level, ttl time.Duration) {
+	if m == nil || m.levels == nil {
+		return
+	}
+
+	m.levels.set(name, lvl, ttl)
+}
+
+// ResetLoggerLevel removes the log level override of the given logger name
+func (m *Monitor) ResetLoggerLevel(name string) {
+	if m == nil || m.levels == nil {
+		return
+	}
+
+	m.levels.unset(name)
+}
+
+// LevelRequest is the payload to change log level through LevelHandler
+type LevelRequest struct {
+	Level  string `json:"level"`
+	Logger string `json:"logger,omitempty"` // Empty to change the global level
+	TTL    string `json:"ttl,omitempty"`    // Duration before reverting, e.g. 15m. Empty to keep the level
+}
+
+// LevelResponse is the current log levels returned by LevelHandler
+type LevelResponse struct {
+	Level   string            `json:"level"`
+	Loggers map[string]string `json:"loggers,omitempty"`
+}
+
+// LevelHandler returns a http.Handler to get the current log levels with GET,
+// and to change them with PUT using a LevelRequest JSON body
+func (m *Monitor) LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m == nil || m.levels == nil {
+			writeLevelJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "monitor not initialized"})
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req LevelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeLevelJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid body: %v", err)})
+				return
+			}
+
+			if err := m.applyLevelRequest(req); err != nil {
+				writeLevelJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			m.Infof("Log level changed: level=%s, logger=%s, ttl=%s", req.Level, req.Logger, req.TTL)
+		default:
+			writeLevelJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "only GET and PUT are supported"})
+			return
+		}
+
+		global, named := m.levels.snapshot()
+		resp := LevelResponse{Level: global.String()}
+		if len(named) > 0 {
+			resp.Loggers = make(map[string]string, len(named))
+			for k, v := range named {
+				resp.Loggers[k] = v.String()
+			}
+		}
+
+		writeLevelJSON(w, http.StatusOK, resp)
+	})
+}
+
+func (m *Monitor) applyLevelRequest(req LevelRequest) error {
+	lvl, err := zapcore.ParseLevel(req.Level)
+	if err != nil {
+		return err
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		if ttl, err = time.ParseDuration(req.TTL); err != nil {
+			return fmt.Errorf("invalid ttl: %w", err)
+		}
+	}
+
+	m.levels.set(req.Logger, lvl, ttl)
+
+	return nil
+}
+
+func writeLevelJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
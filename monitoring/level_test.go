@@ -0,0 +1,207 @@
+package monitoring
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/viebiz/lit/testutil"
+)
+
+func TestMonitor_Levels(t *testing.T) {
+	type args struct {
+		givenCfg  Config
+		doLogging func(m *Monitor)
+		expected  []map[string]string
+	}
+	tcs := map[string]args{
+		"default level - debug is skipped": {
+			doLogging: func(m *Monitor) {
+				m.Debugf("Debug %s", "message")
+				m.Infof("Info %s", "message")
+				m.Warnf("Warn %s", "message")
+			},
+			expected: []map[string]string{
+				{"level": "INFO", "msg": "Info message"},
+				{"level": "WARN", "msg": "Warn message"},
+			},
+		},
+		"debug level": {
+			givenCfg: Config{LogLevel: "debug"},
+			doLogging: func(m *Monitor) {
+				m.Debugf("Debug %s", "message")
+				m.Infof("Info %s", "message")
+			},
+			expected: []map[string]string{
+				{"level": "DEBUG", "msg": "Debug message"},
+				{"level": "INFO", "msg": "Info message"},
+			},
+		},
+		"warn level": {
+			givenCfg: Config{LogLevel: "warn"},
+			doLogging: func(m *Monitor) {
+				m.Infof("Info %s", "message")
+				m.Warnf("Warn %s", "message")
+			},
+			expected: []map[string]string{
+				{"level": "WARN", "msg": "Warn message"},
+			},
+		},
+		"logger level overrides": {
+			givenCfg: Config{LogLevel: "warn", LoggerLevels: map[string]string{"postgres": "debug"}},
+			doLogging: func(m *Monitor) {
+				m.Debugf("Skipped")
+				m.Named("postgres").Debugf("Debug from postgres")
+				m.Named("redis").Debugf("Skipped")
+			},
+			expected: []map[string]string{
+				{"level": "DEBUG", "msg": "Debug from postgres", "logger": "postgres"},
+			},
+		},
+		"set level at runtime": {
+			doLogging: func(m *Monitor) {
+				child := m.WithTag("request_id", "123")
+				m.SetLevel(zapcore.DebugLevel, 0)
+				child.Debugf("Debug after changed")
+				m.AtomicLevel().SetLevel(zapcore.ErrorLevel)
+				child.Warnf("Skipped")
+			},
+			expected: []map[string]string{
+				{"level": "DEBUG", "msg": "Debug after changed", "request_id": "123"},
+			},
+		},
+		"set logger level at runtime": {
+			doLogging: func(m *Monitor) {
+				pg := m.Named("postgres")
+				m.SetLoggerLevel("postgres", zapcore.DebugLevel, 0)
+				pg.Debugf("Debug after changed")
+				m.ResetLoggerLevel("postgres")
+				pg.Debugf("Skipped")
+			},
+			expected: []map[string]string{
+				{"level": "DEBUG", "msg": "Debug after changed", "logger": "postgres"},
+			},
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			logBuffer := bytes.NewBuffer(nil)
+			tc.givenCfg.Writer = logBuffer
+			m, err := New(tc.givenCfg)
+			require.NoError(t, err)
+			logBuffer.Reset() // Skip initialization logs
+
+			// When
+			tc.doLogging(m)
+			m.Flush(DefaultFlushWait)
+
+			// Then
+			parsedLog, err := parseLog(logBuffer.Bytes())
+			require.NoError(t, err)
+			testutil.Equal(t, tc.expected, parsedLog, testutil.IgnoreSliceMapEntries(func(k string, v string) bool {
+				return k == "ts" || k == "server.name" || k == "environment" || k == "version"
+			}))
+		})
+	}
+}
+
+func TestNew_InvalidLevel(t *testing.T) {
+	_, err := New(Config{LogLevel: "verbose", Writer: bytes.NewBuffer(nil)})
+	require.ErrorContains(t, err, `unrecognized level: "verbose"`)
+
+	_, err = New(Config{LoggerLevels: map[string]string{"postgres": "verbose"}, Writer: bytes.NewBuffer(nil)})
+	require.ErrorContains(t, err, `logger postgres: unrecognized level: "verbose"`)
+}
+
+func TestMonitor_SetLevelWithTTL(t *testing.T) {
+	// Given
+	m, err := New(Config{Writer: bytes.NewBuffer(nil)})
+	require.NoError(t, err)
+
+	// When
+	m.SetLevel(zapcore.DebugLevel, 50*time.Millisecond)
+	m.SetLevel(zapcore.WarnLevel, 50*time.Millisecond) // Extends the temporary change, should still revert to info
+	m.SetLoggerLevel("postgres", zapcore.DebugLevel, 50*time.Millisecond)
+
+	// Then
+	require.Equal(t, zapcore.WarnLevel, m.AtomicLevel().Level())
+	require.True(t, m.levels.enabled("postgres", zapcore.DebugLevel))
+	require.Eventually(t, func() bool {
+		return m.AtomicLevel().Level() == zapcore.InfoLevel && !m.levels.enabled("postgres", zapcore.DebugLevel)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestMonitor_LevelHandler(t *testing.T) {
+	type args struct {
+		givenMethod string
+		givenBody   string
+		expStatus   int
+		expBody     string
+	}
+	tcs := map[string]args{
+		"get": {
+			givenMethod: http.MethodGet,
+			expStatus:   http.StatusOK,
+			expBody:     `{"level":"info"}`,
+		},
+		"put global level": {
+			givenMethod: http.MethodPut,
+			givenBody:   `{"level":"debug","ttl":"10m"}`,
+			expStatus:   http.StatusOK,
+			expBody:     `{"level":"debug"}`,
+		},
+		"put logger level": {
+			givenMethod: http.MethodPut,
+			givenBody:   `{"level":"debug","logger":"postgres"}`,
+			expStatus:   http.StatusOK,
+			expBody:     `{"level":"info","loggers":{"postgres":"debug"}}`,
+		},
+		"invalid level": {
+			givenMethod: http.MethodPut,
+			givenBody:   `{"level":"verbose"}`,
+			expStatus:   http.StatusBadRequest,
+			expBody:     `{"error":"unrecognized level: \"verbose\""}`,
+		},
+		"invalid ttl": {
+			givenMethod: http.MethodPut,
+			givenBody:   `{"level":"debug","ttl":"forever"}`,
+			expStatus:   http.StatusBadRequest,
+			expBody:     `{"error":"invalid ttl: time: invalid duration \"forever\""}`,
+		},
+		"method not allowed": {
+			givenMethod: http.MethodPost,
+			expStatus:   http.StatusMethodNotAllowed,
+			expBody:     `{"error":"only GET and PUT are supported"}`,
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			m, err := New(Config{Writer: bytes.NewBuffer(nil)})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(tc.givenMethod, "/_/log-level", strings.NewReader(tc.givenBody))
+			w := httptest.NewRecorder()
+
+			// When
+			m.LevelHandler().ServeHTTP(w, req)
+
+			// Then
+			require.Equal(t, tc.expStatus, w.Code)
+			require.JSONEq(t, tc.expBody, w.Body.String())
+		})
+	}
+}
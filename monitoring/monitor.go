@@ -22,6 +22,7 @@ type Monitor struct {
 	// Currently unable to retrieve logTags saved in uber zap logger due to its design to be quick.
 	// Hence, keeping a local copy of logTags for other purpose such as sentry error reporting
 	logTags map[string]string
+	levels  *levelController
 }
 
 func (m *Monitor) WithTag(key string, value string) *Monitor {
@@ -35,6 +36,7 @@ func (m *Monitor) WithTag(key string, value string) *Monitor {
 		sentryClient: m.sentryClient,
 		logger:       m.logger.With(zap.String(key, value)),
 		logTags:      clonedTags,
+		levels:       m.levels,
 	}
 }
 
@@ -56,9 +58,33 @@ func (m *Monitor) With(tags map[string]string) *Monitor {
 		sentryClient: m.sentryClient,
 		logger:       m.logger.With(zapFields...),
 		logTags:      clonedTags,
+		levels:       m.levels,
 	}
 }
 
+// Named creates a new child Monitor with the given logger name appended to the parent's name.
+// The level of a named Monitor can be overridden with SetLoggerLevel.
+func (m *Monitor) Named(name string) *Monitor {
+	if m == nil {
+		return nil
+	}
+
+	return &Monitor{
+		sentryClient: m.sentryClient,
+		logger:       m.logger.Named(name),
+		logTags:      maps.Clone(m.logTags),
+		levels:       m.levels,
+	}
+}
+
+// Debugf logs the message using debug level
+func (m *Monitor) Debugf(format string, args ...interface{}) {
+	if m == nil {
+		return
+	}
+	m.logger.Debug(fmt.Sprintf(format, args...))
+}
+
 // Infof logs the message using info level
 func (m *Monitor) Infof(format string, args ...interface{}) {
 	if m == nil {
@@ -67,6 +93,14 @@ func (m *Monitor) Infof(format string, args ...interface{}) {
 	m.logger.Info(fmt.Sprintf(format, args...))
 }
 
+// Warnf logs the message using warn level
+func (m *Monitor) Warnf(format string, args ...interface{}) {
+	if m == nil {
+		return
+	}
+	m.logger.Warn(fmt.Sprintf(format, args...))
+}
+
 // Errorf logs the message using error level and reports the error to sentry
 func (m *Monitor) Errorf(err error, msg string, args ...interface{}) {
 	if m == nil {
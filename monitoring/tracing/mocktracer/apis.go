@@ -1,6 +1,8 @@
 package mocktracer
 
 import (
+	"sync"
+
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -8,25 +10,36 @@ import (
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
+var (
+	initOnce sync.Once
+	exporter *tracetest.InMemoryExporter
+	provider *sdktrace.TracerProvider
+)
+
 // Start setup TracerProviderMock for testing
+// The provider is shared by the whole test binary, because the package level tracers keep delegating to the first global provider
+// Calling Start again resets the collected spans
 func Start() TracerProviderMock {
-	// Create in-memory exporter support collect span for unit test
-	exp := tracetest.NewInMemoryExporter()
+	initOnce.Do(func() {
+		// Create in-memory exporter support collect span for unit test
+		exporter = tracetest.NewInMemoryExporter()
 
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-		sdktrace.WithResource(resource.Default()),
-		sdktrace.WithIDGenerator(&staticIDGenerator{}),
-		sdktrace.WithSpanProcessor(
-			// To process span immediately
-			sdktrace.NewSimpleSpanProcessor(exp),
-		),
-	)
+		provider = sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(sdktrace.AlwaysSample()),
+			sdktrace.WithResource(resource.Default()),
+			sdktrace.WithIDGenerator(&staticIDGenerator{}),
+			sdktrace.WithSpanProcessor(
+				// To process span immediately
+				sdktrace.NewSimpleSpanProcessor(exporter),
+			),
+		)
+	})
+	exporter.Reset()
 
-	otel.SetTracerProvider(tp) // Override tracer provider
+	otel.SetTracerProvider(provider) // Override tracer provider
 	otel.SetTextMapPropagator(propagation.TraceContext{})
 
 	return TracerProviderMock{
-		spanExporter: exp,
+		spanExporter: exporter,
 	}
 }
@@ -25,6 +25,17 @@ func (tp TracerProviderMock) GetLatestSpan() SpanStub {
 	return SpanStub(spans[len(spans)-1])
 }
 
+// GetSpans returns all ended spans in order
+func (tp TracerProviderMock) GetSpans() []SpanStub {
+	spans := tp.spanExporter.GetSpans()
+	rs := make([]SpanStub, len(spans))
+	for idx, span := range spans {
+		rs[idx] = SpanStub(span)
+	}
+
+	return rs
+}
+
 func (tp TracerProviderMock) Reset() {
 	tp.spanExporter.Reset()
 }
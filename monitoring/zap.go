@@ -10,12 +10,16 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-func newZapCore(w io.Writer) zapcore.Core {
-	return zapcore.NewCore(
-		zapcore.NewJSONEncoder(newEncoderConfig()),
-		zapcore.AddSync(w),
-		zapcore.InfoLevel,
-	)
+func newZapCore(w io.Writer, levels *levelController) zapcore.Core {
+	return leveledCore{
+		// The lowest level is enabled here, the levelController decides which entries are written
+		Core: zapcore.NewCore(
+			zapcore.NewJSONEncoder(newEncoderConfig()),
+			zapcore.AddSync(w),
+			zapcore.DebugLevel,
+		),
+		levels: levels,
+	}
 }
 
 func newEncoderConfig() zapcore.EncoderConfig {
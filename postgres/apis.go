@@ -58,3 +58,15 @@ func NewPool(
 
 	return pool, nil
 }
+
+// Ping verifies the DB is still reachable, e.g. to be used as a health check
+func Ping(ctx context.Context, db BeginnerExecutor) error {
+	if pinger, ok := db.(interface {
+		PingContext(ctx context.Context) error
+	}); ok {
+		return pkgerrors.WithStack(pinger.PingContext(ctx))
+	}
+
+	_, err := db.ExecContext(ctx, "SELECT 1")
+	return pkgerrors.WithStack(err)
+}
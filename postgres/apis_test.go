@@ -1,5 +1,13 @@
 package postgres
 
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
 //func TestNewPool(t *testing.T) {
 //	ctx := context.Background()
 //	m, err := monitoring.New(monitoring.Config{})
@@ -16,3 +24,35 @@ package postgres
 //	require.NoError(t, err)
 //	require.NotNil(t, pool)
 //}
+
+func TestPing(t *testing.T) {
+	tcs := map[string]struct {
+		givenErr error
+		expErr   error
+	}{
+		"success": {},
+		"error": {
+			givenErr: errors.New("connection refused"),
+			expErr:   errors.New("connection refused"),
+		},
+	}
+
+	for scenario, tc := range tcs {
+		t.Run(scenario, func(t *testing.T) {
+			// Given
+			ctx := context.Background()
+			db := NewMockBeginnerExecutor(t)
+			db.On("ExecContext", ctx, "SELECT 1").Return(nil, tc.givenErr)
+
+			// When
+			err := Ping(ctx, db)
+
+			// Then
+			if tc.expErr != nil {
+				require.EqualError(t, err, tc.expErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ type Server struct {
 	certFile      string
 	keyFile       string
 	shutdownGrace time.Duration
+	admin         *AdminServer
 }
 
 // NewHttpServer creates new http server
@@ -59,9 +60,21 @@ func (srv *Server) Run() error {
 }
 
 // RunWithContext starts http server and manages its lifecycle using given context
+// The admin server given by ServerWithAdmin is started and stopped along with it
 func (srv *Server) RunWithContext(ctx context.Context) error {
 	startupErr := make(chan error)
 
+	// Start admin server
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	adminStopped := make(chan error, 1)
+	if srv.admin != nil {
+		go func() {
+			adminStopped <- srv.admin.RunWithContext(ctx)
+		}()
+	}
+
 	// Start server
 	go func() {
 		fmt.Printf("web server started; listening at %s\n", srv.httpServer.Addr)
@@ -87,8 +100,20 @@ func (srv *Server) RunWithContext(ctx context.Context) error {
 			return pkgerrors.Wrap(err, "http server stopped")
 		}
 		return nil
+	case err := <-adminStopped:
+		// Admin server only stops by itself when it failed to start
+		if stopErr := srv.stop(); stopErr != nil {
+			return stopErr
+		}
+		return pkgerrors.Wrap(err, "admin server stopped")
 	case <-ctx.Done():
-		return srv.stop()
+		err := srv.stop()
+		if srv.admin != nil {
+			if adminErr := <-adminStopped; err == nil {
+				err = adminErr
+			}
+		}
+		return err
 	}
 }
 
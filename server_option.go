@@ -26,3 +26,10 @@ func ServerWriteTimeout(duration time.Duration) ServerOption {
 		s.httpServer.WriteTimeout = duration
 	}
 }
+
+// ServerWithAdmin runs the given AdminServer along with the server
+func ServerWithAdmin(admin *AdminServer) ServerOption {
+	return func(s *Server) {
+		s.admin = admin
+	}
+}